@@ -1,15 +1,25 @@
 package bootstrap
 
 import (
+	"context"
 	"github.com/patriciabonaldy/cash_register/api/cmd/bootstrap/handler"
 	"log"
+	"os"
+	"time"
 
 	"github.com/patriciabonaldy/cash_register/internal/cashRegister"
+	catalog "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
 	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
 )
 
 const (
 	port = 8080
+	// initialStock are the units of each product the memory inventory starts with.
+	initialStock = 100
+	// releaseInterval is how often the expired reservations are released
+	// and the abandoned baskets expired.
+	releaseInterval = time.Minute
 )
 
 // Run application
@@ -19,9 +29,54 @@ func Run() error {
 		log.Fatal(err)
 	}
 
+	err = cashRegister.LoadExchangeRates(os.Getenv("EXCHANGE_RATES_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = cashRegister.LoadManagers(os.Getenv("MANAGERS_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	repository := memory.NewRepository()
-	service := cashRegister.NewService(cashRegister.RulesEngine, repository)
+	customers := memory.NewCustomerRepository()
+	priceLists := memory.NewPriceListRepository()
+	markdowns := memory.NewMarkdownRepository()
+	products := catalog.NewProductRepository(
+		models.ProductMap[models.Voucher],
+		models.ProductMap[models.Tshirt],
+		models.ProductMap[models.Pants],
+	)
+	inventory := memory.NewInventoryRepository(
+		models.Stock{ProductCode: models.Voucher, OnHand: initialStock},
+		models.Stock{ProductCode: models.Tshirt, OnHand: initialStock},
+		models.Stock{ProductCode: models.Pants, OnHand: initialStock},
+	)
+	service := cashRegister.NewService(cashRegister.RulesEngine, repository,
+		cashRegister.WithCustomers(customers),
+		cashRegister.WithPriceLists(priceLists),
+		cashRegister.WithMarkdowns(markdowns),
+		cashRegister.WithCatalog(products),
+		cashRegister.WithInventory(inventory),
+	)
+	go releaseReservations(service)
+
 	handler := handler.New(service)
 	srv := New(port, handler)
 	return srv.Run()
 }
+
+// releaseReservations gives back the stock held by the abandoned baskets,
+// and expires the ones without scans for too long.
+func releaseReservations(service cashRegister.Service) {
+	for now := range time.Tick(releaseInterval) {
+		if _, err := service.ReleaseExpiredReservations(context.Background(), now); err != nil {
+			log.Println("releasing expired reservations:", err)
+		}
+
+		if _, err := service.ExpireBaskets(context.Background(), now); err != nil {
+			log.Println("expiring baskets:", err)
+		}
+	}
+}
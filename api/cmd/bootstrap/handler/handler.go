@@ -1,15 +1,23 @@
 package handler
 
 import (
+	"bytes"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/patriciabonaldy/cash_register/internal/models"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/patriciabonaldy/cash_register/internal/cashRegister"
+	"github.com/patriciabonaldy/cash_register/internal/catalog"
 )
 
+// dateLayout is the layout of the days in the query of the reports.
+const dateLayout = "2006-01-02"
+
 type Handler struct {
 	service cashRegister.Service
 }
@@ -84,12 +92,14 @@ func (h *Handler) GetBasketHandler() gin.HandlerFunc {
 // RemoveBasketHandler godoc
 // @Summary      remove a basket
 // @Description  requires a basket ID example:"0bfce8da-bdc9-11ec-b9f3-acde48001122"
+// @Description  only open baskets are removed, and removing one with lines is a void.
 // @Tags         basket
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "ID"
 // @Success      200
 // @Failure      400
+// @Failure      403  {string}  string  "manager approval required: void"
 // @Failure      500
 // @Router       /baskets/{id} [DELETE]
 func (h *Handler) RemoveBasketHandler() gin.HandlerFunc {
@@ -102,7 +112,8 @@ func (h *Handler) RemoveBasketHandler() gin.HandlerFunc {
 
 		err := h.service.RemoveBasket(ctx, id)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, err.Error())
+			ctx.JSON(errorStatus(err), err.Error())
+			return
 		}
 
 		ctx.Status(http.StatusOK)
@@ -122,6 +133,7 @@ func (h *Handler) RemoveBasketHandler() gin.HandlerFunc {
 // @Param        id   path      string  true  "ID"
 // @Success      200  {object}  Response
 // @Failure      400  {object}  Response
+// @Failure      409  {object}  Response
 // @Failure      500  {object}  Response
 // @Router       /baskets/{id}/checkout [post]
 func (h *Handler) CheckoutBasketHandler() gin.HandlerFunc {
@@ -134,7 +146,110 @@ func (h *Handler) CheckoutBasketHandler() gin.HandlerFunc {
 
 		basket, err := h.service.CheckoutBasket(ctx, id)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, err.Error())
+			ctx.JSON(errorStatus(err), err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// PayBasketHandler mark a checked out basket as paid.
+// require a basket id.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// PayBasketHandler godoc
+// @Summary      mark a basket as paid.
+// @Description  requires a basket id, the basket must be checked out.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "ID"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Failure      409  {string}  string  "basket state transition is not allowed: from open to paid"
+// @Router       /baskets/{id}/pay [post]
+func (h *Handler) PayBasketHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		if id == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.PayBasket(ctx, id)
+		if err != nil {
+			ctx.JSON(errorStatus(err), err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// VoidBasketHandler cancel a basket before it's paid.
+// require a basket id.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// VoidBasketHandler godoc
+// @Summary      void a basket.
+// @Description  cancels an open or checked out basket and puts back its stock. It's a void when
+// @Description  the basket has lines, it can require the approval of a manager.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "ID"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Failure      403  {string}  string  "manager approval required: void"
+// @Failure      409  {string}  string  "basket state transition is not allowed: from open to paid"
+// @Router       /baskets/{id}/void [post]
+func (h *Handler) VoidBasketHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		if id == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.VoidBasket(ctx, id)
+		if err != nil {
+			ctx.JSON(errorStatus(err), err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// RefundBasketHandler give back the payment of a paid basket.
+// require a basket id.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// RefundBasketHandler godoc
+// @Summary      refund a basket.
+// @Description  refunds a paid basket and puts back its stock. Refunds over the large refund amount
+// @Description  require the approval of a manager.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "ID"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Failure      403  {string}  string  "manager approval required: refund"
+// @Failure      409  {string}  string  "basket state transition is not allowed: from open to paid"
+// @Router       /baskets/{id}/refund [post]
+func (h *Handler) RefundBasketHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		if id == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.RefundBasket(ctx, id)
+		if err != nil {
+			ctx.JSON(errorStatus(err), err.Error())
 			return
 		}
 
@@ -147,14 +262,21 @@ func (h *Handler) CheckoutBasketHandler() gin.HandlerFunc {
 // Otherwise, it will return 500
 // AddProductHandler godoc
 // @Summary      add a new product to basket.
-// @Description  requires a basket id, and a product code. if product/code not exists then return "product does not exist"
+// @Description  requires a basket id, and a product code or an EAN-13/UPC-A barcode. if product/code not exists then return "product does not exist",
+// @Description  a barcode with a wrong check digit returns 400 and one of no product returns 404,
+// @Description  a product without enough stock returns 409
+// @Description  a product over its purchase limit, or a total over the limit without approval, returns 422
 // @Tags         basket
 // @Accept       json
 // @Produce      plain
 // @Param        id     path      string  true  "ID"
 // @Param        code   path      string  true  "CODE"
+// @Param        weight query     string  false "weight in kg of a weighed product, like 1.250"
 // @Success      200  {object}  Response
 // @Failure      400  {object}  Response
+// @Failure      404  {object}  Response
+// @Failure      409  {object}  Response
+// @Failure      422  {object}  Response
 // @Failure      500  {object}  Response
 // @Router       /baskets/{id}/products/{code} [post]
 func (h *Handler) AddProductHandler() gin.HandlerFunc {
@@ -174,9 +296,23 @@ func (h *Handler) AddProductHandler() gin.HandlerFunc {
 			BasketID:    id,
 			ProductCode: code,
 		}
-		_, err := h.service.AddProduct(ctx, req.BasketID, req.ProductCode)
+
+		var err error
+		if w := ctx.Query("weight"); w != "" {
+			var weight models.Weight
+			weight, err = models.ParseWeight(w)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, err.Error())
+				return
+			}
+
+			_, err = h.service.AddWeighedProduct(ctx, req.BasketID, req.ProductCode, weight)
+		} else {
+			_, err = h.service.AddProduct(ctx, req.BasketID, req.ProductCode)
+		}
+
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, err.Error())
+			ctx.JSON(errorStatus(err), err.Error())
 			return
 		}
 
@@ -190,7 +326,8 @@ func (h *Handler) AddProductHandler() gin.HandlerFunc {
 // otherwise will return 400
 // RemoveProductHandler godoc
 // @Summary      remove a product in the basket.
-// @Description  requires a basket id, and a product code. if product/code not exists then return "product does not exist"
+// @Description  requires a basket id, and a product code or an EAN-13/UPC-A barcode. if product/code not exists then return "product does not exist",
+// @Description  a barcode with a wrong check digit returns 400 and one of no product returns 404
 // @Tags         basket
 // @Accept       json
 // @Produce      plain
@@ -198,6 +335,7 @@ func (h *Handler) AddProductHandler() gin.HandlerFunc {
 // @Param        code   path      string  true  "CODE"
 // @Success      200
 // @Failure      400
+// @Failure      403  {string}  string  "manager approval required: void"
 // @Failure      500
 // @Router       /baskets/{id}/products/{code} [delete]
 func (h *Handler) RemoveProductHandler() gin.HandlerFunc {
@@ -218,6 +356,154 @@ func (h *Handler) RemoveProductHandler() gin.HandlerFunc {
 			ProductCode: code,
 		}
 		basket, err := h.service.RemoveProduct(ctx, req.BasketID, req.ProductCode)
+		if err != nil {
+			ctx.JSON(errorStatus(err), err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// SetQuantityHandler set the units of a product inside basket.
+// require a basket id, product code and the quantity.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// SetQuantityHandler godoc
+// @Summary      set the quantity of a product in the basket.
+// @Description  adds units, or removes the last units added, and zero removes the line. Removing units is a void,
+// @Description  it can require the approval of a manager. Weighed products can only be set to zero.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string           true  "ID"
+// @Param        code      path      string           true  "CODE"
+// @Param        quantity  body      QuantityRequest  true  "quantity"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Failure      403  {string}  string  "manager approval required: void"
+// @Failure      409
+// @Failure      422
+// @Router       /baskets/{id}/products/{code} [put]
+func (h *Handler) SetQuantityHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		code := ctx.Param("code")
+		if id == "" || code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req QuantityRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		basket, err := h.service.SetQuantity(ctx, id, code, *req.Quantity)
+		if err != nil {
+			ctx.JSON(errorStatus(err), err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// RemoveUnitHandler remove the last unit added of a product inside basket.
+// require a basket id and product code.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// RemoveUnitHandler godoc
+// @Summary      remove a unit of a product in the basket.
+// @Description  removes the last unit added with its price, and the line with its last unit. It's a void,
+// @Description  it can require the approval of a manager.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string  true  "ID"
+// @Param        code   path      string  true  "CODE"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Failure      403  {string}  string  "manager approval required: void"
+// @Router       /baskets/{id}/products/{code}/unit [delete]
+func (h *Handler) RemoveUnitHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		code := ctx.Param("code")
+		if id == "" || code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.RemoveUnit(ctx, id, code)
+		if err != nil {
+			ctx.JSON(errorStatus(err), err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// UndoScanHandler undo the last scan of a basket.
+// require a basket id.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// UndoScanHandler godoc
+// @Summary      undo the last scan of a basket.
+// @Description  the units added by the last scan are removed, which is a void and can require the approval
+// @Description  of a manager, and the units removed are added again. The scan is kept marked as undone.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string  true  "ID"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Failure      403  {string}  string  "manager approval required: void"
+// @Router       /baskets/{id}/scans/undo [post]
+func (h *Handler) UndoScanHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		if id == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.UndoScan(ctx, id)
+		if err != nil {
+			ctx.JSON(errorStatus(err), err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// SetCashierHandler set the cashier at the till of a basket.
+// require a basket id and cashier id.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// SetCashierHandler godoc
+// @Summary      set the cashier of a basket.
+// @Description  requires a basket id and the cashier id the next scans are recorded with.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true  "ID"
+// @Param        cashierID  path      string  true  "CASHIER ID"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Router       /baskets/{id}/cashier/{cashierID} [put]
+func (h *Handler) SetCashierHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		cashierID := ctx.Param("cashierID")
+		if id == "" || cashierID == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.SetCashier(ctx, id, cashierID)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, err.Error())
 			return
@@ -227,24 +513,1729 @@ func (h *Handler) RemoveProductHandler() gin.HandlerFunc {
 	}
 }
 
-func toResponse(basket models.Basket) Response {
-	resp := Response{
-		ID:   basket.Code,
-		Item: []Item{},
+// AddProductsHandler add units of many products into basket at once.
+// require a basket id and the products with their quantities.
+// it will return 201 if this is ok.
+// otherwise will return 400
+// AddProductsHandler godoc
+// @Summary      add many products to the basket.
+// @Description  either all the products are added or none is, the errors are the ones of adding a product.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string              true  "ID"
+// @Param        products  body      AddProductsRequest  true  "products"
+// @Success      201  {object}  Response
+// @Failure      400
+// @Failure      403
+// @Failure      404
+// @Failure      409
+// @Failure      422
+// @Router       /baskets/{id}/products [post]
+func (h *Handler) AddProductsHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		if id == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req AddProductsRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		products := make([]models.ProductQuantity, 0, len(req.Products))
+		for _, p := range req.Products {
+			products = append(products, models.ProductQuantity{ProductCode: p.Code, Quantity: p.Quantity})
+		}
+
+		basket, err := h.service.AddProducts(ctx, id, products)
+		if err != nil {
+			ctx.JSON(errorStatus(err), err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, toResponse(basket))
 	}
+}
 
-	for _, v := range basket.Items {
-		item := Item{
-			Product: Product{
-				Code:  v.Product.Code,
-				Name:  v.Product.Name,
-				Price: v.Product.Price,
-			},
-			Quantity: v.Quantity,
-			Total:    v.Total,
+// OverrideProductHandler change manually the price of a product inside a basket.
+// require a basket id, product code and a reason code.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// OverrideProductHandler godoc
+// @Summary      override the price of a product in the basket.
+// @Description  requires one of unit_price, discount_amount or discount_percent and a reason code.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string           true  "ID"
+// @Param        code      path      string           true  "CODE"
+// @Param        override  body      OverrideRequest  true  "override"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Failure      403  {string}  string  "manager approval required: override"
+// @Router       /baskets/{id}/products/{code} [patch]
+func (h *Handler) OverrideProductHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		code := ctx.Param("code")
+		if id == "" || code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
 		}
-		resp.Item = append(resp.Item, item)
-		resp.Total += item.Total
+
+		var req OverrideRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		override, ok := req.toOverride()
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, models.ErrInvalidOverride.Error())
+			return
+		}
+
+		basket, err := h.service.OverrideItem(ctx, id, code, override)
+		if err != nil {
+			ctx.JSON(errorStatus(err), err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// ApproveHandler record the approval of a manager for a restricted operation.
+// require a basket id, the operation and the credentials of the manager.
+// it will return 201 if this is ok.
+// otherwise will return 400, or 401 when the credentials are wrong
+// ApproveHandler godoc
+// @Summary      approve a restricted operation on a basket.
+// @Description  overrides, voids and large refunds require the approval of a manager before doing them.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string           true  "ID"
+// @Param        approval  body      ApprovalRequest  true  "approval"
+// @Success      201  {object}  Response
+// @Failure      400
+// @Failure      401
+// @Failure      429  {string}  string  "manager is locked out after too many wrong pins"
+// @Router       /baskets/{id}/approvals [post]
+func (h *Handler) ApproveHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		if id == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req ApprovalRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		basket, err := h.service.Approve(ctx, id, req.Operation, req.ManagerID, req.Pin)
+		if err != nil {
+			ctx.JSON(errorStatus(err), err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, toResponse(basket))
+	}
+}
+
+// CreateCustomerHandler create a loyalty account.
+// return 201 if this could be created.
+// Otherwise, it will return 400
+// CreateCustomerHandler godoc
+// @Summary      Create a new loyalty account.
+// @Description  requires a name and optionally a tier (standard, silver, gold).
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        customer  body      CustomerRequest  true  "customer"
+// @Success      201  {object}  CustomerResponse
+// @Failure      400
+// @Router       /customers [post]
+func (h *Handler) CreateCustomerHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CustomerRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		customer, err := h.service.CreateCustomer(ctx, req.Name, req.Tier)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, toCustomerResponse(customer))
+	}
+}
+
+// GetCustomerHandler return a loyalty account.
+// require a customer id and
+// return 200 if this is ok.
+// otherwise will return 400
+// GetCustomerHandler godoc
+// @Summary      Show a loyalty account and its points balance
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "ID"
+// @Success      200  {object}  CustomerResponse
+// @Failure      400
+// @Router       /customers/{id} [get]
+func (h *Handler) GetCustomerHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		if id == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		customer, err := h.service.GetCustomer(ctx, id)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toCustomerResponse(customer))
+	}
+}
+
+// AttachCustomerHandler link a loyalty account to a basket.
+// require a basket id and customer id.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// AttachCustomerHandler godoc
+// @Summary      attach a customer to a basket.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string  true  "ID"
+// @Param        customerID  path      string  true  "CUSTOMER ID"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Router       /baskets/{id}/customer/{customerID} [put]
+func (h *Handler) AttachCustomerHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		customerID := ctx.Param("customerID")
+		if id == "" || customerID == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.AttachCustomer(ctx, id, customerID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// RedeemPointsHandler use points of the customer as a discount.
+// require a basket id and the points.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// RedeemPointsHandler godoc
+// @Summary      redeem loyalty points on a basket.
+// @Description  requires a basket with a customer attached, zero points cancels the redemption.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string         true  "ID"
+// @Param        points  body      PointsRequest  true  "points"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Router       /baskets/{id}/points [post]
+func (h *Handler) RedeemPointsHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		if id == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req PointsRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		basket, err := h.service.RedeemPoints(ctx, id, req.Points)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// SetEmployeeHandler mark a basket as a staff purchase.
+// require a basket id and employee id.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// SetEmployeeHandler godoc
+// @Summary      apply the employee discount to a basket.
+// @Description  requires a basket id and the employee id the purchase is attributed to.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string  true  "ID"
+// @Param        employeeID  path      string  true  "EMPLOYEE ID"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Router       /baskets/{id}/employee/{employeeID} [put]
+func (h *Handler) SetEmployeeHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		employeeID := ctx.Param("employeeID")
+		if id == "" || employeeID == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.SetEmployee(ctx, id, employeeID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// SetPaymentCurrencyHandler set the currency a basket is paid in.
+// require a basket id and currency code.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// SetPaymentCurrencyHandler godoc
+// @Summary      pay a basket in another currency.
+// @Description  requires a basket id and the currency code, the total is converted at checkout.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string  true  "ID"
+// @Param        currency  path      string  true  "CURRENCY"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Router       /baskets/{id}/currency/{currency} [put]
+func (h *Handler) SetPaymentCurrencyHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		currency := ctx.Param("currency")
+		if id == "" || currency == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.SetPaymentCurrency(ctx, id, currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// SetTenderHandler set how a basket is paid.
+// require a basket id and tender.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// SetTenderHandler godoc
+// @Summary      set how a basket is paid.
+// @Description  requires a basket id and the tender, cash or card. Cash payments may be rounded at checkout.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string  true  "ID"
+// @Param        tender  path      string  true  "TENDER"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Router       /baskets/{id}/tender/{tender} [put]
+func (h *Handler) SetTenderHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		tender := ctx.Param("tender")
+		if id == "" || tender == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.SetTender(ctx, id, tender)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// StaffPurchasesHandler return the staff purchases per employee.
+// StaffPurchasesHandler godoc
+// @Summary      staff purchases per employee
+// @Description  checked out baskets with employee discount, for payroll deduction.
+// @Tags         report
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}  StaffPurchasesResponse
+// @Failure      500
+// @Router       /reports/staff-purchases [get]
+func (h *Handler) StaffPurchasesHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		report, err := h.service.StaffPurchases(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		resp := []StaffPurchasesResponse{}
+		for _, p := range report {
+			resp = append(resp, StaffPurchasesResponse{
+				EmployeeID: p.EmployeeID,
+				Baskets:    p.Baskets,
+				Gross:      p.Gross,
+				Discount:   p.Discount,
+				Total:      p.Total,
+				Rounding:   p.Rounding,
+				Tendered:   p.Tendered,
+			})
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+// ProductDiscountsHandler return the markdowns and the discounts of the promotions per product.
+// ProductDiscountsHandler godoc
+// @Summary      markdowns and promotional discounts per product
+// @Description  units sold in the checked out baskets, with the clearance markdowns apart from the promotions.
+// @Tags         report
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}  ProductDiscountsResponse
+// @Failure      500
+// @Router       /reports/product-discounts [get]
+func (h *Handler) ProductDiscountsHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		report, err := h.service.ProductDiscounts(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		resp := []ProductDiscountsResponse{}
+		for _, p := range report {
+			resp = append(resp, ProductDiscountsResponse{
+				ProductCode:       p.ProductCode,
+				Units:             p.Units,
+				Markdown:          p.Markdown,
+				PromotionDiscount: p.PromotionDiscount,
+				Total:             p.Total,
+			})
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+// SaveMarkdownHandler create or replace a markdown policy.
+// require a markdown name, the products or categories and the steps.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// SaveMarkdownHandler godoc
+// @Summary      create or replace a markdown policy.
+// @Description  clearance markdown in steps, like -20% after 60 days and -50% after 90, from the start.
+// @Tags         markdown
+// @Accept       json
+// @Produce      json
+// @Param        name       path      string           true  "NAME"
+// @Param        markdown   body      MarkdownRequest  true  "markdown"
+// @Success      200  {object}  MarkdownResponse
+// @Failure      400
+// @Router       /markdowns/{name} [put]
+func (h *Handler) SaveMarkdownHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		name := ctx.Param("name")
+		if name == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req MarkdownRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		markdown := models.Markdown{
+			Name:       name,
+			Products:   req.Products,
+			Categories: req.Categories,
+			Start:      req.Start,
+		}
+		for _, step := range req.Steps {
+			markdown.Steps = append(markdown.Steps, models.MarkdownStep{AfterDays: step.AfterDays, Percent: step.Percent})
+		}
+
+		markdown, err := h.service.SaveMarkdown(ctx, markdown)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toMarkdownResponse(markdown))
+	}
+}
+
+// GetMarkdownHandler return a markdown policy.
+// GetMarkdownHandler godoc
+// @Summary      show a markdown policy
+// @Tags         markdown
+// @Accept       json
+// @Produce      json
+// @Param        name   path      string  true  "NAME"
+// @Success      200  {object}  MarkdownResponse
+// @Failure      400
+// @Router       /markdowns/{name} [get]
+func (h *Handler) GetMarkdownHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		name := ctx.Param("name")
+		if name == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		markdown, err := h.service.GetMarkdown(ctx, name)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toMarkdownResponse(markdown))
+	}
+}
+
+func toMarkdownResponse(markdown models.Markdown) MarkdownResponse {
+	resp := MarkdownResponse{
+		Name:       markdown.Name,
+		Products:   markdown.Products,
+		Categories: markdown.Categories,
+		Start:      markdown.Start,
+		Steps:      []MarkdownStepRequest{},
+	}
+
+	for _, step := range markdown.Steps {
+		resp.Steps = append(resp.Steps, MarkdownStepRequest{AfterDays: step.AfterDays, Percent: step.Percent})
+	}
+
+	return resp
+}
+
+// SavePriceListHandler create or replace a price list.
+// require a price list name and the prices.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// SavePriceListHandler godoc
+// @Summary      create or replace a price list.
+// @Description  negotiated prices by product code, with optional validity dates.
+// @Tags         price-list
+// @Accept       json
+// @Produce      json
+// @Param        name       path      string            true  "NAME"
+// @Param        priceList  body      PriceListRequest  true  "price list"
+// @Success      200  {object}  PriceListResponse
+// @Failure      400
+// @Router       /price-lists/{name} [put]
+func (h *Handler) SavePriceListHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		name := ctx.Param("name")
+		if name == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req PriceListRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		priceList, err := h.service.SavePriceList(ctx, models.PriceList{
+			Name:              name,
+			Currency:          req.Currency,
+			Prices:            req.Prices,
+			ValidFrom:         req.ValidFrom,
+			ValidTo:           req.ValidTo,
+			DisablePromotions: req.DisablePromotions,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toPriceListResponse(priceList))
+	}
+}
+
+// GetPriceListHandler return a price list.
+// GetPriceListHandler godoc
+// @Summary      show a price list
+// @Tags         price-list
+// @Accept       json
+// @Produce      json
+// @Param        name   path      string  true  "NAME"
+// @Success      200  {object}  PriceListResponse
+// @Failure      400
+// @Router       /price-lists/{name} [get]
+func (h *Handler) GetPriceListHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		name := ctx.Param("name")
+		if name == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		priceList, err := h.service.GetPriceList(ctx, name)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toPriceListResponse(priceList))
+	}
+}
+
+// AssignBasketPriceListHandler set the price list of a basket.
+// AssignBasketPriceListHandler godoc
+// @Summary      price the new lines of a basket with a price list.
+// @Tags         basket
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true  "ID"
+// @Param        name  path      string  true  "PRICE LIST"
+// @Success      200  {object}  Response
+// @Failure      400
+// @Router       /baskets/{id}/price-list/{name} [put]
+func (h *Handler) AssignBasketPriceListHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		name := ctx.Param("name")
+		if id == "" || name == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		basket, err := h.service.AssignBasketPriceList(ctx, id, name)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponse(basket))
+	}
+}
+
+// AssignCustomerPriceListHandler set the price list of a customer.
+// AssignCustomerPriceListHandler godoc
+// @Summary      assign a negotiated price list to a customer.
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true  "ID"
+// @Param        name  path      string  true  "PRICE LIST"
+// @Success      200  {object}  CustomerResponse
+// @Failure      400
+// @Router       /customers/{id}/price-list/{name} [put]
+func (h *Handler) AssignCustomerPriceListHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		name := ctx.Param("name")
+		if id == "" || name == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		customer, err := h.service.AssignCustomerPriceList(ctx, id, name)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toCustomerResponse(customer))
+	}
+}
+
+// ExperimentResultsHandler return the results of an experiment.
+// require an experiment name.
+// it will return 200 if this is ok.
+// otherwise will return 400
+// ExperimentResultsHandler godoc
+// @Summary      results of an A/B experiment
+// @Description  requires an experiment name, return conversion and revenue per variant.
+// @Tags         experiment
+// @Accept       json
+// @Produce      json
+// @Param        name   path      string  true  "NAME"
+// @Success      200  {object}  ExperimentResponse
+// @Failure      400
+// @Failure      500
+// @Router       /experiments/{name}/results [get]
+func (h *Handler) ExperimentResultsHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		name := ctx.Param("name")
+		if name == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		result, err := h.service.ExperimentResults(ctx, name)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		resp := ExperimentResponse{
+			Experiment: result.Experiment,
+			Rule:       result.Rule,
+			Variants:   []VariantResponse{},
+		}
+		for _, v := range result.Variants {
+			resp.Variants = append(resp.Variants, VariantResponse{
+				Variant:        v.Variant,
+				Baskets:        v.Baskets,
+				CheckedOut:     v.CheckedOut,
+				ConversionRate: v.ConversionRate,
+				Revenue:        v.Revenue,
+				AverageBasket:  v.AverageBasket,
+				Rounding:       v.Rounding,
+				Tendered:       v.Tendered,
+			})
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+func toResponse(basket models.Basket) Response {
+	resp := Response{
+		ID:             basket.Code,
+		State:          basket.CurrentState(),
+		Item:           []Item{},
+		Variants:       basket.Variants,
+		CustomerID:     basket.CustomerID,
+		PointsRedeemed: basket.PointsRedeemed,
+		PointsDiscount: basket.PointsDiscount,
+		PointsEarned:   basket.PointsEarned,
+		EmployeeID:     basket.EmployeeID,
+		Cashier:        basket.Cashier,
+		// the tax of the items is only added when prices don't include it
+		PricesIncludeTax:       basket.PricesIncludeTax,
+		Tax:                    basket.Tax,
+		Taxes:                  []TaxResponse{},
+		Currency:               basket.Currency,
+		PaymentCurrency:        basket.PaymentCurrency,
+		ExchangeRate:           basket.ExchangeRate.String(),
+		PaymentTotal:           basket.PaymentTotal,
+		Tender:                 basket.Tender,
+		RoundingAdjustment:     basket.RoundingAdjustment,
+		BaseRoundingAdjustment: basket.BaseRoundingAdjustment,
+		Markdown:               basket.Markdown,
+		PromotionDiscount:      basket.PromotionDiscount,
+	}
+	if !basket.CheckedOutAt.IsZero() {
+		resp.CheckedOutAt = &basket.CheckedOutAt
+	}
+
+	for _, t := range basket.Transitions {
+		resp.Transitions = append(resp.Transitions, TransitionResponse{From: t.From, To: t.To, At: t.At})
+	}
+
+	for _, t := range basket.Taxes {
+		resp.Taxes = append(resp.Taxes, TaxResponse{
+			Category: t.Category,
+			Rate:     t.Rate,
+			Net:      t.Net,
+			Tax:      t.Tax,
+			Gross:    t.Gross,
+		})
+	}
+
+	for _, a := range basket.Approvals {
+		approval := ApprovalResponse{
+			Operation:  a.Operation,
+			ManagerID:  a.ManagerID,
+			ApprovedAt: a.ApprovedAt,
+		}
+		if !a.UsedAt.IsZero() {
+			usedAt := a.UsedAt
+			approval.UsedAt = &usedAt
+		}
+		resp.Approvals = append(resp.Approvals, approval)
+	}
+
+	for _, scan := range basket.Scans {
+		scanResponse := ScanResponse{
+			ProductCode: scan.ProductCode,
+			Quantity:    scan.Quantity,
+			Cashier:     scan.Cashier,
+			ScannedAt:   scan.ScannedAt,
+		}
+		if scan.IsUndone() {
+			undoneAt := scan.UndoneAt
+			scanResponse.UndoneAt = &undoneAt
+		}
+		resp.Scans = append(resp.Scans, scanResponse)
+	}
+
+	// the lines are listed in the order they were scanned
+	for _, v := range basket.OrderedItems() {
+		item := Item{
+			Product: Product{
+				Code:     v.Product.Code,
+				Name:     v.Product.Name,
+				Price:    v.Product.Price,
+				Currency: v.Product.Currency,
+				Unit:     v.Product.Unit,
+				Parent:   v.Product.Parent,
+				Size:     v.Product.Size,
+				Colour:   v.Product.Colour,
+			},
+			Quantity:          v.Quantity,
+			Total:             v.Total,
+			EmployeeDiscount:  v.EmployeeDiscount,
+			PriceList:         v.PriceList,
+			ManualDiscount:    v.ManualDiscount,
+			PointsDiscount:    v.PointsDiscount,
+			TaxCategory:       v.TaxCategory(),
+			TaxRate:           v.TaxRate,
+			Tax:               v.Tax,
+			Weight:            v.Weight,
+			PrintedAmount:     v.PrintedAmount,
+			ScanAmounts:       v.ScanAmounts,
+			UnitPrices:        v.UnitPrices,
+			Markdown:          v.MarkdownAmount(),
+			MarkdownPercent:   v.MarkdownPercent,
+			PromotionDiscount: v.PromotionDiscount,
+		}
+		for _, a := range v.Allocations {
+			item.Allocations = append(item.Allocations, AllocationResponse{
+				ProductCode: a.ProductCode,
+				Quantity:    a.Quantity,
+				Revenue:     a.Revenue,
+				Tax:         a.Tax,
+			})
+		}
+		if v.Override != nil {
+			item.Override = &OverrideResponse{
+				Type:    v.Override.Type,
+				Amount:  v.Override.Amount,
+				Percent: v.Override.Percent,
+				Reason:  v.Override.Reason,
+			}
+		}
+		resp.Item = append(resp.Item, item)
+		resp.EmployeeDiscount += item.EmployeeDiscount
+		resp.Total += item.Total
+	}
+
+	if !basket.PricesIncludeTax {
+		resp.Total += basket.Tax
+	}
+
+	resp.Total -= basket.PointsDiscount
+	return resp
+}
+
+func toCustomerResponse(customer models.Customer) CustomerResponse {
+	return CustomerResponse{
+		ID:        customer.ID,
+		Name:      customer.Name,
+		Tier:      customer.Tier,
+		Points:    customer.Points,
+		PriceList: customer.PriceList,
+	}
+}
+
+func toPriceListResponse(priceList models.PriceList) PriceListResponse {
+	return PriceListResponse{
+		Name:              priceList.Name,
+		Currency:          priceList.Currency,
+		Prices:            priceList.Prices,
+		ValidFrom:         priceList.ValidFrom,
+		ValidTo:           priceList.ValidTo,
+		DisablePromotions: priceList.DisablePromotions,
+	}
+}
+
+// toOverride returns the override of the request,
+// exactly one of the unit price or the discounts must be set.
+func (r OverrideRequest) toOverride() (models.Override, bool) {
+	overrides := []models.Override{}
+	if r.UnitPrice != nil {
+		overrides = append(overrides, models.Override{Type: models.OverrideUnitPrice, Amount: *r.UnitPrice})
+	}
+
+	if r.DiscountAmount != nil {
+		overrides = append(overrides, models.Override{Type: models.OverrideDiscountAmount, Amount: *r.DiscountAmount})
+	}
+
+	if r.DiscountPercent != nil {
+		overrides = append(overrides, models.Override{Type: models.OverrideDiscountPercent, Percent: *r.DiscountPercent})
+	}
+
+	if len(overrides) != 1 {
+		return models.Override{}, false
+	}
+
+	override := overrides[0]
+	override.Reason = r.Reason
+
+	return override, true
+}
+
+// errorStatus maps the errors of the service that are not a bad request.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrApprovalRequired):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrInvalidManagerCredential):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrManagerLockedOut):
+		return http.StatusTooManyRequests
+	case errors.Is(err, models.ErrUnknownBarcode):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInsufficientStock):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrPurchaseLimit):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, models.ErrInvalidTransition), errors.Is(err, models.ErrBasketChanged):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// CreateProductHandler add a new product to the catalog.
+// return 201 if this could be created.
+// Otherwise, it will return 400
+// CreateProductHandler godoc
+// @Summary      Create a new product in the catalog.
+// @Description  requires a code, name and price, the product is created active.
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        product  body      CatalogProductRequest  true  "product"
+// @Success      201  {object}  ProductResponse
+// @Failure      400
+// @Router       /products [post]
+func (h *Handler) CreateProductHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CatalogProductRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		product, err := h.service.CreateProduct(ctx, req.toProduct(req.Code))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, toProductResponse(product))
+	}
+}
+
+// ListProductsHandler return the products of the catalog.
+// ListProductsHandler godoc
+// @Summary      List the products of the catalog
+// @Description  deactivated products are only listed with all=true.
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        all  query     bool  false  "include deactivated products"
+// @Success      200  {array}   ProductResponse
+// @Failure      400
+// @Router       /products [get]
+func (h *Handler) ListProductsHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		products, err := h.service.ListProducts(ctx, ctx.Query("all") == "true")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		resp := make([]ProductResponse, 0, len(products))
+		for _, product := range products {
+			resp = append(resp, toProductResponse(product))
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+// GetProductHandler return a product of the catalog.
+// GetProductHandler godoc
+// @Summary      Show a product of the catalog
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string  true  "CODE"
+// @Success      200  {object}  ProductResponse
+// @Failure      400
+// @Router       /products/{code} [get]
+func (h *Handler) GetProductHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		product, err := h.service.GetProduct(ctx, code)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toProductResponse(product))
+	}
+}
+
+// GetStockHandler return the stock of a product.
+// GetStockHandler godoc
+// @Summary      Show the stock of a product
+// @Description  the units on hand, the units held by the open baskets and the available ones.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string  true  "CODE"
+// @Success      200  {object}  StockResponse
+// @Failure      400
+// @Router       /inventory/{code} [get]
+func (h *Handler) GetStockHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		stock, err := h.service.GetStock(ctx, code)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, StockResponse{
+			ProductCode: stock.ProductCode,
+			OnHand:      stock.OnHand,
+			Reserved:    stock.Reserved,
+			Available:   stock.Available(),
+		})
+	}
+}
+
+// AdjustStockHandler record a change of the stock of a product out of the sales.
+// AdjustStockHandler godoc
+// @Summary      Adjust the stock of a product
+// @Description  the reason is receipt, damage or theft with the units received, damaged or stolen,
+// @Description  or count_correction with the units counted.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string             true  "CODE"
+// @Param        body   body      AdjustmentRequest  true  "adjustment"
+// @Success      201  {object}  MovementResponse
+// @Failure      400
+// @Router       /inventory/{code}/adjustments [post]
+func (h *Handler) AdjustStockHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req AdjustmentRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		movement, err := h.service.AdjustStock(ctx, code, req.Reason, req.Quantity, req.Note)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, toMovementResponse(movement))
+	}
+}
+
+// ListMovementsHandler return the movements of the stock of a product.
+// ListMovementsHandler godoc
+// @Summary      List the movements of the stock of a product
+// @Description  sales, reservations, adjustments and count corrections, oldest first.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string  true  "CODE"
+// @Success      200  {array}   MovementResponse
+// @Failure      400
+// @Router       /inventory/{code}/movements [get]
+func (h *Handler) ListMovementsHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		movements, err := h.service.ListMovements(ctx, code)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		resp := make([]MovementResponse, 0, len(movements))
+		for _, movement := range movements {
+			resp = append(resp, toMovementResponse(movement))
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+// StartCycleCountHandler open a physical count of some products.
+// StartCycleCountHandler godoc
+// @Summary      Start a cycle count
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        body   body      CycleCountRequest  true  "products to count"
+// @Success      201  {object}  CycleCountResponse
+// @Failure      400
+// @Router       /cycle-counts [post]
+func (h *Handler) StartCycleCountHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CycleCountRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		count, err := h.service.StartCycleCount(ctx, req.Products)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, toCycleCountResponse(count))
+	}
+}
+
+// GetCycleCountHandler return a cycle count.
+// GetCycleCountHandler godoc
+// @Summary      Show a cycle count and its variances
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "ID"
+// @Success      200  {object}  CycleCountResponse
+// @Failure      400
+// @Router       /cycle-counts/{id} [get]
+func (h *Handler) GetCycleCountHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		if id == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		count, err := h.service.GetCycleCount(ctx, id)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toCycleCountResponse(count))
+	}
+}
+
+// CountProductHandler record the units counted of a product of a cycle count.
+// CountProductHandler godoc
+// @Summary      Count a product of a cycle count
+// @Description  the units on hand when it's counted are the expected ones.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string        true  "ID"
+// @Param        code   path      string        true  "CODE"
+// @Param        body   body      CountRequest  true  "units counted"
+// @Success      200  {object}  CycleCountResponse
+// @Failure      400
+// @Router       /cycle-counts/{id}/products/{code} [put]
+func (h *Handler) CountProductHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		code := ctx.Param("code")
+		if id == "" || code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req CountRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		count, err := h.service.CountProduct(ctx, id, code, *req.Counted)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toCycleCountResponse(count))
+	}
+}
+
+// PostCycleCountHandler close a cycle count and post its variances.
+// PostCycleCountHandler godoc
+// @Summary      Post the variances of a cycle count
+// @Description  the variances of the products counted are posted as count corrections.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "ID"
+// @Success      200  {object}  CycleCountResponse
+// @Failure      400
+// @Router       /cycle-counts/{id}/post [post]
+func (h *Handler) PostCycleCountHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		if id == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		count, err := h.service.PostCycleCount(ctx, id)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toCycleCountResponse(count))
+	}
+}
+
+func toMovementResponse(movement models.StockMovement) MovementResponse {
+	return MovementResponse{
+		ProductCode:   movement.ProductCode,
+		Type:          movement.Type,
+		OnHandDelta:   movement.OnHandDelta,
+		ReservedDelta: movement.ReservedDelta,
+		OnHand:        movement.OnHand,
+		Reserved:      movement.Reserved,
+		BasketID:      movement.BasketID,
+		CycleCountID:  movement.CycleCountID,
+		Note:          movement.Note,
+		CreatedAt:     movement.CreatedAt,
+	}
+}
+
+func toCycleCountResponse(count models.CycleCount) CycleCountResponse {
+	resp := CycleCountResponse{
+		ID:        count.ID,
+		Status:    count.Status,
+		Lines:     make([]CountLineResponse, 0, len(count.Lines)),
+		CreatedAt: count.CreatedAt,
+	}
+
+	if !count.PostedAt.IsZero() {
+		resp.PostedAt = &count.PostedAt
+	}
+
+	for _, line := range count.Lines {
+		resp.Lines = append(resp.Lines, CountLineResponse{
+			ProductCode: line.ProductCode,
+			Expected:    line.Expected,
+			Counted:     line.Counted,
+			Variance:    line.Variance(),
+		})
+	}
+
+	return resp
+}
+
+// SchedulePriceChangeHandler set a new price of a product from a given time on.
+// SchedulePriceChangeHandler godoc
+// @Summary      Schedule a price change of a product
+// @Description  the price takes effect at effective_from, or now when it's empty. The lines of the baskets
+// @Description  keep the price in force when their units were added.
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string              true  "CODE"
+// @Param        body   body      PriceChangeRequest  true  "price change"
+// @Success      201  {object}  PriceChangeResponse
+// @Failure      400
+// @Router       /products/{code}/prices [post]
+func (h *Handler) SchedulePriceChangeHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req PriceChangeRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		change, err := h.service.SchedulePriceChange(ctx, models.PriceChange{
+			ProductCode:   code,
+			Price:         *req.Price,
+			Currency:      req.Currency,
+			EffectiveFrom: req.EffectiveFrom,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, toPriceChangeResponse(change))
+	}
+}
+
+// PriceHistoryHandler return the price changes of a product.
+// PriceHistoryHandler godoc
+// @Summary      Show the price history of a product
+// @Description  the past and the scheduled price changes, sorted by the time they take effect.
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string  true  "CODE"
+// @Success      200  {array}   PriceChangeResponse
+// @Failure      400
+// @Router       /products/{code}/prices [get]
+func (h *Handler) PriceHistoryHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		changes, err := h.service.PriceHistory(ctx, code)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		resp := make([]PriceChangeResponse, 0, len(changes))
+		for _, change := range changes {
+			resp = append(resp, toPriceChangeResponse(change))
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+func toPriceChangeResponse(change models.PriceChange) PriceChangeResponse {
+	return PriceChangeResponse{
+		ProductCode:   change.ProductCode,
+		Price:         change.Price,
+		Currency:      change.Currency,
+		EffectiveFrom: change.EffectiveFrom,
+		CreatedAt:     change.CreatedAt,
+	}
+}
+
+// ScheduleCostChangeHandler set a new cost price of a product from a given time on.
+// ScheduleCostChangeHandler godoc
+// @Summary      Schedule a cost change of a product
+// @Description  the cost takes effect at effective_from, or now when it's empty. The lines of the baskets
+// @Description  are costed at checkout with the cost in force.
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string             true  "CODE"
+// @Param        body   body      CostChangeRequest  true  "cost change"
+// @Success      201  {object}  CostChangeResponse
+// @Failure      400
+// @Router       /products/{code}/costs [post]
+func (h *Handler) ScheduleCostChangeHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req CostChangeRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		change, err := h.service.ScheduleCostChange(ctx, models.CostChange{
+			ProductCode:   code,
+			Cost:          *req.Cost,
+			EffectiveFrom: req.EffectiveFrom,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, toCostChangeResponse(change))
+	}
+}
+
+// CostHistoryHandler return the cost changes of a product.
+// CostHistoryHandler godoc
+// @Summary      Show the cost history of a product
+// @Description  the past and the scheduled cost changes, sorted by the time they take effect.
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string  true  "CODE"
+// @Success      200  {array}   CostChangeResponse
+// @Failure      400
+// @Router       /products/{code}/costs [get]
+func (h *Handler) CostHistoryHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		changes, err := h.service.CostHistory(ctx, code)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		resp := make([]CostChangeResponse, 0, len(changes))
+		for _, change := range changes {
+			resp = append(resp, toCostChangeResponse(change))
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+func toCostChangeResponse(change models.CostChange) CostChangeResponse {
+	return CostChangeResponse{
+		ProductCode:   change.ProductCode,
+		Cost:          change.Cost,
+		EffectiveFrom: change.EffectiveFrom,
+		CreatedAt:     change.CreatedAt,
+	}
+}
+
+// MarginsHandler return the gross margin of the checked out baskets.
+// MarginsHandler godoc
+// @Summary      gross margin per product, category or promotion
+// @Description  net line amounts and their cost in the baskets checked out from the first day to the last one,
+// @Description  both included. Without dates the range is open.
+// @Tags         report
+// @Accept       json
+// @Produce      json
+// @Param        by     query     string  false  "product, category or promotion"  default(product)
+// @Param        from   query     string  false  "first day, 2006-01-02"
+// @Param        to     query     string  false  "last day, 2006-01-02"
+// @Success      200  {array}  MarginResponse
+// @Failure      400
+// @Failure      500
+// @Router       /reports/margins [get]
+func (h *Handler) MarginsHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var from, to time.Time
+		var err error
+		if day := ctx.Query("from"); day != "" {
+			if from, err = time.ParseInLocation(dateLayout, day, time.Local); err != nil {
+				ctx.JSON(http.StatusBadRequest, models.ErrInvalidDateRange.Error())
+				return
+			}
+		}
+
+		if day := ctx.Query("to"); day != "" {
+			if to, err = time.ParseInLocation(dateLayout, day, time.Local); err != nil {
+				ctx.JSON(http.StatusBadRequest, models.ErrInvalidDateRange.Error())
+				return
+			}
+
+			to = to.AddDate(0, 0, 1)
+		}
+
+		report, err := h.service.Margins(ctx, ctx.DefaultQuery("by", models.MarginByProduct), from, to)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidMarginGroup) || errors.Is(err, models.ErrInvalidDateRange) {
+				ctx.JSON(http.StatusBadRequest, err.Error())
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		resp := []MarginResponse{}
+		for _, m := range report {
+			resp = append(resp, MarginResponse{
+				Key:           m.Key,
+				Units:         m.Units,
+				Revenue:       m.Revenue,
+				Cost:          m.Cost,
+				Margin:        m.Margin,
+				MarginPercent: m.Percent(),
+			})
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+// ListVariantsHandler return the variants of a product of the catalog.
+// ListVariantsHandler godoc
+// @Summary      List the variants of a product of the catalog
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string  true  "CODE"
+// @Success      200  {array}   ProductResponse
+// @Failure      400
+// @Router       /products/{code}/variants [get]
+func (h *Handler) ListVariantsHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		variants, err := h.service.ListVariants(ctx, code)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		resp := make([]ProductResponse, 0, len(variants))
+		for _, variant := range variants {
+			resp = append(resp, toProductResponse(variant))
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+// UpdateProductHandler replace the details of a product of the catalog.
+// UpdateProductHandler godoc
+// @Summary      Update a product of the catalog
+// @Description  the code of the body is ignored, a deactivated product is reactivated.
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        code     path      string                 true  "CODE"
+// @Param        product  body      CatalogProductRequest  true  "product"
+// @Success      200  {object}  ProductResponse
+// @Failure      400
+// @Router       /products/{code} [put]
+func (h *Handler) UpdateProductHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req CatalogProductRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return
+		}
+
+		product := req.toProduct(code)
+		product.Active = true
+		product, err := h.service.UpdateProduct(ctx, product)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toProductResponse(product))
+	}
+}
+
+// DeactivateProductHandler remove a product from sale, it's kept in the catalog.
+// DeactivateProductHandler godoc
+// @Summary      Deactivate a product of the catalog
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        code   path      string  true  "CODE"
+// @Success      200  {object}  ProductResponse
+// @Failure      400
+// @Router       /products/{code} [delete]
+func (h *Handler) DeactivateProductHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		code := ctx.Param("code")
+		if code == "" {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		product, err := h.service.DeactivateProduct(ctx, code)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toProductResponse(product))
+	}
+}
+
+func (r CatalogProductRequest) toProduct(code string) models.Product {
+	return models.Product{
+		Code:        code,
+		Name:        r.Name,
+		Price:       r.Price,
+		Currency:    r.Currency,
+		TaxCategory: r.TaxCategory,
+		Category:    r.Category,
+		Barcodes:    r.Barcodes,
+		Unit:        r.Unit,
+		PLU:         r.PLU,
+		Parent:      r.Parent,
+		Size:        r.Size,
+		Colour:      r.Colour,
+		Components:  toComponents(r.Components),
+	}
+}
+
+func toComponents(requests []ComponentRequest) []models.Component {
+	var components []models.Component
+	for _, c := range requests {
+		components = append(components, models.Component{ProductCode: c.ProductCode, Quantity: c.Quantity})
+	}
+
+	return components
+}
+
+func toProductResponse(product models.Product) ProductResponse {
+	return ProductResponse{
+		Code:        product.Code,
+		Name:        product.Name,
+		Price:       product.Price,
+		Currency:    product.Currency,
+		TaxCategory: product.TaxCategory,
+		Category:    product.Category,
+		Barcodes:    product.Barcodes,
+		Unit:        product.Unit,
+		PLU:         product.PLU,
+		Parent:      product.Parent,
+		Size:        product.Size,
+		Colour:      product.Colour,
+		Components:  toComponentRequests(product.Components),
+		Active:      product.Active,
+	}
+}
+
+func toComponentRequests(components []models.Component) []ComponentRequest {
+	var requests []ComponentRequest
+	for _, c := range components {
+		requests = append(requests, ComponentRequest{ProductCode: c.ProductCode, Quantity: c.Quantity})
+	}
+
+	return requests
+}
+
+// ImportProductsHandler create or update the products of a CSV or JSON file.
+// ImportProductsHandler godoc
+// @Summary      Import products into the catalog
+// @Description  the body is a CSV file with the columns code, name, price, category, barcodes, currency, tax_category,
+// @Description  unit, plu, parent, size, colour, components and active, or a JSON array with the same fields.
+// @Description  Only code, name and price are required. Nothing is written unless all the rows are valid.
+// @Tags         catalog
+// @Accept       plain
+// @Produce      json
+// @Param        format   query     string  false  "csv or json, by default the one of the content type"
+// @Param        dry_run  query     bool    false  "only validate the rows"
+// @Param        upsert   query     bool    false  "update the products that already exist"
+// @Success      200  {object}  ImportResponse
+// @Failure      400  {object}  ImportResponse
+// @Router       /catalog/import [post]
+func (h *Handler) ImportProductsHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		format := ctx.Query("format")
+		if format == "" {
+			format = catalog.FormatJSON
+			if strings.Contains(ctx.ContentType(), catalog.FormatCSV) {
+				format = catalog.FormatCSV
+			}
+		}
+
+		result, err := h.service.ImportProducts(ctx, format, ctx.Request.Body, models.ImportOptions{
+			DryRun: ctx.Query("dry_run") == "true",
+			Upsert: ctx.Query("upsert") == "true",
+		})
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		resp := ImportResponse{
+			DryRun:  result.DryRun,
+			Applied: result.Applied,
+			Created: result.Created,
+			Updated: result.Updated,
+			Errors:  []ImportErrorResponse{},
+		}
+		for _, e := range result.Errors {
+			resp.Errors = append(resp.Errors, ImportErrorResponse{Row: e.Row, Code: e.Code, Error: e.Error})
+		}
+
+		if len(resp.Errors) > 0 {
+			ctx.JSON(http.StatusBadRequest, resp)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
+
+// ExportProductsHandler return all the products of the catalog as a CSV or JSON file.
+// ExportProductsHandler godoc
+// @Summary      Export the products of the catalog
+// @Description  the file has the same format the import reads.
+// @Tags         catalog
+// @Produce      plain
+// @Param        format  query     string  false  "csv or json, json by default"
+// @Success      200
+// @Failure      400
+// @Router       /catalog/export [get]
+func (h *Handler) ExportProductsHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		format := ctx.DefaultQuery("format", catalog.FormatJSON)
+
+		var buf bytes.Buffer
+		if err := h.service.ExportProducts(ctx, format, &buf); err != nil {
+			ctx.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		contentType := "application/json"
+		if format == catalog.FormatCSV {
+			contentType = "text/csv"
+		}
+
+		ctx.Data(http.StatusOK, contentType, buf.Bytes())
 	}
-	return resp
 }
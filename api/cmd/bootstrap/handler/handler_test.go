@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,7 +15,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/patriciabonaldy/cash_register/internal/cashRegister"
+	catalogmemory "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
 	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
 	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
 )
 
@@ -92,6 +96,7 @@ func TestRemoveBasketHandler(t *testing.T) {
 
 	t.Run("given a basket id it returns 200", func(t *testing.T) {
 		repositoryMock := new(storagemocks.Repository)
+		repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(models.NewBasket("4200f350-4fa5-11ec-a386-1e003b1e5256"), nil)
 		repositoryMock.On("RemoveBasket", mock.Anything, mock.Anything).Return(nil)
 		service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
 
@@ -112,7 +117,7 @@ func TestRemoveBasketHandler(t *testing.T) {
 
 	t.Run("given a invalid basket id it returns 400", func(t *testing.T) {
 		repositoryMock := new(storagemocks.Repository)
-		repositoryMock.On("RemoveBasket", mock.Anything, mock.Anything).Return(models.ErrBasketNotFound)
+		repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(models.Basket{}, models.ErrBasketNotFound)
 		service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
 
 		r := gin.New()
@@ -185,13 +190,13 @@ func TestCheckoutBasketHandler(t *testing.T) {
 					Product: models.Product{
 						Code:  "TSHIRT",
 						Name:  "Summer T-Shirt",
-						Price: 20,
+						Price: 2000,
 					},
 					Quantity: 5,
-					Total:    100,
+					Total:    10000,
 				},
 			},
-			Total: 100,
+			Total: 10000,
 		}
 
 		repositoryMock := new(storagemocks.Repository)
@@ -204,14 +209,14 @@ func TestCheckoutBasketHandler(t *testing.T) {
 					Product: models.Product{
 						Code:  "TSHIRT",
 						Name:  "Summer T-Shirt",
-						Price: 20,
+						Price: 2000,
 					},
 					Quantity: 5,
-					Total:    75,
+					Total:    7500,
 				},
 			},
-			Total: 75,
-			Close: true,
+			Total: 7500,
+			State: models.StateCheckedOut,
 		}
 		repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).Return(basketMock2, nil)
 		service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
@@ -255,7 +260,7 @@ func TestAddProductHandler(t *testing.T) {
 			Product: models.Product{
 				Code:  "TSHIRT",
 				Name:  "Summer T-Shirt",
-				Price: 20,
+				Price: 2000,
 			},
 		}
 
@@ -266,7 +271,7 @@ func TestAddProductHandler(t *testing.T) {
 			Items: map[string]models.Item{
 				"TSHIRT": itemMock,
 			},
-			Total: 20,
+			Total: 2000,
 		}
 		basketmock := models.Basket{
 			Code:  "4200f350-4fa5-11ec-a386-1e003b1e5256",
@@ -318,6 +323,71 @@ func TestAddProductHandler(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 	})
+
+	barcodes := []struct {
+		name    string
+		barcode string
+		want    int
+	}{
+		{name: "given a malformed barcode it returns 400", barcode: "8412345000028", want: http.StatusBadRequest},
+		{name: "given an unknown barcode it returns 404", barcode: "4006381333931", want: http.StatusNotFound},
+	}
+	for _, tt := range barcodes {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := new(storagemocks.Repository)
+			repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).
+				Return(models.Basket{Code: request.BasketID, Items: map[string]models.Item{}}, nil)
+
+			service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
+			r := gin.New()
+			handler := New(service)
+			r.POST("/baskets/:id/products/:code", handler.AddProductHandler())
+
+			url := fmt.Sprintf("/baskets/%s/products/%s", request.BasketID, tt.barcode)
+			req, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.want, res.StatusCode)
+		})
+	}
+
+	weights := []struct {
+		name   string
+		weight string
+	}{
+		{name: "given a malformed weight it returns 400", weight: "1.2505"},
+		{name: "given a weight of a product sold by unit it returns 400", weight: "1.250"},
+	}
+	for _, tt := range weights {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := new(storagemocks.Repository)
+			repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).
+				Return(models.Basket{Code: request.BasketID, Items: map[string]models.Item{}}, nil)
+
+			service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
+			r := gin.New()
+			handler := New(service)
+			r.POST("/baskets/:id/products/:code", handler.AddProductHandler())
+
+			url := fmt.Sprintf("/baskets/%s/products/%s?weight=%s", request.BasketID, request.ProductCode, tt.weight)
+			req, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		})
+	}
 }
 
 func TestRemoveProductHandler(t *testing.T) {
@@ -334,8 +404,17 @@ func TestRemoveProductHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("given a valid request it returns 200", func(t *testing.T) {
+		basketMock := models.Basket{
+			Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+			Items: map[string]models.Item{
+				"TSHIRT": {Product: models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000}, Quantity: 1, Total: 2000},
+			},
+			Total:     2000,
+			Approvals: []models.Approval{{Operation: models.OperationVoid, ManagerID: "M-001"}},
+		}
 		repositoryMock := new(storagemocks.Repository)
-		repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketExpected, nil)
+		repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+		repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).Return(basketMock, nil).Maybe()
 		repositoryMock.On("RemoveProduct", mock.Anything, mock.Anything, mock.Anything).Return(basketExpected, nil)
 		service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
 
@@ -399,3 +478,922 @@ func TestRemoveProductHandler(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 	})
 }
+
+func TestExperimentResultsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	err := cashRegister.LoadRulesConfig()
+	require.NoError(t, err)
+
+	t.Run("given a valid experiment it returns 200", func(t *testing.T) {
+		repositoryMock := new(storagemocks.Repository)
+		repositoryMock.On("ListBaskets", mock.Anything).Return([]models.Basket{}, nil)
+		service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
+
+		r := gin.New()
+		handler := New(service)
+		r.GET("/experiments/:name/results", handler.ExperimentResultsHandler())
+		req, err := http.NewRequest(http.MethodGet, "/experiments/tshirt_new_price/results", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		var response ExperimentResponse
+		err = json.NewDecoder(res.Body).Decode(&response)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "tshirt_new_price", response.Experiment)
+		assert.Len(t, response.Variants, 2)
+	})
+
+	t.Run("given an unknown experiment it returns 400", func(t *testing.T) {
+		repositoryMock := new(storagemocks.Repository)
+		service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
+
+		r := gin.New()
+		handler := New(service)
+		r.GET("/experiments/:name/results", handler.ExperimentResultsHandler())
+		req, err := http.NewRequest(http.MethodGet, "/experiments/unknown/results", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestCustomerHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	err := cashRegister.LoadRulesConfig()
+	require.NoError(t, err)
+
+	t.Run("given a valid customer it returns 201", func(t *testing.T) {
+		customersMock := new(storagemocks.CustomerRepository)
+		customersMock.On("CreateCustomer", mock.Anything, mock.Anything).
+			Return(models.Customer{ID: "c1", Name: "Pepito", Tier: models.TierGold}, nil)
+		service := cashRegister.NewService(cashRegister.RulesEngine, nil, cashRegister.WithCustomers(customersMock))
+
+		r := gin.New()
+		handler := New(service)
+		r.POST("/customers", handler.CreateCustomerHandler())
+		body := bytes.NewBufferString(`{"name":"Pepito","tier":"gold"}`)
+		req, err := http.NewRequest(http.MethodPost, "/customers", body)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		var response CustomerResponse
+		err = json.NewDecoder(res.Body).Decode(&response)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, CustomerResponse{ID: "c1", Name: "Pepito", Tier: models.TierGold}, response)
+	})
+
+	t.Run("given a customer without name it returns 400", func(t *testing.T) {
+		service := cashRegister.NewService(cashRegister.RulesEngine, nil, cashRegister.WithCustomers(new(storagemocks.CustomerRepository)))
+
+		r := gin.New()
+		handler := New(service)
+		r.POST("/customers", handler.CreateCustomerHandler())
+		req, err := http.NewRequest(http.MethodPost, "/customers", bytes.NewBufferString(`{}`))
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("given an unknown customer it returns 400 attaching it", func(t *testing.T) {
+		repositoryMock := new(storagemocks.Repository)
+		repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).
+			Return(models.Basket{Code: "4200f350-4fa5-11ec-a386-1e003b1e5256", Items: map[string]models.Item{}}, nil)
+		customersMock := new(storagemocks.CustomerRepository)
+		customersMock.On("FindCustomerByID", mock.Anything, mock.Anything).Return(models.Customer{}, models.ErrCustomerNotFound)
+		service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock, cashRegister.WithCustomers(customersMock))
+
+		r := gin.New()
+		handler := New(service)
+		r.PUT("/baskets/:id/customer/:customerID", handler.AttachCustomerHandler())
+		req, err := http.NewRequest(http.MethodPut, "/baskets/4200f350-4fa5-11ec-a386-1e003b1e5256/customer/c1", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestStaffPurchasesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("given staff purchases it returns 200", func(t *testing.T) {
+		repositoryMock := new(storagemocks.Repository)
+		repositoryMock.On("ListBaskets", mock.Anything).Return([]models.Basket{
+			{Code: "1", Total: 1600, EmployeeDiscount: 400, EmployeeID: "E-001", State: models.StateCheckedOut},
+		}, nil)
+		service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
+
+		r := gin.New()
+		handler := New(service)
+		r.GET("/reports/staff-purchases", handler.StaffPurchasesHandler())
+		req, err := http.NewRequest(http.MethodGet, "/reports/staff-purchases", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		var response []StaffPurchasesResponse
+		err = json.NewDecoder(res.Body).Decode(&response)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []StaffPurchasesResponse{
+			{EmployeeID: "E-001", Baskets: 1, Gross: 2000, Discount: 400, Total: 1600, Tendered: 1600},
+		}, response)
+	})
+}
+
+func TestOverrideProductHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"TSHIRT": {
+				Product:  models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000},
+				Quantity: 1,
+				Total:    2000,
+			},
+		},
+		Total:     2000,
+		Approvals: []models.Approval{{Operation: models.OperationOverride, ManagerID: "M-001"}},
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "given an unit price it returns 200", body: `{"unit_price":15,"reason":"damaged"}`, want: http.StatusOK},
+		{name: "given two overrides it returns 400", body: `{"unit_price":15,"discount_amount":2,"reason":"damaged"}`, want: http.StatusBadRequest},
+		{name: "given no reason it returns 400", body: `{"discount_percent":10}`, want: http.StatusBadRequest},
+		{name: "given an unknown reason it returns 400", body: `{"discount_percent":10,"reason":"bored"}`, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := new(storagemocks.Repository)
+			repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+			repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+				Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+			service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
+
+			r := gin.New()
+			handler := New(service)
+			r.PATCH("/baskets/:id/products/:code", handler.OverrideProductHandler())
+
+			url := fmt.Sprintf("/baskets/%s/products/%s", basketMock.Code, "TSHIRT")
+			req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.want, res.StatusCode)
+		})
+	}
+}
+
+func TestApproveHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	err := cashRegister.LoadRulesConfig()
+	require.NoError(t, err)
+	require.NoError(t, cashRegister.LoadManagers("testdata/managers.yml"))
+
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"TSHIRT": {Product: models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000}, Quantity: 1, Total: 2000},
+		},
+		Total: 2000,
+	}
+
+	t.Run("given an override without approval it returns 403", func(t *testing.T) {
+		repositoryMock := new(storagemocks.Repository)
+		repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+		service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
+
+		r := gin.New()
+		handler := New(service)
+		r.PATCH("/baskets/:id/products/:code", handler.OverrideProductHandler())
+
+		url := fmt.Sprintf("/baskets/%s/products/%s", basketMock.Code, "TSHIRT")
+		req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBufferString(`{"unit_price":15,"reason":"damaged"}`))
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "given a valid pin it returns 201", body: `{"operation":"override","manager_id":"M-001","pin":"1234"}`, want: http.StatusCreated},
+		{name: "given a wrong pin it returns 401", body: `{"operation":"override","manager_id":"M-001","pin":"0000"}`, want: http.StatusUnauthorized},
+		{name: "given an unknown operation it returns 400", body: `{"operation":"checkout","manager_id":"M-001","pin":"1234"}`, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := new(storagemocks.Repository)
+			repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+			repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+				Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+			service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
+
+			r := gin.New()
+			handler := New(service)
+			r.POST("/baskets/:id/approvals", handler.ApproveHandler())
+
+			url := fmt.Sprintf("/baskets/%s/approvals", basketMock.Code)
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.want, res.StatusCode)
+		})
+	}
+}
+
+func TestSetPaymentCurrencyHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, cashRegister.LoadExchangeRates(""))
+
+	tests := []struct {
+		name     string
+		currency string
+		want     int
+	}{
+		{name: "given a known currency it returns 200", currency: "USD", want: http.StatusOK},
+		{name: "given an unknown currency it returns 400", currency: "JPY", want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := new(storagemocks.Repository)
+			repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).
+				Return(models.Basket{Code: "1", Items: map[string]models.Item{}}, nil)
+			repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+				Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+			service := cashRegister.NewService(cashRegister.RulesEngine, repositoryMock)
+
+			r := gin.New()
+			handler := New(service)
+			r.PUT("/baskets/:id/currency/:currency", handler.SetPaymentCurrencyHandler())
+
+			req, err := http.NewRequest(http.MethodPut, "/baskets/1/currency/"+tt.currency, nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.want, res.StatusCode)
+		})
+	}
+}
+
+func TestProductHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, nil,
+		cashRegister.WithCatalog(catalogmemory.NewProductRepository(models.ProductMap[models.Tshirt])))
+	r := gin.New()
+	handler := New(service)
+	r.POST("/products", handler.CreateProductHandler())
+	r.GET("/products", handler.ListProductsHandler())
+	r.DELETE("/products/:code", handler.DeactivateProductHandler())
+
+	t.Run("given a valid product it returns 201", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"code":"SOCKS","name":"Summer Socks","price":"4.50"}`)
+		req, err := http.NewRequest(http.MethodPost, "/products", body)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		var response ProductResponse
+		err = json.NewDecoder(res.Body).Decode(&response)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, ProductResponse{Code: "SOCKS", Name: "Summer Socks", Price: 450, Active: true}, response)
+	})
+
+	t.Run("given a product without name it returns 400", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/products", bytes.NewBufferString(`{"code":"HAT"}`))
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("given a kit it returns its components", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"code":"OUTFIT","name":"Summer Outfit","price":"25.00","components":[{"product_code":"TSHIRT","quantity":1}]}`)
+		req, err := http.NewRequest(http.MethodPost, "/products", body)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response ProductResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		assert.Equal(t, []ComponentRequest{{ProductCode: "TSHIRT", Quantity: 1}}, response.Components)
+
+		body = bytes.NewBufferString(`{"code":"OUTFIT2","name":"Summer Outfit","price":"25.00","components":[{"product_code":"HAT","quantity":1}]}`)
+		req, err = http.NewRequest(http.MethodPost, "/products", body)
+		require.NoError(t, err)
+
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		req, err = http.NewRequest(http.MethodDelete, "/products/OUTFIT", nil)
+		require.NoError(t, err)
+
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("given a deactivated product it's not listed", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodDelete, "/products/TSHIRT", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		req, err = http.NewRequest(http.MethodGet, "/products", nil)
+		require.NoError(t, err)
+
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		var response []ProductResponse
+		err = json.NewDecoder(res.Body).Decode(&response)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []ProductResponse{{Code: "SOCKS", Name: "Summer Socks", Price: 450, Active: true}}, response)
+	})
+}
+
+func TestImportProductsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		url  string
+		body string
+		want int
+	}{
+		{name: "given valid rows it returns 200", url: "/catalog/import?format=csv", body: "code,name,price\nSOCKS,Summer Socks,4.50\n", want: http.StatusOK},
+		{name: "given invalid rows it returns 400", url: "/catalog/import?format=csv", body: "code,name,price\nSOCKS,Summer Socks,ten\n", want: http.StatusBadRequest},
+		{name: "given an unknown format it returns 400", url: "/catalog/import?format=xml", body: "<products/>", want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := cashRegister.NewService(cashRegister.RulesEngine, nil,
+				cashRegister.WithCatalog(catalogmemory.NewProductRepository()))
+
+			r := gin.New()
+			handler := New(service)
+			r.POST("/catalog/import", handler.ImportProductsHandler())
+			req, err := http.NewRequest(http.MethodPost, tt.url, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.want, res.StatusCode)
+		})
+	}
+}
+
+func TestGetStockHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, cashRegister.LoadRulesConfig())
+
+	repository := memory.NewRepository()
+	basket, err := repository.CreateBasket(context.Background(), "1")
+	require.NoError(t, err)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, repository, cashRegister.WithInventory(
+		memory.NewInventoryRepository(models.Stock{ProductCode: models.Tshirt, OnHand: 1})))
+	r := gin.New()
+	handler := New(service)
+	r.GET("/inventory/:code", handler.GetStockHandler())
+	r.POST("/baskets/:id/products/:code", handler.AddProductHandler())
+
+	statuses := []int{http.StatusCreated, http.StatusConflict}
+	for _, want := range statuses {
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/baskets/%s/products/TSHIRT", basket.Code), nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, want, rec.Code)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/inventory/TSHIRT", nil)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	res := rec.Result()
+	defer res.Body.Close()
+
+	var response StockResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, StockResponse{ProductCode: "TSHIRT", OnHand: 1, Reserved: 1}, response)
+}
+
+func TestCycleCountHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, memory.NewRepository(), cashRegister.WithInventory(
+		memory.NewInventoryRepository(models.Stock{ProductCode: models.Tshirt, OnHand: 5})))
+	r := gin.New()
+	handler := New(service)
+	r.POST("/inventory/:code/adjustments", handler.AdjustStockHandler())
+	r.GET("/inventory/:code/movements", handler.ListMovementsHandler())
+	r.POST("/cycle-counts", handler.StartCycleCountHandler())
+	r.PUT("/cycle-counts/:id/products/:code", handler.CountProductHandler())
+	r.POST("/cycle-counts/:id/post", handler.PostCycleCountHandler())
+
+	do := func(method, url, body string, response interface{}) int {
+		req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if response != nil {
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(response))
+		}
+
+		return rec.Code
+	}
+
+	var movement MovementResponse
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/inventory/TSHIRT/adjustments", `{"reason":"receipt","quantity":3}`, &movement))
+	assert.Equal(t, 8, movement.OnHand)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/inventory/TSHIRT/adjustments", `{"reason":"lost","quantity":3}`, nil))
+
+	var count CycleCountResponse
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/cycle-counts", `{"products":["TSHIRT"]}`, &count))
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/cycle-counts/"+count.ID+"/products/TSHIRT", `{"counted":6}`, &count))
+	assert.Equal(t, -2, count.Lines[0].Variance)
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/cycle-counts/"+count.ID+"/post", "", &count))
+	assert.Equal(t, models.CycleCountPosted, count.Status)
+
+	var movements []MovementResponse
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/inventory/TSHIRT/movements", "", &movements))
+	require.Len(t, movements, 2)
+	assert.Equal(t, models.MovementCountCorrection, movements[1].Type)
+	assert.Equal(t, count.ID, movements[1].CycleCountID)
+	assert.Equal(t, 6, movements[1].OnHand)
+}
+
+func TestPriceChangeHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, nil,
+		cashRegister.WithCatalog(catalogmemory.NewProductRepository(models.ProductMap[models.Tshirt])))
+	r := gin.New()
+	handler := New(service)
+	r.POST("/products/:code/prices", handler.SchedulePriceChangeHandler())
+	r.GET("/products/:code/prices", handler.PriceHistoryHandler())
+
+	tests := []struct {
+		name string
+		code string
+		body string
+		want int
+	}{
+		{name: "given a scheduled price it returns 201", code: "TSHIRT", body: `{"price":"18.00","effective_from":"2100-01-01T00:00:00Z"}`, want: http.StatusCreated},
+		{name: "given a past price it returns 400", code: "TSHIRT", body: `{"price":"18.00","effective_from":"2000-01-01T00:00:00Z"}`, want: http.StatusBadRequest},
+		{name: "given an unknown product it returns 400", code: "SOCKS", body: `{"price":"4.00"}`, want: http.StatusBadRequest},
+		{name: "given a free price it returns 201", code: "TSHIRT", body: `{"price":"0.00","effective_from":"2099-01-01T00:00:00Z"}`, want: http.StatusCreated},
+		{name: "given no price it returns 400", code: "TSHIRT", body: `{"effective_from":"2100-01-01T00:00:00Z"}`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "/products/"+tt.code+"/prices", bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/products/TSHIRT/prices", nil)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	var response []PriceChangeResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response, 2)
+	assert.Equal(t, models.Money(0), response[0].Price)
+	assert.Equal(t, models.Money(1800), response[1].Price)
+}
+
+func TestMarkdownHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, memory.NewRepository(),
+		cashRegister.WithCatalog(catalogmemory.NewProductRepository(models.ProductMap[models.Tshirt])),
+		cashRegister.WithMarkdowns(memory.NewMarkdownRepository()))
+	r := gin.New()
+	handler := New(service)
+	r.PUT("/markdowns/:name", handler.SaveMarkdownHandler())
+	r.GET("/markdowns/:name", handler.GetMarkdownHandler())
+	r.GET("/reports/product-discounts", handler.ProductDiscountsHandler())
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "given a markdown it returns 200", body: `{"products":["TSHIRT"],"start":"2022-06-01T00:00:00Z","steps":[{"after_days":60,"percent":20},{"after_days":90,"percent":50}]}`, want: http.StatusOK},
+		{name: "given a markdown without steps it returns 400", body: `{"products":["TSHIRT"],"start":"2022-06-01T00:00:00Z","steps":[]}`, want: http.StatusBadRequest},
+		{name: "given an unknown product it returns 400", body: `{"products":["SOCKS"],"start":"2022-06-01T00:00:00Z","steps":[{"percent":20}]}`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, "/markdowns/summer", bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/markdowns/summer", nil)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	var response MarkdownResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, []MarkdownStepRequest{{AfterDays: 60, Percent: 20}, {AfterDays: 90, Percent: 50}}, response.Steps)
+
+	req, err = http.NewRequest(http.MethodGet, "/markdowns/winter", nil)
+	require.NoError(t, err)
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req, err = http.NewRequest(http.MethodGet, "/reports/product-discounts", nil)
+	require.NoError(t, err)
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "[]", rec.Body.String())
+}
+
+func TestCostAndMarginsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, memory.NewRepository(),
+		cashRegister.WithCatalog(catalogmemory.NewProductRepository(models.ProductMap[models.Voucher])))
+	r := gin.New()
+	handler := New(service)
+	r.POST("/products/:code/costs", handler.ScheduleCostChangeHandler())
+	r.GET("/products/:code/costs", handler.CostHistoryHandler())
+	r.GET("/reports/margins", handler.MarginsHandler())
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+	}{
+		{name: "given a cost it returns 201", method: http.MethodPost, target: "/products/VOUCHER/costs", body: `{"cost":"3.00"}`, want: http.StatusCreated},
+		{name: "given a past cost it returns 400", method: http.MethodPost, target: "/products/VOUCHER/costs", body: `{"cost":"3.00","effective_from":"2000-01-01T00:00:00Z"}`, want: http.StatusBadRequest},
+		{name: "given an unknown product it returns 400", method: http.MethodGet, target: "/products/SOCKS/costs", want: http.StatusBadRequest},
+		{name: "given a free cost it returns 201", method: http.MethodPost, target: "/products/VOUCHER/costs", body: `{"cost":"0.00","effective_from":"2099-01-01T00:00:00Z"}`, want: http.StatusCreated},
+		{name: "given no cost it returns 400", method: http.MethodPost, target: "/products/VOUCHER/costs", body: `{}`, want: http.StatusBadRequest},
+		{name: "given a range it returns 200", method: http.MethodGet, target: "/reports/margins?by=category&from=2022-06-01&to=2022-06-30", want: http.StatusOK},
+		{name: "given an unknown group it returns 400", method: http.MethodGet, target: "/reports/margins?by=cashier", want: http.StatusBadRequest},
+		{name: "given a malformed day it returns 400", method: http.MethodGet, target: "/reports/margins?from=01/06/2022", want: http.StatusBadRequest},
+		{name: "given a reversed range it returns 400", method: http.MethodGet, target: "/reports/margins?from=2022-06-30&to=2022-06-01", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.target, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/products/VOUCHER/costs", nil)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	var response []CostChangeResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response, 2)
+	assert.Equal(t, models.Money(300), response[0].Cost)
+	assert.Equal(t, models.Money(0), response[1].Cost)
+}
+
+func TestAddProductHandler_PurchaseLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, cashRegister.LoadRulesConfig())
+
+	repository := memory.NewRepository()
+	basket, err := repository.CreateBasket(context.Background(), "1")
+	require.NoError(t, err)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, repository)
+	r := gin.New()
+	handler := New(service)
+	r.POST("/baskets/:id/products/:code", handler.AddProductHandler())
+
+	for i := 0; i < 11; i++ {
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/baskets/%s/products/VOUCHER", basket.Code), nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		want := http.StatusCreated
+		if i == 10 {
+			want = http.StatusUnprocessableEntity
+		}
+		assert.Equal(t, want, rec.Code)
+	}
+}
+
+func TestAddProductsHandler_TotalLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, cashRegister.LoadRulesConfig())
+
+	repository := memory.NewRepository()
+	basket, err := repository.CreateBasket(context.Background(), "1")
+	require.NoError(t, err)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, repository)
+	r := gin.New()
+	handler := New(service)
+	r.POST("/baskets/:id/products", handler.AddProductsHandler())
+
+	// 26 units of 20.00 are over the total of 500.00 a manager must approve
+	body := `{"products":[{"code":"TSHIRT","quantity":26}]}`
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/baskets/%s/products", basket.Code), bytes.NewBufferString(body))
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "requires the approval of a manager")
+}
+
+func TestQuantityHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, cashRegister.LoadRulesConfig())
+
+	repository := memory.NewRepository()
+	basket, err := repository.CreateBasket(context.Background(), "1")
+	require.NoError(t, err)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, repository)
+	r := gin.New()
+	handler := New(service)
+	r.POST("/baskets/:id/products", handler.AddProductsHandler())
+	r.PUT("/baskets/:id/products/:code", handler.SetQuantityHandler())
+	r.DELETE("/baskets/:id/products/:code/unit", handler.RemoveUnitHandler())
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		wantCode int
+	}{
+		{name: "bulk add", method: http.MethodPost, url: "/baskets/%s/products",
+			body: `{"products":[{"code":"TSHIRT","quantity":2},{"code":"PANTS","quantity":1}]}`, wantCode: http.StatusCreated},
+		{name: "bulk add unknown product", method: http.MethodPost, url: "/baskets/%s/products",
+			body: `{"products":[{"code":"SOCKS","quantity":2}]}`, wantCode: http.StatusBadRequest},
+		{name: "bulk add without products", method: http.MethodPost, url: "/baskets/%s/products",
+			body: `{}`, wantCode: http.StatusBadRequest},
+		{name: "set quantity", method: http.MethodPut, url: "/baskets/%s/products/TSHIRT",
+			body: `{"quantity":3}`, wantCode: http.StatusOK},
+		{name: "set quantity without quantity", method: http.MethodPut, url: "/baskets/%s/products/TSHIRT",
+			body: `{}`, wantCode: http.StatusBadRequest},
+		{name: "lower quantity needs approval", method: http.MethodPut, url: "/baskets/%s/products/TSHIRT",
+			body: `{"quantity":1}`, wantCode: http.StatusForbidden},
+		{name: "remove unit needs approval", method: http.MethodDelete, url: "/baskets/%s/products/TSHIRT/unit",
+			wantCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, fmt.Sprintf(tt.url, basket.Code), bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+
+	got, err := service.GetBasket(context.Background(), basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 3, got.Items[models.Tshirt].Quantity)
+	assert.Equal(t, 1, got.Items[models.Pants].Quantity)
+}
+
+func TestScanHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, cashRegister.LoadRulesConfig())
+	require.NoError(t, cashRegister.LoadManagers("testdata/managers.yml"))
+
+	repository := memory.NewRepository()
+	basket, err := repository.CreateBasket(context.Background(), "1")
+	require.NoError(t, err)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, repository)
+	r := gin.New()
+	handler := New(service)
+	r.PUT("/baskets/:id/cashier/:cashierID", handler.SetCashierHandler())
+	r.POST("/baskets/:id/products/:code", handler.AddProductHandler())
+	r.POST("/baskets/:id/scans/undo", handler.UndoScanHandler())
+	r.POST("/baskets/:id/approvals", handler.ApproveHandler())
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, fmt.Sprintf(url, basket.Code), bytes.NewBufferString(body))
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/baskets/%s/scans/undo", "").Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPut, "/baskets/%s/cashier/C-001", "").Code)
+
+	// the lines are listed in the order they were scanned
+	codes := []string{models.Voucher, models.Pants, models.Tshirt, models.Pants}
+	for _, code := range codes {
+		require.Equal(t, http.StatusCreated, serve(http.MethodPost, "/baskets/%s/products/"+code, "").Code)
+	}
+
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/baskets/%s/scans/undo", "").Code)
+	rec := serve(http.MethodPost, "/baskets/%s/approvals", `{"operation": "void", "manager_id": "M-001", "pin": "1234"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = serve(http.MethodPost, "/baskets/%s/scans/undo", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Item, 3)
+	assert.Equal(t, []string{models.Voucher, models.Pants, models.Tshirt},
+		[]string{resp.Item[0].Product.Code, resp.Item[1].Product.Code, resp.Item[2].Product.Code})
+	assert.Equal(t, 1, resp.Item[1].Quantity)
+	assert.Equal(t, "C-001", resp.Cashier)
+	require.Len(t, resp.Scans, 4)
+	assert.Equal(t, "C-001", resp.Scans[3].Cashier)
+	assert.NotNil(t, resp.Scans[3].UndoneAt)
+}
+
+func TestBasketStateHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, cashRegister.LoadRulesConfig())
+	require.NoError(t, cashRegister.LoadExchangeRates(""))
+
+	repository := memory.NewRepository()
+	basket, err := repository.CreateBasket(context.Background(), "1")
+	require.NoError(t, err)
+
+	service := cashRegister.NewService(cashRegister.RulesEngine, repository)
+	r := gin.New()
+	handler := New(service)
+	r.POST("/baskets/:id/products/:code", handler.AddProductHandler())
+	r.POST("/baskets/:id/checkout", handler.CheckoutBasketHandler())
+	r.POST("/baskets/:id/pay", handler.PayBasketHandler())
+	r.POST("/baskets/:id/void", handler.VoidBasketHandler())
+	r.POST("/baskets/:id/refund", handler.RefundBasketHandler())
+
+	tests := []struct {
+		name      string
+		url       string
+		wantCode  int
+		wantState string
+	}{
+		{name: "an open basket can't be paid", url: "/baskets/%s/pay", wantCode: http.StatusConflict},
+		{name: "add a product", url: "/baskets/%s/products/TSHIRT", wantCode: http.StatusCreated},
+		{name: "checkout", url: "/baskets/%s/checkout", wantCode: http.StatusOK, wantState: models.StateCheckedOut},
+		{name: "no adds once checked out", url: "/baskets/%s/products/TSHIRT", wantCode: http.StatusBadRequest},
+		{name: "a basket not paid can't be refunded", url: "/baskets/%s/refund", wantCode: http.StatusConflict},
+		{name: "pay", url: "/baskets/%s/pay", wantCode: http.StatusOK, wantState: models.StatePaid},
+		{name: "a paid basket can't be voided", url: "/baskets/%s/void", wantCode: http.StatusConflict},
+		{name: "refund", url: "/baskets/%s/refund", wantCode: http.StatusOK, wantState: models.StateRefunded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(tt.url, basket.Code), nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			require.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantState == "" {
+				return
+			}
+
+			var resp Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantState, resp.State)
+		})
+	}
+}
@@ -1,5 +1,11 @@
 package handler
 
+import (
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
 // swagger:model ProductRequest
 type ProductRequest struct {
 	// the id of basket
@@ -8,32 +14,482 @@ type ProductRequest struct {
 	ProductCode string `json:"product_code" binding:"required"`
 }
 
+// swagger:model QuantityRequest
+type QuantityRequest struct {
+	// the units of the product in the basket, zero removes the line
+	Quantity *int `json:"quantity" binding:"required" example:"3"`
+}
+
+// swagger:model AddProductsRequest
+type AddProductsRequest struct {
+	Products []ProductQuantityRequest `json:"products" binding:"required"`
+}
+
+type ProductQuantityRequest struct {
+	// the code of product or its barcode
+	Code     string `json:"code" binding:"required" example:"VOUCHER"`
+	Quantity int    `json:"quantity" example:"2"`
+}
+
+// swagger:model CatalogProductRequest
+type CatalogProductRequest struct {
+	// the code of product, only read on creation
+	Code string `json:"code" example:"SOCKS"`
+	// the name of product
+	Name string `json:"name" binding:"required" example:"Summer Socks"`
+	// the price of product
+	Price models.Money `json:"price" example:"4.50"`
+	// the currency of the price, the base currency when it's empty
+	Currency string `json:"currency,omitempty" example:"EUR"`
+	// the tax category: standard, reduced, exempt or out_of_scope
+	TaxCategory string `json:"tax_category,omitempty" example:"standard"`
+	// the category of the store the product belongs to
+	Category string `json:"category,omitempty" example:"apparel"`
+	// the GTINs printed on the product, no two products share one
+	Barcodes []string `json:"barcodes,omitempty" example:"4006381333931"`
+	// each or kg, the price of the products by kg is per kilogram
+	Unit string `json:"unit,omitempty" example:"each"`
+	// the item reference of a weighed product in the barcodes of the scales
+	PLU string `json:"plu,omitempty" example:"00123"`
+	// the code of the product this one is a variant of, the variants
+	// without price are sold at the price of their parent
+	Parent string `json:"parent,omitempty" example:"TSHIRT"`
+	Size   string `json:"size,omitempty" example:"M"`
+	Colour string `json:"colour,omitempty" example:"blue"`
+	// the products of a kit, sold at the price of the kit as one line
+	Components []ComponentRequest `json:"components,omitempty"`
+}
+
+type ComponentRequest struct {
+	ProductCode string `json:"product_code" example:"TSHIRT"`
+	Quantity    int    `json:"quantity" example:"1"`
+}
+
+// swagger:model OverrideRequest
+type OverrideRequest struct {
+	// the new unit price of the product
+	UnitPrice *models.Money `json:"unit_price,omitempty"`
+	// a discount of the line by amount
+	DiscountAmount *models.Money `json:"discount_amount,omitempty"`
+	// a discount of the line by percent
+	DiscountPercent *float64 `json:"discount_percent,omitempty"`
+	// the reason code: damaged, price_match, customer_service or missing_tag
+	Reason string `json:"reason" binding:"required" example:"damaged"`
+}
+
+// swagger:model ApprovalRequest
+type ApprovalRequest struct {
+	// the restricted operation: override, void or refund
+	Operation string `json:"operation" binding:"required" example:"override"`
+	// the manager approving the operation
+	ManagerID string `json:"manager_id" binding:"required"`
+	// the pin or token of the manager
+	Pin string `json:"pin" binding:"required"`
+}
+
 // swagger:model BasketRequest
 type BasketRequest struct {
 	// the id for add a new basket
 	BasketID string `json:"basket_id" binding:"required"`
 }
 
+// swagger:model CustomerRequest
+type CustomerRequest struct {
+	// the name of customer
+	Name string `json:"name" binding:"required"`
+	// the loyalty tier: standard, silver or gold
+	Tier string `json:"tier" example:"standard"`
+}
+
+// swagger:model PointsRequest
+type PointsRequest struct {
+	// the points to redeem as discount
+	Points int `json:"points" binding:"min=0"`
+}
+
+// swagger:model CustomerResponse
+type CustomerResponse struct {
+	ID        string `json:"customer_id"`
+	Name      string `json:"name"`
+	Tier      string `json:"tier"`
+	Points    int    `json:"points"`
+	PriceList string `json:"price_list,omitempty"`
+}
+
+// swagger:model PriceListRequest
+type PriceListRequest struct {
+	// the currency of the prices, the base currency when it's empty
+	Currency string `json:"currency,omitempty" example:"EUR"`
+	// the price by product code
+	Prices map[string]models.Money `json:"prices" binding:"required"`
+	// start of validity, optional
+	ValidFrom time.Time `json:"valid_from,omitempty" example:"2022-05-01T00:00:00Z"`
+	// end of validity, optional
+	ValidTo time.Time `json:"valid_to,omitempty" example:"2022-12-31T00:00:00Z"`
+	// promotions are not applied to the products priced by this list
+	DisablePromotions bool `json:"disable_promotions"`
+}
+
+// swagger:model PriceListResponse
+type PriceListResponse struct {
+	Name              string                  `json:"name"`
+	Currency          string                  `json:"currency,omitempty"`
+	Prices            map[string]models.Money `json:"prices"`
+	ValidFrom         time.Time               `json:"valid_from,omitempty"`
+	ValidTo           time.Time               `json:"valid_to,omitempty"`
+	DisablePromotions bool                    `json:"disable_promotions"`
+}
+
+// swagger:model MarkdownRequest
+type MarkdownRequest struct {
+	// the codes of the products marked down, their variants are marked down too
+	Products []string `json:"products,omitempty" example:"TSHIRT"`
+	// the categories marked down
+	Categories []string `json:"categories,omitempty" example:"apparel"`
+	// the steps are counted in days from the start
+	Start time.Time             `json:"start" binding:"required" example:"2022-06-01T00:00:00Z"`
+	Steps []MarkdownStepRequest `json:"steps" binding:"required"`
+}
+
+type MarkdownStepRequest struct {
+	AfterDays int     `json:"after_days" example:"60"`
+	Percent   float64 `json:"percent" example:"20"`
+}
+
+// swagger:model MarkdownResponse
+type MarkdownResponse struct {
+	Name       string                `json:"name"`
+	Products   []string              `json:"products,omitempty"`
+	Categories []string              `json:"categories,omitempty"`
+	Start      time.Time             `json:"start"`
+	Steps      []MarkdownStepRequest `json:"steps"`
+}
+
+// swagger:model ProductDiscountsResponse
+type ProductDiscountsResponse struct {
+	ProductCode       string       `json:"product_code"`
+	Units             int          `json:"units"`
+	Markdown          models.Money `json:"markdown"`
+	PromotionDiscount models.Money `json:"promotion_discount"`
+	Total             models.Money `json:"total"`
+}
+
+// swagger:model CostChangeRequest
+type CostChangeRequest struct {
+	// the new cost price of a unit, or a kilogram of the weighed products
+	Cost *models.Money `json:"cost" binding:"required" example:"2.10"`
+	// when the cost takes effect, now when it's empty
+	EffectiveFrom time.Time `json:"effective_from,omitempty" example:"2022-09-01T00:00:00Z"`
+}
+
+// swagger:model CostChangeResponse
+type CostChangeResponse struct {
+	ProductCode   string       `json:"product_code"`
+	Cost          models.Money `json:"cost"`
+	EffectiveFrom time.Time    `json:"effective_from"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// swagger:model MarginResponse
+type MarginResponse struct {
+	// the product code, category or promotion, empty for the lines without them
+	Key           string       `json:"key"`
+	Units         int          `json:"units"`
+	Revenue       models.Money `json:"revenue"`
+	Cost          models.Money `json:"cost"`
+	Margin        models.Money `json:"margin"`
+	MarginPercent float64      `json:"margin_percent"`
+}
+
 // swagger:model Response
 type Response struct {
 	// basket id
 	ID string `json:"basket_id"`
+	// state of the basket and the transitions that led to it
+	State       string               `json:"state"`
+	Transitions []TransitionResponse `json:"transitions,omitempty"`
 	// items
 	Item []Item `json:"items"`
 	// total
-	Total float64 `json:"total"`
+	Total models.Money `json:"total"`
+	// variant assigned by experiment name
+	Variants map[string]string `json:"variants,omitempty"`
+	// loyalty account attached to the basket
+	CustomerID string `json:"customer_id,omitempty"`
+	// points used as discount
+	PointsRedeemed int          `json:"points_redeemed,omitempty"`
+	PointsDiscount models.Money `json:"points_discount,omitempty"`
+	// points credited on checkout
+	PointsEarned int `json:"points_earned,omitempty"`
+	// employee of a staff purchase
+	EmployeeID       string       `json:"employee_id,omitempty"`
+	EmployeeDiscount models.Money `json:"employee_discount,omitempty"`
+	// approvals of managers for restricted operations
+	Approvals []ApprovalResponse `json:"approvals,omitempty"`
+	// whether the totals of the items include their tax
+	PricesIncludeTax bool         `json:"prices_include_tax"`
+	Tax              models.Money `json:"tax"`
+	// tax summary by category and rate
+	Taxes []TaxResponse `json:"taxes"`
+	// currency of the amounts of the basket
+	Currency string `json:"currency"`
+	// currency the basket is paid in and the total converted at checkout
+	PaymentCurrency string `json:"payment_currency,omitempty"`
+	// exact rate, a decimal like "1.05" or a fraction like "20/21"
+	ExchangeRate string       `json:"exchange_rate,omitempty" example:"1.05"`
+	PaymentTotal models.Money `json:"payment_total,omitempty"`
+	// tender of the payment and the cash rounding included in the payment total
+	Tender             string       `json:"tender,omitempty"`
+	RoundingAdjustment models.Money `json:"rounding_adjustment,omitempty"`
+	// cash rounding in the currency of the basket
+	BaseRoundingAdjustment models.Money `json:"base_rounding_adjustment,omitempty"`
+	// clearance markdowns and discounts of the promotions, already in the total
+	Markdown          models.Money `json:"markdown,omitempty"`
+	PromotionDiscount models.Money `json:"promotion_discount,omitempty"`
+	// when the basket was checked out
+	CheckedOutAt *time.Time `json:"checked_out_at,omitempty"`
+	// cashier at the till and the scans of the lines, in the order they were made
+	Cashier string         `json:"cashier,omitempty"`
+	Scans   []ScanResponse `json:"scans,omitempty"`
+}
+
+// swagger:model TaxResponse
+type TaxResponse struct {
+	Category string       `json:"category"`
+	Rate     float64      `json:"rate"`
+	Net      models.Money `json:"net"`
+	Tax      models.Money `json:"tax"`
+	Gross    models.Money `json:"gross"`
+}
+
+// swagger:model ProductResponse
+type ProductResponse struct {
+	Code        string             `json:"code"`
+	Name        string             `json:"name"`
+	Price       models.Money       `json:"price"`
+	Currency    string             `json:"currency,omitempty"`
+	TaxCategory string             `json:"tax_category,omitempty"`
+	Category    string             `json:"category,omitempty"`
+	Barcodes    []string           `json:"barcodes,omitempty"`
+	Unit        string             `json:"unit,omitempty"`
+	PLU         string             `json:"plu,omitempty"`
+	Parent      string             `json:"parent,omitempty"`
+	Size        string             `json:"size,omitempty"`
+	Colour      string             `json:"colour,omitempty"`
+	Components  []ComponentRequest `json:"components,omitempty"`
+	Active      bool               `json:"active"`
+}
+
+// swagger:model ImportResponse
+type ImportResponse struct {
+	DryRun bool `json:"dry_run"`
+	// whether the products were written, only when all the rows are valid
+	Applied bool                  `json:"applied"`
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Errors  []ImportErrorResponse `json:"errors"`
+}
+
+// swagger:model ImportErrorResponse
+type ImportErrorResponse struct {
+	// line of a CSV file or position in a JSON array, starting at 1
+	Row   int    `json:"row"`
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error"`
+}
+
+// swagger:model ApprovalResponse
+type ApprovalResponse struct {
+	Operation  string     `json:"operation"`
+	ManagerID  string     `json:"manager_id"`
+	ApprovedAt time.Time  `json:"approved_at"`
+	UsedAt     *time.Time `json:"used_at,omitempty"`
+}
+
+// swagger:model TransitionResponse
+type TransitionResponse struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	At   time.Time `json:"at"`
+}
+
+// swagger:model ScanResponse
+type ScanResponse struct {
+	ProductCode string     `json:"product_code"`
+	Quantity    int        `json:"quantity"`
+	Cashier     string     `json:"cashier,omitempty"`
+	ScannedAt   time.Time  `json:"scanned_at"`
+	UndoneAt    *time.Time `json:"undone_at,omitempty"`
 }
 
 // swagger:model Product
 type Product struct {
-	Code  string  `json:"code"`
-	Name  string  `json:"name"`
-	Price float64 `json:"price"`
+	Code     string       `json:"code"`
+	Name     string       `json:"name"`
+	Price    models.Money `json:"price"`
+	Currency string       `json:"currency"`
+	Unit     string       `json:"unit,omitempty"`
+	Parent   string       `json:"parent,omitempty"`
+	Size     string       `json:"size,omitempty"`
+	Colour   string       `json:"colour,omitempty"`
 }
 
 // swagger:model Item
 type Item struct {
-	Product  Product `json:"product"`
-	Quantity int     `json:"quantity"`
-	Total    float64 `json:"total"`
+	Product          Product      `json:"product"`
+	Quantity         int          `json:"quantity"`
+	Total            models.Money `json:"total"`
+	EmployeeDiscount models.Money `json:"employee_discount,omitempty"`
+	// price list the unit price was taken from
+	PriceList string `json:"price_list,omitempty"`
+	// manual price change of the line
+	Override       *OverrideResponse `json:"override,omitempty"`
+	ManualDiscount models.Money      `json:"manual_discount,omitempty"`
+	// share of the points discount of the basket
+	PointsDiscount models.Money `json:"points_discount,omitempty"`
+	// tax of the line after its discounts
+	TaxCategory string       `json:"tax_category"`
+	TaxRate     float64      `json:"tax_rate"`
+	Tax         models.Money `json:"tax"`
+	// weight in kg of a weighed product
+	Weight        models.Weight `json:"weight,omitempty"`
+	PrintedAmount models.Money  `json:"printed_amount,omitempty"`
+	// the amount of every scan of a weighed product, at the price when it was scanned
+	ScanAmounts []models.Money `json:"scan_amounts,omitempty"`
+	// the price of every unit when it was added
+	UnitPrices []models.Money `json:"unit_prices,omitempty"`
+	// clearance markdown of the units and discount of the promotions
+	Markdown          models.Money `json:"markdown,omitempty"`
+	MarkdownPercent   float64      `json:"markdown_percent,omitempty"`
+	PromotionDiscount models.Money `json:"promotion_discount,omitempty"`
+	// the part of a kit line attributed to each of its components
+	Allocations []AllocationResponse `json:"allocations,omitempty"`
+}
+
+type AllocationResponse struct {
+	ProductCode string       `json:"product_code"`
+	Quantity    int          `json:"quantity"`
+	Revenue     models.Money `json:"revenue"`
+	Tax         models.Money `json:"tax"`
+}
+
+// swagger:model OverrideResponse
+type OverrideResponse struct {
+	Type    string       `json:"type"`
+	Amount  models.Money `json:"amount,omitempty"`
+	Percent float64      `json:"percent,omitempty"`
+	Reason  string       `json:"reason"`
+}
+
+// swagger:model StaffPurchasesResponse
+type StaffPurchasesResponse struct {
+	EmployeeID string       `json:"employee_id"`
+	Baskets    int          `json:"baskets"`
+	Gross      models.Money `json:"gross"`
+	Discount   models.Money `json:"discount"`
+	Total      models.Money `json:"total"`
+	// cash rounding of the payments and the total taken with it
+	Rounding models.Money `json:"rounding"`
+	Tendered models.Money `json:"tendered"`
+}
+
+// swagger:model ExperimentResponse
+type ExperimentResponse struct {
+	// experiment name
+	Experiment string `json:"experiment"`
+	// rule under test
+	Rule string `json:"rule"`
+	// results per variant
+	Variants []VariantResponse `json:"variants"`
+}
+
+// swagger:model VariantResponse
+type VariantResponse struct {
+	Variant        string       `json:"variant"`
+	Baskets        int          `json:"baskets"`
+	CheckedOut     int          `json:"checked_out"`
+	ConversionRate float64      `json:"conversion_rate"`
+	Revenue        models.Money `json:"revenue"`
+	AverageBasket  models.Money `json:"average_basket"`
+	// cash rounding of the payments and the revenue taken with it
+	Rounding models.Money `json:"rounding"`
+	Tendered models.Money `json:"tendered"`
+}
+
+// swagger:model StockResponse
+type StockResponse struct {
+	ProductCode string `json:"product_code"`
+	OnHand      int    `json:"on_hand"`
+	Reserved    int    `json:"reserved"`
+	Available   int    `json:"available"`
+}
+
+// swagger:model AdjustmentRequest
+type AdjustmentRequest struct {
+	// the reason code: receipt, damage, theft or count_correction
+	Reason string `json:"reason" binding:"required" example:"receipt"`
+	// the units received, damaged or stolen, or the units counted for a count correction
+	Quantity int    `json:"quantity" binding:"min=0" example:"24"`
+	Note     string `json:"note,omitempty" example:"delivery 4512"`
+}
+
+// swagger:model MovementResponse
+type MovementResponse struct {
+	ProductCode   string    `json:"product_code"`
+	Type          string    `json:"type"`
+	OnHandDelta   int       `json:"on_hand_delta"`
+	ReservedDelta int       `json:"reserved_delta"`
+	OnHand        int       `json:"on_hand"`
+	Reserved      int       `json:"reserved"`
+	BasketID      string    `json:"basket_id,omitempty"`
+	CycleCountID  string    `json:"cycle_count_id,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// swagger:model CycleCountRequest
+type CycleCountRequest struct {
+	// the codes of the products to count
+	Products []string `json:"products" binding:"required" example:"TSHIRT,PANTS"`
+}
+
+// swagger:model CountRequest
+type CountRequest struct {
+	// the units counted
+	Counted *int `json:"counted" binding:"required" example:"12"`
+}
+
+// swagger:model CycleCountResponse
+type CycleCountResponse struct {
+	ID        string              `json:"cycle_count_id"`
+	Status    string              `json:"status"`
+	Lines     []CountLineResponse `json:"lines"`
+	CreatedAt time.Time           `json:"created_at"`
+	PostedAt  *time.Time          `json:"posted_at,omitempty"`
+}
+
+type CountLineResponse struct {
+	ProductCode string `json:"product_code"`
+	Expected    int    `json:"expected"`
+	Counted     *int   `json:"counted"`
+	Variance    int    `json:"variance"`
+}
+
+// swagger:model PriceChangeRequest
+type PriceChangeRequest struct {
+	// the new price of the product
+	Price *models.Money `json:"price" binding:"required" example:"18.00"`
+	// the currency of the price, the one of the product when it's empty
+	Currency string `json:"currency,omitempty" example:"EUR"`
+	// when the price takes effect, now when it's empty
+	EffectiveFrom time.Time `json:"effective_from,omitempty" example:"2022-09-01T00:00:00Z"`
+}
+
+// swagger:model PriceChangeResponse
+type PriceChangeResponse struct {
+	ProductCode   string       `json:"product_code"`
+	Price         models.Money `json:"price"`
+	Currency      string       `json:"currency,omitempty"`
+	EffectiveFrom time.Time    `json:"effective_from"`
+	CreatedAt     time.Time    `json:"created_at"`
 }
@@ -41,7 +41,7 @@ func Middleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -61,8 +61,90 @@ func (s *Server) registerRoutes() {
 		basket.GET("/:id", s.handler.GetBasketHandler())
 		basket.DELETE(":id", s.handler.RemoveBasketHandler())
 		basket.POST("/:id/checkout", s.handler.CheckoutBasketHandler())
+		basket.POST("/:id/pay", s.handler.PayBasketHandler())
+		basket.POST("/:id/void", s.handler.VoidBasketHandler())
+		basket.POST("/:id/refund", s.handler.RefundBasketHandler())
+		basket.POST("/:id/products", s.handler.AddProductsHandler())
 		basket.POST("/:id/products/:code", s.handler.AddProductHandler())
+		basket.PUT("/:id/products/:code", s.handler.SetQuantityHandler())
 		basket.DELETE("/:id/products/:code", s.handler.RemoveProductHandler())
+		basket.DELETE("/:id/products/:code/unit", s.handler.RemoveUnitHandler())
+		basket.PATCH("/:id/products/:code", s.handler.OverrideProductHandler())
+		basket.POST("/:id/scans/undo", s.handler.UndoScanHandler())
+		basket.PUT("/:id/cashier/:cashierID", s.handler.SetCashierHandler())
+		basket.POST("/:id/approvals", s.handler.ApproveHandler())
+		basket.PUT("/:id/customer/:customerID", s.handler.AttachCustomerHandler())
+		basket.POST("/:id/points", s.handler.RedeemPointsHandler())
+		basket.PUT("/:id/employee/:employeeID", s.handler.SetEmployeeHandler())
+		basket.PUT("/:id/price-list/:name", s.handler.AssignBasketPriceListHandler())
+		basket.PUT("/:id/currency/:currency", s.handler.SetPaymentCurrencyHandler())
+		basket.PUT("/:id/tender/:tender", s.handler.SetTenderHandler())
+	}
+
+	product := s.engine.Group("/products")
+	{
+		product.POST("", s.handler.CreateProductHandler())
+		product.GET("", s.handler.ListProductsHandler())
+		product.GET("/:code", s.handler.GetProductHandler())
+		product.GET("/:code/variants", s.handler.ListVariantsHandler())
+		product.POST("/:code/prices", s.handler.SchedulePriceChangeHandler())
+		product.GET("/:code/prices", s.handler.PriceHistoryHandler())
+		product.POST("/:code/costs", s.handler.ScheduleCostChangeHandler())
+		product.GET("/:code/costs", s.handler.CostHistoryHandler())
+		product.PUT("/:code", s.handler.UpdateProductHandler())
+		product.DELETE("/:code", s.handler.DeactivateProductHandler())
+	}
+
+	catalog := s.engine.Group("/catalog")
+	{
+		catalog.POST("/import", s.handler.ImportProductsHandler())
+		catalog.GET("/export", s.handler.ExportProductsHandler())
+	}
+
+	inventory := s.engine.Group("/inventory")
+	{
+		inventory.GET("/:code", s.handler.GetStockHandler())
+		inventory.POST("/:code/adjustments", s.handler.AdjustStockHandler())
+		inventory.GET("/:code/movements", s.handler.ListMovementsHandler())
+	}
+
+	cycleCount := s.engine.Group("/cycle-counts")
+	{
+		cycleCount.POST("", s.handler.StartCycleCountHandler())
+		cycleCount.GET("/:id", s.handler.GetCycleCountHandler())
+		cycleCount.PUT("/:id/products/:code", s.handler.CountProductHandler())
+		cycleCount.POST("/:id/post", s.handler.PostCycleCountHandler())
+	}
+
+	customer := s.engine.Group("/customers")
+	{
+		customer.POST("", s.handler.CreateCustomerHandler())
+		customer.GET("/:id", s.handler.GetCustomerHandler())
+		customer.PUT("/:id/price-list/:name", s.handler.AssignCustomerPriceListHandler())
+	}
+
+	priceList := s.engine.Group("/price-lists")
+	{
+		priceList.PUT("/:name", s.handler.SavePriceListHandler())
+		priceList.GET("/:name", s.handler.GetPriceListHandler())
+	}
+
+	report := s.engine.Group("/reports")
+	{
+		report.GET("/staff-purchases", s.handler.StaffPurchasesHandler())
+		report.GET("/product-discounts", s.handler.ProductDiscountsHandler())
+		report.GET("/margins", s.handler.MarginsHandler())
+	}
+
+	markdown := s.engine.Group("/markdowns")
+	{
+		markdown.PUT("/:name", s.handler.SaveMarkdownHandler())
+		markdown.GET("/:name", s.handler.GetMarkdownHandler())
+	}
+
+	experiment := s.engine.Group("/experiments")
+	{
+		experiment.GET("/:name/results", s.handler.ExperimentResultsHandler())
 	}
 
 	docs.SwaggerInfo.Title = "Swagger Documentation API"
@@ -95,7 +95,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "requires a basket ID example:\"0bfce8da-bdc9-11ec-b9f3-acde48001122\"",
+                "description": "requires a basket ID example:\"0bfce8da-bdc9-11ec-b9f3-acde48001122\"\nonly open baskets are removed, and removing one with lines is a void.",
                 "consumes": [
                     "application/json"
                 ],
@@ -122,12 +122,113 @@ const docTemplate = `{
                     "400": {
                         "description": ""
                     },
+                    "403": {
+                        "description": "manager approval required: void",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": ""
                     }
                 }
             }
         },
+        "/baskets/{id}/approvals": {
+            "post": {
+                "description": "overrides, voids and large refunds require the approval of a manager before doing them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "approve a restricted operation on a basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "approval",
+                        "name": "approval",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ApprovalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "401": {
+                        "description": ""
+                    },
+                    "429": {
+                        "description": "manager is locked out after too many wrong pins",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/baskets/{id}/cashier/{cashierID}": {
+            "put": {
+                "description": "requires a basket id and the cashier id the next scans are recorded with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "set the cashier of a basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "CASHIER ID",
+                        "name": "cashierID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
         "/baskets/{id}/checkout": {
             "post": {
                 "description": "requires a basket id, close of basket and will show details of order.",
@@ -163,6 +264,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -172,19 +279,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/baskets/{id}/products/{code}": {
-            "post": {
-                "description": "requires a basket id, and a product code. if product/code not exists then return \"product does not exist\"",
+        "/baskets/{id}/currency/{currency}": {
+            "put": {
+                "description": "requires a basket id and the currency code, the total is converted at checkout.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
                     "basket"
                 ],
-                "summary": "add a new product to basket.",
+                "summary": "pay a basket in another currency.",
                 "parameters": [
                     {
                         "type": "string",
@@ -195,8 +302,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "CODE",
-                        "name": "code",
+                        "description": "CURRENCY",
+                        "name": "currency",
                         "in": "path",
                         "required": true
                     }
@@ -209,31 +316,65 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handler.Response"
-                        }
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/baskets/{id}/customer/{customerID}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "attach a customer to a basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    {
+                        "type": "string",
+                        "description": "CUSTOMER ID",
+                        "name": "customerID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/handler.Response"
                         }
+                    },
+                    "400": {
+                        "description": ""
                     }
                 }
-            },
-            "delete": {
-                "description": "requires a basket id, and a product code. if product/code not exists then return \"product does not exist\"",
+            }
+        },
+        "/baskets/{id}/employee/{employeeID}": {
+            "put": {
+                "description": "requires a basket id and the employee id the purchase is attributed to.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
                     "basket"
                 ],
-                "summary": "remove a product in the basket.",
+                "summary": "apply the employee discount to a basket.",
                 "parameters": [
                     {
                         "type": "string",
@@ -244,72 +385,2960 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "CODE",
-                        "name": "code",
+                        "description": "EMPLOYEE ID",
+                        "name": "employeeID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": ""
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
                     },
                     "400": {
                         "description": ""
-                    },
-                    "500": {
-                        "description": ""
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.Item": {
-            "type": "object",
-            "properties": {
-                "product": {
-                    "$ref": "#/definitions/handler.Product"
-                },
-                "quantity": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "number"
+        },
+        "/baskets/{id}/pay": {
+            "post": {
+                "description": "requires a basket id, the basket must be checked out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "mark a basket as paid.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "409": {
+                        "description": "basket state transition is not allowed: from open to paid",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 }
             }
         },
-        "handler.Product": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "number"
+        "/baskets/{id}/points": {
+            "post": {
+                "description": "requires a basket with a customer attached, zero points cancels the redemption.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "redeem loyalty points on a basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "points",
+                        "name": "points",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PointsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
                 }
             }
         },
-        "handler.Response": {
-            "type": "object",
-            "properties": {
-                "basket_id": {
-                    "description": "basket id",
-                    "type": "string"
-                },
-                "items": {
-                    "description": "items",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.Item"
-                    }
-                },
-                "total": {
+        "/baskets/{id}/price-list/{name}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "price the new lines of a basket with a price list.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "PRICE LIST",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/baskets/{id}/products": {
+            "post": {
+                "description": "either all the products are added or none is, the errors are the ones of adding a product.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "add many products to the basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "products",
+                        "name": "products",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AddProductsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": ""
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "409": {
+                        "description": ""
+                    },
+                    "422": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/baskets/{id}/products/{code}": {
+            "put": {
+                "description": "adds units, or removes the last units added, and zero removes the line. Removing units is a void,\nit can require the approval of a manager. Weighed products can only be set to zero.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "set the quantity of a product in the basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "quantity",
+                        "name": "quantity",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.QuantityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": "manager approval required: void",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": ""
+                    },
+                    "422": {
+                        "description": ""
+                    }
+                }
+            },
+            "post": {
+                "description": "requires a basket id, and a product code or an EAN-13/UPC-A barcode. if product/code not exists then return \"product does not exist\",\na barcode with a wrong check digit returns 400 and one of no product returns 404,\na product without enough stock returns 409\na product over its purchase limit, or a total over the limit without approval, returns 422",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "add a new product to basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "weight in kg of a weighed product, like 1.250",
+                        "name": "weight",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "requires a basket id, and a product code or an EAN-13/UPC-A barcode. if product/code not exists then return \"product does not exist\",\na barcode with a wrong check digit returns 400 and one of no product returns 404",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "remove a product in the basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": "manager approval required: void",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            },
+            "patch": {
+                "description": "requires one of unit_price, discount_amount or discount_percent and a reason code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "override the price of a product in the basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "override",
+                        "name": "override",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.OverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": "manager approval required: override",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/baskets/{id}/products/{code}/unit": {
+            "delete": {
+                "description": "removes the last unit added with its price, and the line with its last unit. It's a void,\nit can require the approval of a manager.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "remove a unit of a product in the basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": "manager approval required: void",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/baskets/{id}/refund": {
+            "post": {
+                "description": "refunds a paid basket and puts back its stock. Refunds over the large refund amount\nrequire the approval of a manager.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "refund a basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": "manager approval required: refund",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "basket state transition is not allowed: from open to paid",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/baskets/{id}/scans/undo": {
+            "post": {
+                "description": "the units added by the last scan are removed, which is a void and can require the approval\nof a manager, and the units removed are added again. The scan is kept marked as undone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "undo the last scan of a basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": "manager approval required: void",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/baskets/{id}/tender/{tender}": {
+            "put": {
+                "description": "requires a basket id and the tender, cash or card. Cash payments may be rounded at checkout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "set how a basket is paid.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "TENDER",
+                        "name": "tender",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/baskets/{id}/void": {
+            "post": {
+                "description": "cancels an open or checked out basket and puts back its stock. It's a void when\nthe basket has lines, it can require the approval of a manager.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "basket"
+                ],
+                "summary": "void a basket.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": "manager approval required: void",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "basket state transition is not allowed: from open to paid",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/export": {
+            "get": {
+                "description": "the file has the same format the import reads.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "catalog"
+                ],
+                "summary": "Export the products of the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv or json, json by default",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/catalog/import": {
+            "post": {
+                "description": "the body is a CSV file with the columns code, name, price, category, barcodes, currency, tax_category,\nunit, plu, parent, size, colour, components and active, or a JSON array with the same fields.\nOnly code, name and price are required. Nothing is written unless all the rows are valid.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "catalog"
+                ],
+                "summary": "Import products into the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv or json, by default the one of the content type",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only validate the rows",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "update the products that already exist",
+                        "name": "upsert",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ImportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers": {
+            "post": {
+                "description": "requires a name and optionally a tier (standard, silver, gold).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customer"
+                ],
+                "summary": "Create a new loyalty account.",
+                "parameters": [
+                    {
+                        "description": "customer",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CustomerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CustomerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/customers/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customer"
+                ],
+                "summary": "Show a loyalty account and its points balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CustomerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/customers/{id}/price-list/{name}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customer"
+                ],
+                "summary": "assign a negotiated price list to a customer.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "PRICE LIST",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CustomerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/cycle-counts": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Start a cycle count",
+                "parameters": [
+                    {
+                        "description": "products to count",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CycleCountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CycleCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/cycle-counts/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Show a cycle count and its variances",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CycleCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/cycle-counts/{id}/post": {
+            "post": {
+                "description": "the variances of the products counted are posted as count corrections.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Post the variances of a cycle count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CycleCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/cycle-counts/{id}/products/{code}": {
+            "put": {
+                "description": "the units on hand when it's counted are the expected ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Count a product of a cycle count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "units counted",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CycleCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/experiments/{name}/results": {
+            "get": {
+                "description": "requires an experiment name, return conversion and revenue per variant.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "experiment"
+                ],
+                "summary": "results of an A/B experiment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "NAME",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ExperimentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/inventory/{code}": {
+            "get": {
+                "description": "the units on hand, the units held by the open baskets and the available ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Show the stock of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StockResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/inventory/{code}/adjustments": {
+            "post": {
+                "description": "the reason is receipt, damage or theft with the units received, damaged or stolen,\nor count_correction with the units counted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Adjust the stock of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "adjustment",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AdjustmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MovementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/inventory/{code}/movements": {
+            "get": {
+                "description": "sales, reservations, adjustments and count corrections, oldest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "List the movements of the stock of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.MovementResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/markdowns/{name}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "markdown"
+                ],
+                "summary": "show a markdown policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "NAME",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MarkdownResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            },
+            "put": {
+                "description": "clearance markdown in steps, like -20% after 60 days and -50% after 90, from the start.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "markdown"
+                ],
+                "summary": "create or replace a markdown policy.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "NAME",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "markdown",
+                        "name": "markdown",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MarkdownRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MarkdownResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/price-lists/{name}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-list"
+                ],
+                "summary": "show a price list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "NAME",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PriceListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            },
+            "put": {
+                "description": "negotiated prices by product code, with optional validity dates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-list"
+                ],
+                "summary": "create or replace a price list.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "NAME",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "price list",
+                        "name": "priceList",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PriceListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PriceListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "deactivated products are only listed with all=true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "List the products of the catalog",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "include deactivated products",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ProductResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            },
+            "post": {
+                "description": "requires a code, name and price, the product is created active.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Create a new product in the catalog.",
+                "parameters": [
+                    {
+                        "description": "product",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CatalogProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/products/{code}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Show a product of the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            },
+            "put": {
+                "description": "the code of the body is ignored, a deactivated product is reactivated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Update a product of the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "product",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CatalogProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Deactivate a product of the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/products/{code}/costs": {
+            "get": {
+                "description": "the past and the scheduled cost changes, sorted by the time they take effect.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Show the cost history of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.CostChangeResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            },
+            "post": {
+                "description": "the cost takes effect at effective_from, or now when it's empty. The lines of the baskets\nare costed at checkout with the cost in force.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Schedule a cost change of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "cost change",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CostChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CostChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/products/{code}/prices": {
+            "get": {
+                "description": "the past and the scheduled price changes, sorted by the time they take effect.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Show the price history of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.PriceChangeResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            },
+            "post": {
+                "description": "the price takes effect at effective_from, or now when it's empty. The lines of the baskets\nkeep the price in force when their units were added.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Schedule a price change of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "price change",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PriceChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PriceChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/products/{code}/variants": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "List the variants of a product of the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CODE",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ProductResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/reports/margins": {
+            "get": {
+                "description": "net line amounts and their cost in the baskets checked out from the first day to the last one,\nboth included. Without dates the range is open.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "gross margin per product, category or promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "product",
+                        "description": "product, category or promotion",
+                        "name": "by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "first day, 2006-01-02",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "last day, 2006-01-02",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.MarginResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/reports/product-discounts": {
+            "get": {
+                "description": "units sold in the checked out baskets, with the clearance markdowns apart from the promotions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "markdowns and promotional discounts per product",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ProductDiscountsResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/reports/staff-purchases": {
+            "get": {
+                "description": "checked out baskets with employee discount, for payroll deduction.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "staff purchases per employee",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.StaffPurchasesResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.AddProductsRequest": {
+            "type": "object",
+            "required": [
+                "products"
+            ],
+            "properties": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ProductQuantityRequest"
+                    }
+                }
+            }
+        },
+        "handler.AdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "delivery 4512"
+                },
+                "quantity": {
+                    "description": "the units received, damaged or stolen, or the units counted for a count correction",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 24
+                },
+                "reason": {
+                    "description": "the reason code: receipt, damage, theft or count_correction",
+                    "type": "string",
+                    "example": "receipt"
+                }
+            }
+        },
+        "handler.AllocationResponse": {
+            "type": "object",
+            "properties": {
+                "product_code": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "string"
+                },
+                "tax": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ApprovalRequest": {
+            "type": "object",
+            "required": [
+                "manager_id",
+                "operation",
+                "pin"
+            ],
+            "properties": {
+                "manager_id": {
+                    "description": "the manager approving the operation",
+                    "type": "string"
+                },
+                "operation": {
+                    "description": "the restricted operation: override, void or refund",
+                    "type": "string",
+                    "example": "override"
+                },
+                "pin": {
+                    "description": "the pin or token of the manager",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ApprovalResponse": {
+            "type": "object",
+            "properties": {
+                "approved_at": {
+                    "type": "string"
+                },
+                "manager_id": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "used_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CatalogProductRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "barcodes": {
+                    "description": "the GTINs printed on the product, no two products share one",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "4006381333931"
+                    ]
+                },
+                "category": {
+                    "description": "the category of the store the product belongs to",
+                    "type": "string",
+                    "example": "apparel"
+                },
+                "code": {
+                    "description": "the code of product, only read on creation",
+                    "type": "string",
+                    "example": "SOCKS"
+                },
+                "colour": {
+                    "type": "string",
+                    "example": "blue"
+                },
+                "components": {
+                    "description": "the products of a kit, sold at the price of the kit as one line",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ComponentRequest"
+                    }
+                },
+                "currency": {
+                    "description": "the currency of the price, the base currency when it's empty",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "name": {
+                    "description": "the name of product",
+                    "type": "string",
+                    "example": "Summer Socks"
+                },
+                "parent": {
+                    "description": "the code of the product this one is a variant of, the variants\nwithout price are sold at the price of their parent",
+                    "type": "string",
+                    "example": "TSHIRT"
+                },
+                "plu": {
+                    "description": "the item reference of a weighed product in the barcodes of the scales",
+                    "type": "string",
+                    "example": "00123"
+                },
+                "price": {
+                    "description": "the price of product",
+                    "type": "string",
+                    "example": "4.50"
+                },
+                "size": {
+                    "type": "string",
+                    "example": "M"
+                },
+                "tax_category": {
+                    "description": "the tax category: standard, reduced, exempt or out_of_scope",
+                    "type": "string",
+                    "example": "standard"
+                },
+                "unit": {
+                    "description": "each or kg, the price of the products by kg is per kilogram",
+                    "type": "string",
+                    "example": "each"
+                }
+            }
+        },
+        "handler.ComponentRequest": {
+            "type": "object",
+            "properties": {
+                "product_code": {
+                    "type": "string",
+                    "example": "TSHIRT"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handler.CostChangeRequest": {
+            "type": "object",
+            "required": [
+                "cost"
+            ],
+            "properties": {
+                "cost": {
+                    "description": "the new cost price of a unit, or a kilogram of the weighed products",
+                    "type": "string",
+                    "example": "2.10"
+                },
+                "effective_from": {
+                    "description": "when the cost takes effect, now when it's empty",
+                    "type": "string",
+                    "example": "2022-09-01T00:00:00Z"
+                }
+            }
+        },
+        "handler.CostChangeResponse": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "product_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CountLineResponse": {
+            "type": "object",
+            "properties": {
+                "counted": {
+                    "type": "integer"
+                },
+                "expected": {
+                    "type": "integer"
+                },
+                "product_code": {
+                    "type": "string"
+                },
+                "variance": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.CountRequest": {
+            "type": "object",
+            "required": [
+                "counted"
+            ],
+            "properties": {
+                "counted": {
+                    "description": "the units counted",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "handler.CustomerRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "the name of customer",
+                    "type": "string"
+                },
+                "tier": {
+                    "description": "the loyalty tier: standard, silver or gold",
+                    "type": "string",
+                    "example": "standard"
+                }
+            }
+        },
+        "handler.CustomerResponse": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "price_list": {
+                    "type": "string"
+                },
+                "tier": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CycleCountRequest": {
+            "type": "object",
+            "required": [
+                "products"
+            ],
+            "properties": {
+                "products": {
+                    "description": "the codes of the products to count",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "TSHIRT",
+                        "PANTS"
+                    ]
+                }
+            }
+        },
+        "handler.CycleCountResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "cycle_count_id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.CountLineResponse"
+                    }
+                },
+                "posted_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ExperimentResponse": {
+            "type": "object",
+            "properties": {
+                "experiment": {
+                    "description": "experiment name",
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "rule under test",
+                    "type": "string"
+                },
+                "variants": {
+                    "description": "results per variant",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.VariantResponse"
+                    }
+                }
+            }
+        },
+        "handler.ImportErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "row": {
+                    "description": "line of a CSV file or position in a JSON array, starting at 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "whether the products were written, only when all the rows are valid",
+                    "type": "boolean"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ImportErrorResponse"
+                    }
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.Item": {
+            "type": "object",
+            "properties": {
+                "allocations": {
+                    "description": "the part of a kit line attributed to each of its components",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.AllocationResponse"
+                    }
+                },
+                "employee_discount": {
+                    "type": "string"
+                },
+                "manual_discount": {
+                    "type": "string"
+                },
+                "markdown": {
+                    "description": "clearance markdown of the units and discount of the promotions",
+                    "type": "string"
+                },
+                "markdown_percent": {
+                    "type": "number"
+                },
+                "override": {
+                    "description": "manual price change of the line",
+                    "$ref": "#/definitions/handler.OverrideResponse"
+                },
+                "points_discount": {
+                    "description": "share of the points discount of the basket",
+                    "type": "string"
+                },
+                "price_list": {
+                    "description": "price list the unit price was taken from",
+                    "type": "string"
+                },
+                "printed_amount": {
+                    "type": "string"
+                },
+                "product": {
+                    "$ref": "#/definitions/handler.Product"
+                },
+                "promotion_discount": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "scan_amounts": {
+                    "description": "the amount of every scan of a weighed product, at the price when it was scanned",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tax": {
+                    "type": "string"
+                },
+                "tax_category": {
+                    "description": "tax of the line after its discounts",
+                    "type": "string"
+                },
+                "tax_rate": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "string"
+                },
+                "unit_prices": {
+                    "description": "the price of every unit when it was added",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "weight": {
+                    "description": "weight in kg of a weighed product",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.MarginResponse": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "the product code, category or promotion, empty for the lines without them",
+                    "type": "string"
+                },
+                "margin": {
+                    "type": "string"
+                },
+                "margin_percent": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.MarkdownRequest": {
+            "type": "object",
+            "required": [
+                "start",
+                "steps"
+            ],
+            "properties": {
+                "categories": {
+                    "description": "the categories marked down",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "apparel"
+                    ]
+                },
+                "products": {
+                    "description": "the codes of the products marked down, their variants are marked down too",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "TSHIRT"
+                    ]
+                },
+                "start": {
+                    "description": "the steps are counted in days from the start",
+                    "type": "string",
+                    "example": "2022-06-01T00:00:00Z"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.MarkdownStepRequest"
+                    }
+                }
+            }
+        },
+        "handler.MarkdownResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "start": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.MarkdownStepRequest"
+                    }
+                }
+            }
+        },
+        "handler.MarkdownStepRequest": {
+            "type": "object",
+            "properties": {
+                "after_days": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "percent": {
+                    "type": "number",
+                    "example": 20
+                }
+            }
+        },
+        "handler.MovementResponse": {
+            "type": "object",
+            "properties": {
+                "basket_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cycle_count_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "on_hand_delta": {
+                    "type": "integer"
+                },
+                "product_code": {
+                    "type": "string"
+                },
+                "reserved": {
+                    "type": "integer"
+                },
+                "reserved_delta": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.OverrideRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "discount_amount": {
+                    "description": "a discount of the line by amount",
+                    "type": "string"
+                },
+                "discount_percent": {
+                    "description": "a discount of the line by percent",
+                    "type": "number"
+                },
+                "reason": {
+                    "description": "the reason code: damaged, price_match, customer_service or missing_tag",
+                    "type": "string",
+                    "example": "damaged"
+                },
+                "unit_price": {
+                    "description": "the new unit price of the product",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.OverrideResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.PointsRequest": {
+            "type": "object",
+            "properties": {
+                "points": {
+                    "description": "the points to redeem as discount",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "handler.PriceChangeRequest": {
+            "type": "object",
+            "required": [
+                "price"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "the currency of the price, the one of the product when it's empty",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "effective_from": {
+                    "description": "when the price takes effect, now when it's empty",
+                    "type": "string",
+                    "example": "2022-09-01T00:00:00Z"
+                },
+                "price": {
+                    "description": "the new price of the product",
+                    "type": "string",
+                    "example": "18.00"
+                }
+            }
+        },
+        "handler.PriceChangeResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "product_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.PriceListRequest": {
+            "type": "object",
+            "required": [
+                "prices"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "the currency of the prices, the base currency when it's empty",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "disable_promotions": {
+                    "description": "promotions are not applied to the products priced by this list",
+                    "type": "boolean"
+                },
+                "prices": {
+                    "description": "the price by product code",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "valid_from": {
+                    "description": "start of validity, optional",
+                    "type": "string",
+                    "example": "2022-05-01T00:00:00Z"
+                },
+                "valid_to": {
+                    "description": "end of validity, optional",
+                    "type": "string",
+                    "example": "2022-12-31T00:00:00Z"
+                }
+            }
+        },
+        "handler.PriceListResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "disable_promotions": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prices": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.Product": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "colour": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ProductDiscountsResponse": {
+            "type": "object",
+            "properties": {
+                "markdown": {
+                    "type": "string"
+                },
+                "product_code": {
+                    "type": "string"
+                },
+                "promotion_discount": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ProductQuantityRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "the code of product or its barcode",
+                    "type": "string",
+                    "example": "VOUCHER"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "handler.ProductResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "barcodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "colour": {
+                    "type": "string"
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ComponentRequest"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent": {
+                    "type": "string"
+                },
+                "plu": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "string"
+                },
+                "tax_category": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.QuantityRequest": {
+            "type": "object",
+            "required": [
+                "quantity"
+            ],
+            "properties": {
+                "quantity": {
+                    "description": "the units of the product in the basket, zero removes the line",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handler.Response": {
+            "type": "object",
+            "properties": {
+                "approvals": {
+                    "description": "approvals of managers for restricted operations",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ApprovalResponse"
+                    }
+                },
+                "base_rounding_adjustment": {
+                    "description": "cash rounding in the currency of the basket",
+                    "type": "string"
+                },
+                "basket_id": {
+                    "description": "basket id",
+                    "type": "string"
+                },
+                "cashier": {
+                    "description": "cashier at the till and the scans of the lines, in the order they were made",
+                    "type": "string"
+                },
+                "checked_out_at": {
+                    "description": "when the basket was checked out",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "currency of the amounts of the basket",
+                    "type": "string"
+                },
+                "customer_id": {
+                    "description": "loyalty account attached to the basket",
+                    "type": "string"
+                },
+                "employee_discount": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "description": "employee of a staff purchase",
+                    "type": "string"
+                },
+                "exchange_rate": {
+                    "description": "exact rate, a decimal like \"1.05\" or a fraction like \"20/21\"",
+                    "type": "string",
+                    "example": "1.05"
+                },
+                "items": {
+                    "description": "items",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Item"
+                    }
+                },
+                "markdown": {
+                    "description": "clearance markdowns and discounts of the promotions, already in the total",
+                    "type": "string"
+                },
+                "payment_currency": {
+                    "description": "currency the basket is paid in and the total converted at checkout",
+                    "type": "string"
+                },
+                "payment_total": {
+                    "type": "string"
+                },
+                "points_discount": {
+                    "type": "string"
+                },
+                "points_earned": {
+                    "description": "points credited on checkout",
+                    "type": "integer"
+                },
+                "points_redeemed": {
+                    "description": "points used as discount",
+                    "type": "integer"
+                },
+                "prices_include_tax": {
+                    "description": "whether the totals of the items include their tax",
+                    "type": "boolean"
+                },
+                "promotion_discount": {
+                    "type": "string"
+                },
+                "rounding_adjustment": {
+                    "type": "string"
+                },
+                "scans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ScanResponse"
+                    }
+                },
+                "state": {
+                    "description": "state of the basket and the transitions that led to it",
+                    "type": "string"
+                },
+                "tax": {
+                    "type": "string"
+                },
+                "taxes": {
+                    "description": "tax summary by category and rate",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.TaxResponse"
+                    }
+                },
+                "tender": {
+                    "description": "tender of the payment and the cash rounding included in the payment total",
+                    "type": "string"
+                },
+                "total": {
                     "description": "total",
+                    "type": "string"
+                },
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.TransitionResponse"
+                    }
+                },
+                "variants": {
+                    "description": "variant assigned by experiment name",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.ScanResponse": {
+            "type": "object",
+            "properties": {
+                "cashier": {
+                    "type": "string"
+                },
+                "product_code": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "scanned_at": {
+                    "type": "string"
+                },
+                "undone_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.StaffPurchasesResponse": {
+            "type": "object",
+            "properties": {
+                "baskets": {
+                    "type": "integer"
+                },
+                "discount": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "gross": {
+                    "type": "string"
+                },
+                "rounding": {
+                    "description": "cash rounding of the payments and the total taken with it",
+                    "type": "string"
+                },
+                "tendered": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.StockResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "product_code": {
+                    "type": "string"
+                },
+                "reserved": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.TaxResponse": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "gross": {
+                    "type": "string"
+                },
+                "net": {
+                    "type": "string"
+                },
+                "rate": {
                     "type": "number"
+                },
+                "tax": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TransitionResponse": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.VariantResponse": {
+            "type": "object",
+            "properties": {
+                "average_basket": {
+                    "type": "string"
+                },
+                "baskets": {
+                    "type": "integer"
+                },
+                "checked_out": {
+                    "type": "integer"
+                },
+                "conversion_rate": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "string"
+                },
+                "rounding": {
+                    "description": "cash rounding of the payments and the revenue taken with it",
+                    "type": "string"
+                },
+                "tendered": {
+                    "type": "string"
+                },
+                "variant": {
+                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type ImportResult struct {
+	DryRun  bool `json:"dry_run"`
+	Applied bool `json:"applied"`
+	Created int  `json:"created"`
+	Updated int  `json:"updated"`
+	Errors  []struct {
+		Row   int    `json:"row"`
+		Code  string `json:"code"`
+		Error string `json:"error"`
+	} `json:"errors"`
+}
+
+func catalogCmd() *cobra.Command { // nolint:funlen
+	catalog := &cobra.Command{
+		Use:   "catalog",
+		Short: "import and export the products of the catalog",
+		Run:   func(cmd *cobra.Command, args []string) {},
+	}
+
+	var dryRun, upsert bool
+	importProducts := &cobra.Command{
+		Use:     "import",
+		Short:   "import the products of a csv or json file",
+		Example: "catalog import products.csv --dry-run --upsert",
+		Args:    cobra.ExactValidArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			file := args[0]
+			format := strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				log.Panicf("error reading file: %s", err)
+			}
+
+			url := fmt.Sprintf("http://localhost:8080/catalog/import?format=%s&dry_run=%t&upsert=%t", format, dryRun, upsert)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(content))
+			if err != nil {
+				log.Panic("error building a http client")
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				log.Panic("error building a http client")
+			}
+
+			defer response.Body.Close()
+			bodyResp, err := io.ReadAll(response.Body)
+			if err != nil {
+				log.Panic("error reading response")
+			}
+
+			var result ImportResult
+			if err = json.Unmarshal(bodyResp, &result); err != nil {
+				fmt.Println(string(bodyResp))
+				return
+			}
+
+			for _, e := range result.Errors {
+				fmt.Printf("row %d %s: %s\n", e.Row, e.Code, e.Error)
+			}
+
+			switch {
+			case result.Applied:
+				fmt.Printf("products imported, created: %d updated: %d\n", result.Created, result.Updated)
+			case len(result.Errors) > 0:
+				fmt.Println("nothing was imported")
+			default:
+				fmt.Printf("dry run, would create: %d update: %d\n", result.Created, result.Updated)
+			}
+		},
+	}
+	importProducts.Flags().BoolVar(&dryRun, "dry-run", false, "only validate the rows")
+	importProducts.Flags().BoolVar(&upsert, "upsert", false, "update the products that already exist")
+
+	var output string
+	exportProducts := &cobra.Command{
+		Use:     "export",
+		Short:   "export the products as csv or json",
+		Example: "catalog export csv --output products.csv",
+		Args:    cobra.ExactValidArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			url := fmt.Sprintf("http://localhost:8080/catalog/export?format=%s", args[0])
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			if err != nil {
+				log.Panic("error building a http client")
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				log.Panic("error building a http client")
+			}
+
+			defer response.Body.Close()
+			bodyResp, err := io.ReadAll(response.Body)
+			if err != nil {
+				log.Panic("error reading response")
+			}
+
+			if response.StatusCode != http.StatusOK || output == "" {
+				fmt.Print(string(bodyResp))
+				return
+			}
+
+			if err = os.WriteFile(output, bodyResp, 0o644); err != nil {
+				log.Panicf("error writing file: %s", err)
+			}
+
+			fmt.Printf("products exported to %s\n", output)
+		},
+	}
+	exportProducts.Flags().StringVarP(&output, "output", "o", "", "file to write, stdout by default")
+
+	catalog.AddCommand(importProducts, exportProducts)
+
+	return catalog
+}
@@ -8,24 +8,54 @@ import (
 	"net/http"
 
 	"github.com/spf13/cobra"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
 )
 
 type Response struct {
-	ID    string  `json:"basket_id"`
-	Item  []Item  `json:"items"`
-	Total float64 `json:"total"`
+	ID               string       `json:"basket_id"`
+	Item             []Item       `json:"items"`
+	Total            models.Money `json:"total"`
+	PricesIncludeTax bool         `json:"prices_include_tax"`
+	Taxes            []Tax        `json:"taxes"`
+	Currency         string       `json:"currency"`
+	PaymentCurrency  string       `json:"payment_currency"`
+	ExchangeRate     float64      `json:"exchange_rate"`
+	PaymentTotal     models.Money `json:"payment_total"`
+	Tender           string       `json:"tender"`
+	Rounding         models.Money `json:"rounding_adjustment"`
+}
+
+type Tax struct {
+	Category string       `json:"category"`
+	Rate     float64      `json:"rate"`
+	Net      models.Money `json:"net"`
+	Tax      models.Money `json:"tax"`
+	Gross    models.Money `json:"gross"`
 }
 
 type Product struct {
-	Code  string  `json:"code"`
-	Name  string  `json:"name"`
-	Price float64 `json:"price"`
+	Code     string       `json:"code"`
+	Name     string       `json:"name"`
+	Price    models.Money `json:"price"`
+	Currency string       `json:"currency"`
+	Unit     string       `json:"unit"`
 }
 
 type Item struct {
-	Product  Product `json:"product"`
-	Quantity int     `json:"quantity"`
-	Total    float64 `json:"total"`
+	Product        Product       `json:"product"`
+	Quantity       int           `json:"quantity"`
+	Weight         models.Weight `json:"weight"`
+	Total          models.Money  `json:"total"`
+	Override       *Override     `json:"override"`
+	ManualDiscount models.Money  `json:"manual_discount"`
+}
+
+type Override struct {
+	Type    string       `json:"type"`
+	Amount  models.Money `json:"amount"`
+	Percent float64      `json:"percent"`
+	Reason  string       `json:"reason"`
 }
 
 func clientCmd() *cobra.Command { // nolint:funlen
@@ -98,7 +128,7 @@ func clientCmd() *cobra.Command { // nolint:funlen
 	addProductToBasket := &cobra.Command{
 		Use:     "add",
 		Short:   "add a new product to basket",
-		Example: "basket add basket_id product_code ",
+		Example: "basket add basket_id product_code [--weight 1.250]",
 		Args:    cobra.ExactValidArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			basketID := args[0]
@@ -109,6 +139,10 @@ func clientCmd() *cobra.Command { // nolint:funlen
 			}
 
 			url := fmt.Sprintf("http://localhost:8080/baskets/%s/products/%s", basketID, productID)
+			if weight, _ := cmd.Flags().GetString("weight"); weight != "" {
+				url += "?weight=" + weight
+			}
+
 			request, err := http.NewRequest(http.MethodPost, url, nil)
 			if err != nil {
 				log.Panic("error building a http client")
@@ -131,6 +165,8 @@ func clientCmd() *cobra.Command { // nolint:funlen
 		},
 	}
 
+	addProductToBasket.Flags().String("weight", "", "weight in kg of a weighed product, like 1.250")
+
 	checkoutBasket := &cobra.Command{
 		Use:     "checkout",
 		Short:   "close a basket and return total amount",
@@ -164,16 +200,118 @@ func clientCmd() *cobra.Command { // nolint:funlen
 			fmt.Println("Items:")
 			for _, item := range _basket.Item {
 				fmt.Printf("      Item: %s\n", item.Product.Code)
-				fmt.Printf("      Quantity: %v      Unit price: %v\n", item.Quantity, item.Product.Price)
+				if item.Product.Unit == models.UnitKg {
+					fmt.Printf("      Weight: %v kg      Price: %v %s/kg\n", item.Weight, item.Product.Price, item.Product.Currency)
+				} else {
+					fmt.Printf("      Quantity: %v      Unit price: %v %s\n", item.Quantity, item.Product.Price, item.Product.Currency)
+				}
+				if item.Override != nil {
+					value := item.Override.Amount.String()
+					if item.Override.Type == models.OverrideDiscountPercent {
+						value = fmt.Sprintf("%v%%", item.Override.Percent)
+					}
+					fmt.Printf("      Override: %s %s (%s)\n", item.Override.Type, value, item.Override.Reason)
+					fmt.Printf("      Manual Discount:             %v\n", item.ManualDiscount)
+				}
 				fmt.Printf("      Total With Discount:         %v\n", item.Total)
 				fmt.Println("")
 			}
 			fmt.Println("----------------------------------------")
-			fmt.Printf("Amount Total: %v\n", _basket.Total)
+			if _basket.PricesIncludeTax {
+				fmt.Println("Taxes (included):")
+			} else {
+				fmt.Println("Taxes:")
+			}
+			for _, tax := range _basket.Taxes {
+				fmt.Printf("      %s %v%%      Net: %v      Tax: %v      Gross: %v\n",
+					tax.Category, tax.Rate, tax.Net, tax.Tax, tax.Gross)
+			}
+			fmt.Printf("Amount Total: %v %s\n", _basket.Total, _basket.Currency)
+			if _basket.PaymentCurrency != "" && _basket.PaymentCurrency != _basket.Currency {
+				fmt.Printf("Exchange Rate: %v\n", _basket.ExchangeRate)
+			}
+			if _basket.Rounding != 0 {
+				fmt.Printf("Cash Rounding: %v %s\n", _basket.Rounding, _basket.PaymentCurrency)
+			}
+			if _basket.PaymentCurrency != "" && (_basket.PaymentCurrency != _basket.Currency || _basket.Rounding != 0) {
+				fmt.Printf("Amount To Pay: %v %s\n", _basket.PaymentTotal, _basket.PaymentCurrency)
+			}
+		},
+	}
+
+	paymentCurrency := &cobra.Command{
+		Use:     "currency",
+		Short:   "set the currency a basket is paid in",
+		Example: "basket currency basket_id USD",
+		Args:    cobra.ExactValidArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			basketID := args[0]
+			currency := args[1]
+
+			if basketID == "" || currency == "" {
+				log.Panic("basket ID/currency is required")
+			}
+
+			url := fmt.Sprintf("http://localhost:8080/baskets/%s/currency/%s", basketID, currency)
+			request, err := http.NewRequest(http.MethodPut, url, nil)
+			if err != nil {
+				log.Panic("error building a http client")
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				log.Panic("error building a http client")
+			}
+
+			defer response.Body.Close()
+			bodyResp, err := io.ReadAll(response.Body)
+
+			if response.StatusCode == http.StatusBadRequest {
+				fmt.Println(string(bodyResp))
+				return
+			}
+
+			fmt.Printf("basket paid in %s\n", currency)
+		},
+	}
+
+	tender := &cobra.Command{
+		Use:     "tender",
+		Short:   "set how a basket is paid, cash or card",
+		Example: "basket tender basket_id cash",
+		Args:    cobra.ExactValidArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			basketID := args[0]
+			tender := args[1]
+
+			if basketID == "" || tender == "" {
+				log.Panic("basket ID/tender is required")
+			}
+
+			url := fmt.Sprintf("http://localhost:8080/baskets/%s/tender/%s", basketID, tender)
+			request, err := http.NewRequest(http.MethodPut, url, nil)
+			if err != nil {
+				log.Panic("error building a http client")
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				log.Panic("error building a http client")
+			}
+
+			defer response.Body.Close()
+			bodyResp, err := io.ReadAll(response.Body)
+
+			if response.StatusCode == http.StatusBadRequest {
+				fmt.Println(string(bodyResp))
+				return
+			}
+
+			fmt.Printf("basket paid by %s\n", tender)
 		},
 	}
 
-	basket.AddCommand(createBasket, removeBasket, addProductToBasket, checkoutBasket)
+	basket.AddCommand(createBasket, removeBasket, addProductToBasket, checkoutBasket, paymentCurrency, tender)
 
 	return basket
 }
@@ -8,7 +8,7 @@ import (
 
 func Execute() {
 	root := rootCmd()
-	root.AddCommand(clientCmd())
+	root.AddCommand(clientCmd(), catalogCmd())
 
 	if err := root.Execute(); err != nil {
 		log.Fatalln(err.Error())
@@ -0,0 +1,220 @@
+package cashRegister
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// AdjustStock record a change of the stock of a product out of the sales.
+// require a product code, the reason code and the quantity: the units received,
+// damaged or stolen, or the units counted for a count correction.
+// it will return the movement with the new balances if this is ok.
+// otherwise will return  error
+func (s Service) AdjustStock(ctx context.Context, code, reason string, quantity int, note string) (models.StockMovement, error) {
+	if s.inventory == nil {
+		return models.StockMovement{}, models.ErrInventoryDisabled
+	}
+
+	if !models.AdjustmentReasons[reason] || quantity < 0 ||
+		(quantity == 0 && reason != models.MovementCountCorrection) {
+		return models.StockMovement{}, models.ErrInvalidAdjustment
+	}
+
+	if err := s.trackedProduct(ctx, code); err != nil {
+		return models.StockMovement{}, err
+	}
+
+	movement := models.StockMovement{
+		ProductCode: code,
+		Type:        reason,
+		OnHandDelta: quantity,
+		Note:        note,
+		CreatedAt:   time.Now(),
+	}
+	switch reason {
+	case models.MovementDamage, models.MovementTheft:
+		movement.OnHandDelta = -quantity
+	case models.MovementCountCorrection:
+		// the delta is taken with the stock when the correction is applied,
+		// so the movements recorded meanwhile are not lost
+		return s.inventory.SetOnHand(ctx, movement, quantity)
+	}
+
+	return s.inventory.Move(ctx, movement)
+}
+
+// ListMovements return the movements of the stock of a product, oldest first.
+// require a product code
+// it will return the movements if this is ok.
+// otherwise will return  error
+func (s Service) ListMovements(ctx context.Context, code string) ([]models.StockMovement, error) {
+	if s.inventory == nil {
+		return nil, models.ErrInventoryDisabled
+	}
+
+	if _, err := s.catalog.FindProductByCode(ctx, code); err != nil {
+		return nil, err
+	}
+
+	return s.inventory.ListMovements(ctx, code)
+}
+
+// StartCycleCount open a physical count of some products.
+// require the codes of the products to count
+// it will return the cycle count if this is ok.
+// otherwise will return  error
+func (s Service) StartCycleCount(ctx context.Context, codes []string) (models.CycleCount, error) {
+	if s.inventory == nil {
+		return models.CycleCount{}, models.ErrInventoryDisabled
+	}
+
+	if len(codes) == 0 {
+		return models.CycleCount{}, models.ErrInvalidCycleCount
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return models.CycleCount{}, err
+	}
+
+	count := models.CycleCount{ID: id.String(), Status: models.CycleCountOpen, CreatedAt: time.Now()}
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if seen[code] {
+			return models.CycleCount{}, models.ErrInvalidCycleCount
+		}
+
+		if err = s.trackedProduct(ctx, code); err != nil {
+			return models.CycleCount{}, err
+		}
+
+		seen[code] = true
+		count.Lines = append(count.Lines, models.CountLine{ProductCode: code})
+	}
+
+	return s.inventory.SaveCycleCount(ctx, count)
+}
+
+// GetCycleCount return a cycle count.
+// require a cycle count id
+// it will return the cycle count if this is ok.
+// otherwise will return  error
+func (s Service) GetCycleCount(ctx context.Context, id string) (models.CycleCount, error) {
+	if s.inventory == nil {
+		return models.CycleCount{}, models.ErrInventoryDisabled
+	}
+
+	return s.inventory.FindCycleCount(ctx, id)
+}
+
+// CountProduct record the units counted of a product of a cycle count,
+// the units on hand are taken as the expected ones.
+// require a cycle count id, a product code and the units counted
+// it will return the cycle count if this is ok.
+// otherwise will return  error
+func (s Service) CountProduct(ctx context.Context, id, code string, counted int) (models.CycleCount, error) {
+	count, err := s.openCycleCount(ctx, id)
+	if err != nil {
+		return models.CycleCount{}, err
+	}
+
+	if counted < 0 {
+		return models.CycleCount{}, models.ErrInvalidCycleCount
+	}
+
+	lines := make([]models.CountLine, len(count.Lines))
+	copy(lines, count.Lines)
+	for i, line := range lines {
+		if line.ProductCode != code {
+			continue
+		}
+
+		stock, err := s.inventory.FindStock(ctx, code)
+		if err != nil {
+			return models.CycleCount{}, err
+		}
+
+		lines[i].Expected = stock.OnHand
+		lines[i].Counted = &counted
+		count.Lines = lines
+
+		return s.inventory.SaveCycleCount(ctx, count)
+	}
+
+	return models.CycleCount{}, models.ErrProductNotCounted
+}
+
+// PostCycleCount close a cycle count and post the variances of the products counted
+// as count corrections, the products not counted are left as they are.
+// require a cycle count id
+// it will return the cycle count if this is ok.
+// otherwise will return  error
+func (s Service) PostCycleCount(ctx context.Context, id string) (models.CycleCount, error) {
+	count, err := s.openCycleCount(ctx, id)
+	if err != nil {
+		return models.CycleCount{}, err
+	}
+
+	// the count is posted before its corrections, so a concurrent post
+	// of the same count fails instead of applying them twice
+	now := time.Now()
+	posted := count
+	posted.Status = models.CycleCountPosted
+	posted.PostedAt = now
+	if posted, err = s.inventory.SaveCycleCount(ctx, posted); err != nil {
+		return models.CycleCount{}, err
+	}
+
+	for _, line := range count.Lines {
+		if line.Variance() == 0 {
+			continue
+		}
+
+		_, err = s.inventory.Move(ctx, models.StockMovement{
+			ProductCode:  line.ProductCode,
+			Type:         models.MovementCountCorrection,
+			OnHandDelta:  line.Variance(),
+			CycleCountID: count.ID,
+			CreatedAt:    now,
+		})
+		if err != nil {
+			return models.CycleCount{}, err
+		}
+	}
+
+	return posted, nil
+}
+
+func (s Service) openCycleCount(ctx context.Context, id string) (models.CycleCount, error) {
+	if s.inventory == nil {
+		return models.CycleCount{}, models.ErrInventoryDisabled
+	}
+
+	count, err := s.inventory.FindCycleCount(ctx, id)
+	if err != nil {
+		return models.CycleCount{}, err
+	}
+
+	if count.Status != models.CycleCountOpen {
+		return models.CycleCount{}, models.ErrCycleCountPosted
+	}
+
+	return count, nil
+}
+
+// trackedProduct checks a product exists and its stock is tracked.
+func (s Service) trackedProduct(ctx context.Context, code string) error {
+	product, err := s.catalog.FindProductByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if product.IsWeighed() || product.IsKit() {
+		return models.ErrInvalidAdjustment
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package cashRegister
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestService_AdjustStock(t *testing.T) {
+	service, _ := newInventoryService(t, 10)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		reason     string
+		quantity   int
+		wantDelta  int
+		wantOnHand int
+		wantErr    error
+	}{
+		{name: "receipt", reason: models.MovementReceipt, quantity: 24, wantDelta: 24, wantOnHand: 34},
+		{name: "damage", reason: models.MovementDamage, quantity: 2, wantDelta: -2, wantOnHand: 32},
+		{name: "theft", reason: models.MovementTheft, quantity: 1, wantDelta: -1, wantOnHand: 31},
+		{name: "count correction", reason: models.MovementCountCorrection, quantity: 30, wantDelta: -1, wantOnHand: 30},
+		{name: "unknown reason", reason: "lost", quantity: 1, wantErr: models.ErrInvalidAdjustment},
+		{name: "no units", reason: models.MovementReceipt, quantity: 0, wantErr: models.ErrInvalidAdjustment},
+		{name: "negative units", reason: models.MovementDamage, quantity: -1, wantErr: models.ErrInvalidAdjustment},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movement, err := service.AdjustStock(ctx, models.Tshirt, tt.reason, tt.quantity, "")
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantDelta, movement.OnHandDelta)
+			assert.Equal(t, tt.wantOnHand, movement.OnHand)
+		})
+	}
+
+	_, err := service.AdjustStock(ctx, "DRESS", models.MovementReceipt, 1, "")
+	assert.Equal(t, models.ErrProductNotFound, err)
+
+	movements, err := service.ListMovements(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Len(t, movements, 4)
+}
+
+func TestService_CycleCount(t *testing.T) {
+	service, inventory := newInventoryService(t, 10)
+	ctx := context.Background()
+
+	_, err := service.StartCycleCount(ctx, []string{models.Tshirt, models.Tshirt})
+	assert.Equal(t, models.ErrInvalidCycleCount, err)
+
+	count, err := service.StartCycleCount(ctx, []string{models.Tshirt, models.Pants})
+	require.NoError(t, err)
+	assert.Equal(t, models.CycleCountOpen, count.Status)
+
+	count, err = service.CountProduct(ctx, count.ID, models.Tshirt, 8)
+	require.NoError(t, err)
+	assert.Equal(t, 10, count.Lines[0].Expected)
+	assert.Equal(t, -2, count.Lines[0].Variance())
+
+	_, err = service.CountProduct(ctx, count.ID, models.Voucher, 1)
+	assert.Equal(t, models.ErrProductNotCounted, err)
+
+	// a sale after the count is kept when the variance is posted
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+
+	count, err = service.PostCycleCount(ctx, count.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CycleCountPosted, count.Status)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, 7, stock.OnHand)
+
+	// the products not counted are not corrected
+	movements, err := inventory.ListMovements(ctx, models.Pants)
+	require.NoError(t, err)
+	assert.Empty(t, movements)
+
+	_, err = service.CountProduct(ctx, count.ID, models.Tshirt, 7)
+	assert.Equal(t, models.ErrCycleCountPosted, err)
+
+	_, err = service.PostCycleCount(ctx, count.ID)
+	assert.Equal(t, models.ErrCycleCountPosted, err)
+}
+
+func TestService_PostCycleCount_Concurrent(t *testing.T) {
+	service, inventory := newInventoryService(t, 10)
+	ctx := context.Background()
+
+	count, err := service.StartCycleCount(ctx, []string{models.Tshirt})
+	require.NoError(t, err)
+	_, err = service.CountProduct(ctx, count.ID, models.Tshirt, 8)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var posted int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.PostCycleCount(ctx, count.ID)
+			if err == nil {
+				atomic.AddInt32(&posted, 1)
+				return
+			}
+
+			assert.Equal(t, models.ErrCycleCountPosted, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), posted)
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, 8, stock.OnHand)
+}
+
+func TestService_AdjustStock_ConcurrentCorrection(t *testing.T) {
+	service, inventory := newInventoryService(t, 10)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := service.AdjustStock(ctx, models.Tshirt, models.MovementReceipt, 1, "")
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := service.AdjustStock(ctx, models.Tshirt, models.MovementCountCorrection, 10, "")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// every movement takes the stock from the balance of the one before it
+	movements, err := inventory.ListMovements(ctx, models.Tshirt)
+	require.NoError(t, err)
+	onHand := 10
+	for _, movement := range movements {
+		onHand += movement.OnHandDelta
+		assert.Equal(t, onHand, movement.OnHand)
+		if movement.Type == models.MovementCountCorrection {
+			assert.Equal(t, 10, movement.OnHand)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package cashRegister
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// Approve record the approval of a manager for a restricted operation on a basket.
+// require a basket id, the operation and the manager id and pin
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) Approve(ctx context.Context, basketID, operation, managerID, pin string) (models.Basket, error) {
+	if !isRestricted(operation) {
+		return models.Basket{}, models.ErrInvalidOperation
+	}
+
+	if err := s.lockouts.authenticate(managerID, pin, time.Now()); err != nil {
+		return models.Basket{}, err
+	}
+
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	basket.Approvals = append(basket.Approvals, models.Approval{
+		Operation:  operation,
+		ManagerID:  managerID,
+		ApprovedAt: time.Now(),
+	})
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
+
+// useApproval marks as used a pending approval of the operation on the basket.
+// it returns an ApprovalRequiredError when the operation is restricted and there is none.
+func useApproval(basket *models.Basket, operation string) error {
+	if !isRestricted(operation) {
+		return nil
+	}
+
+	for i, approval := range basket.Approvals {
+		if approval.Operation == operation && approval.UsedAt.IsZero() {
+			approvals := append([]models.Approval{}, basket.Approvals...)
+			approvals[i].UsedAt = time.Now()
+			basket.Approvals = approvals
+			return nil
+		}
+	}
+
+	return &models.ApprovalRequiredError{Operation: operation}
+}
+
+func isRestricted(operation string) bool {
+	for _, restricted := range configRules.Approvals.Restricted {
+		if restricted == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// managers are the supervisors allowed to approve restricted operations,
+// there are none until LoadManagers loads them.
+var managers []Manager
+
+// LoadManagers function load the managers through a yaml file, with their ids
+// and the bcrypt hashes of their PINs. No manager can approve when path is empty.
+func LoadManagers(path string) error {
+	managers = nil
+	if path == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("couldn't read managers file.: %s", err)
+	}
+
+	var file struct {
+		Managers []Manager `yaml:"managers"`
+	}
+	err = yaml.Unmarshal(content, &file)
+	if err != nil {
+		return fmt.Errorf("couldn't parse managers file.: %s", err)
+	}
+
+	ids := make(map[string]bool)
+	for _, manager := range file.Managers {
+		if _, err = bcrypt.Cost([]byte(manager.PinHash)); err != nil || manager.ID == "" || ids[manager.ID] {
+			return models.ErrInvalidManagers
+		}
+
+		ids[manager.ID] = true
+	}
+
+	managers = file.Managers
+	return nil
+}
+
+func findManager(managerID string) (Manager, bool) {
+	for _, manager := range managers {
+		if manager.ID == managerID {
+			return manager, true
+		}
+	}
+
+	return Manager{}, false
+}
+
+// dummyPinHash is compared with the PINs of unknown managers,
+// so they take as long to check as the ones of the managers.
+var dummyPinHash, _ = bcrypt.GenerateFromPassword([]byte("0000"), bcrypt.DefaultCost)
+
+// lockouts counts the wrong PINs of each manager, after maxAttempts
+// in a row the manager is locked out until the lockout time passes.
+// Only the managers that are loaded are counted.
+type lockouts struct {
+	mux    sync.Mutex
+	failed map[string]int
+	until  map[string]time.Time
+}
+
+func newLockouts() *lockouts {
+	return &lockouts{failed: make(map[string]int), until: make(map[string]time.Time)}
+}
+
+// authenticate checks the PIN of a manager, it returns ErrManagerLockedOut while
+// the manager is locked out and ErrInvalidManagerCredential when the PIN is wrong.
+func (l *lockouts) authenticate(managerID, pin string, now time.Time) error {
+	manager, ok := findManager(managerID)
+	if !ok {
+		_ = bcrypt.CompareHashAndPassword(dummyPinHash, []byte(pin))
+		return models.ErrInvalidManagerCredential
+	}
+
+	if err := l.attempt(managerID, now); err != nil {
+		return err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(manager.PinHash), []byte(pin)) != nil {
+		l.fail(managerID, now)
+		return models.ErrInvalidManagerCredential
+	}
+
+	l.mux.Lock()
+	delete(l.failed, managerID)
+	l.mux.Unlock()
+
+	return nil
+}
+
+// attempt counts an attempt as failed before the PIN is checked, so the attempts
+// running at the same time can't go over maxAttempts. It also forgets the lockouts
+// that passed.
+func (l *lockouts) attempt(managerID string, now time.Time) error {
+	defer l.mux.Unlock()
+	l.mux.Lock()
+
+	for id, until := range l.until {
+		if !now.Before(until) {
+			delete(l.until, id)
+			delete(l.failed, id)
+		}
+	}
+
+	max := configRules.Approvals.MaxAttempts
+	if _, locked := l.until[managerID]; locked || (max > 0 && l.failed[managerID] >= max) {
+		return models.ErrManagerLockedOut
+	}
+
+	l.failed[managerID]++
+	return nil
+}
+
+// fail locks out the manager once the wrong PINs reach maxAttempts.
+func (l *lockouts) fail(managerID string, now time.Time) {
+	defer l.mux.Unlock()
+	l.mux.Lock()
+
+	if max := configRules.Approvals.MaxAttempts; max > 0 && l.failed[managerID] >= max {
+		l.until[managerID] = now.Add(configRules.Approvals.Lockout)
+	}
+}
@@ -0,0 +1,174 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func TestService_Approve(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+	require.NoError(t, LoadManagers("testdata/managers.yml"))
+
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"TSHIRT": {
+				Product:  models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000},
+				Quantity: 1,
+				Total:    2000,
+			},
+		},
+		Total: 2000,
+	}
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil).Once()
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+	service := NewService(RulesEngine, repositoryMock)
+
+	override := models.Override{Type: models.OverrideUnitPrice, Amount: 1000, Reason: models.ReasonDamaged}
+	_, err = service.OverrideItem(context.Background(), basketMock.Code, "TSHIRT", override)
+	assert.True(t, errors.Is(err, models.ErrApprovalRequired))
+	assert.EqualError(t, err, "manager approval required: override")
+
+	_, err = service.Approve(context.Background(), basketMock.Code, models.OperationOverride, "M-001", "0000")
+	assert.Equal(t, models.ErrInvalidManagerCredential, err)
+
+	_, err = service.Approve(context.Background(), basketMock.Code, "checkout", "M-001", "1234")
+	assert.Equal(t, models.ErrInvalidOperation, err)
+
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil).Once()
+	approved, err := service.Approve(context.Background(), basketMock.Code, models.OperationOverride, "M-001", "1234")
+	require.NoError(t, err)
+	require.Len(t, approved.Approvals, 1)
+	assert.Equal(t, "M-001", approved.Approvals[0].ManagerID)
+	assert.True(t, approved.Approvals[0].UsedAt.IsZero())
+
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(approved, nil).Once()
+	basket, err := service.OverrideItem(context.Background(), basketMock.Code, "TSHIRT", override)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(1000), basket.Total)
+	assert.False(t, basket.Approvals[0].UsedAt.IsZero())
+
+	// an approval is used only once
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basket, nil).Once()
+	_, err = service.OverrideItem(context.Background(), basketMock.Code, "TSHIRT", override)
+	assert.True(t, errors.Is(err, models.ErrApprovalRequired))
+	assert.True(t, approved.Approvals[0].UsedAt.IsZero())
+}
+
+func TestService_RemoveProduct_ApprovalRequired(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"TSHIRT": {
+				Product:  models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000},
+				Quantity: 1,
+				Total:    2000,
+			},
+		},
+		Total: 2000,
+	}
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	service := NewService(RulesEngine, repositoryMock)
+
+	_, err = service.RemoveProduct(context.Background(), basketMock.Code, "TSHIRT")
+	assert.Equal(t, &models.ApprovalRequiredError{Operation: models.OperationVoid}, err)
+	repositoryMock.AssertNotCalled(t, "RemoveProduct", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_Approve_Lockout(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadManagers("testdata/managers.yml"))
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(models.Basket{Code: "1"}, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+	service := NewService(RulesEngine, repositoryMock)
+	ctx := context.Background()
+
+	// a right PIN resets the wrong ones
+	_, err := service.Approve(ctx, "1", models.OperationVoid, "M-001", "0000")
+	assert.Equal(t, models.ErrInvalidManagerCredential, err)
+	_, err = service.Approve(ctx, "1", models.OperationVoid, "M-001", "1234")
+	require.NoError(t, err)
+
+	for i := 0; i < configRules.Approvals.MaxAttempts; i++ {
+		_, err = service.Approve(ctx, "1", models.OperationVoid, "M-001", "0000")
+		assert.Equal(t, models.ErrInvalidManagerCredential, err)
+	}
+
+	// the manager is locked out even with the right PIN
+	_, err = service.Approve(ctx, "1", models.OperationVoid, "M-001", "1234")
+	assert.Equal(t, models.ErrManagerLockedOut, err)
+
+	// the lockouts that passed are forgotten
+	now := time.Now().Add(configRules.Approvals.Lockout)
+	assert.NoError(t, service.lockouts.authenticate("M-001", "1234", now))
+	assert.Empty(t, service.lockouts.until)
+
+	// unknown managers are not counted
+	_, err = service.Approve(ctx, "1", models.OperationVoid, "M-002", "1234")
+	assert.Equal(t, models.ErrInvalidManagerCredential, err)
+	assert.NotContains(t, service.lockouts.failed, "M-002")
+}
+
+func TestLockouts_Authenticate_Concurrent(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadManagers("testdata/managers.yml"))
+
+	lockouts := newLockouts()
+	now := time.Now()
+
+	var wrong int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if lockouts.authenticate("M-001", "0000", now) == models.ErrInvalidManagerCredential {
+				atomic.AddInt32(&wrong, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// only maxAttempts PINs are checked, the others are locked out
+	assert.Equal(t, int32(configRules.Approvals.MaxAttempts), wrong)
+	assert.Equal(t, models.ErrManagerLockedOut, lockouts.authenticate("M-001", "1234", now))
+}
+
+func TestLoadManagers(t *testing.T) {
+	defer func() { require.NoError(t, LoadManagers("testdata/managers.yml")) }()
+
+	require.NoError(t, LoadManagers(""))
+	assert.Empty(t, managers)
+
+	path := filepath.Join(t.TempDir(), "managers.yml")
+	err := os.WriteFile(path, []byte("managers:\n  - id: M-001\n    pinHash: 1234\n"), 0o600)
+	require.NoError(t, err)
+	assert.Equal(t, models.ErrInvalidManagers, LoadManagers(path))
+
+	assert.Error(t, LoadManagers(filepath.Join(t.TempDir(), "missing.yml")))
+}
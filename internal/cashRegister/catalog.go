@@ -0,0 +1,367 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/catalog"
+	catalogmemory "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// defaultCatalog returns a memory catalog with the products of models.ProductMap.
+func defaultCatalog() catalog.ProductRepository {
+	products := make([]models.Product, 0, len(models.ProductMap))
+	for _, product := range models.ProductMap {
+		products = append(products, product)
+	}
+
+	return catalogmemory.NewProductRepository(products...)
+}
+
+// CreateProduct add a new active product to the catalog.
+// require a product with code, name and price
+// it will return the product if this is ok.
+// otherwise will return error
+func (s Service) CreateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	if err := s.validateProduct(ctx, &product); err != nil {
+		return models.Product{}, err
+	}
+
+	product.Active = true
+
+	return s.catalog.CreateProduct(ctx, product)
+}
+
+// GetProduct return a product of the catalog.
+// require a product code
+// it will return the product if this is ok.
+// otherwise will return error
+func (s Service) GetProduct(ctx context.Context, code string) (models.Product, error) {
+	product, err := s.catalog.FindProductByCode(ctx, code)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	return s.priceAt(ctx, product, time.Now())
+}
+
+// ListProducts return the products of the catalog sorted by code, with their prices in force,
+// the deactivated ones are only included when asked.
+func (s Service) ListProducts(ctx context.Context, includeInactive bool) ([]models.Product, error) {
+	products, err := s.currentProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if includeInactive {
+		return products, nil
+	}
+
+	active := make([]models.Product, 0, len(products))
+	for _, product := range products {
+		if product.Active {
+			active = append(active, product)
+		}
+	}
+
+	return active, nil
+}
+
+// currentProducts returns all the products of the catalog with their prices in force.
+func (s Service) currentProducts(ctx context.Context) ([]models.Product, error) {
+	products, err := s.catalog.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range products {
+		if products[i], err = s.priceAt(ctx, products[i], now); err != nil {
+			return nil, err
+		}
+	}
+
+	return products, nil
+}
+
+// UpdateProduct replace the details of a product of the catalog.
+// require a product with code, name and price
+// it will return the product if this is ok.
+// otherwise will return error
+func (s Service) UpdateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	if err := s.validateProduct(ctx, &product); err != nil {
+		return models.Product{}, err
+	}
+
+	// the catalog checks the barcodes again as it stores the product,
+	// so the price is only changed once it's stored.
+	updated, err := s.catalog.UpdateProduct(ctx, product)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	if err = s.repriceNow(ctx, updated); err != nil {
+		return models.Product{}, err
+	}
+
+	return updated, nil
+}
+
+// DeactivateProduct remove a product from sale without deleting it,
+// the baskets that already have it keep their lines.
+// require a product code
+// it will return the product if this is ok.
+// otherwise will return error
+func (s Service) DeactivateProduct(ctx context.Context, code string) (models.Product, error) {
+	product, err := s.catalog.FindProductByCode(ctx, code)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	product.Active = false
+
+	return s.catalog.UpdateProduct(ctx, product)
+}
+
+// ImportProducts create, or update on upsert, the products of an import file.
+// require the format, csv or json, and the file
+// it will return the result with the errors of every row if the file could be read.
+// The products are only written when all the rows are valid and it's not a dry run.
+// otherwise will return error
+func (s Service) ImportProducts(ctx context.Context, format string, r io.Reader, opts models.ImportOptions) (models.ImportResult, error) {
+	rows, err := catalog.Read(format, r)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	// the rows are validated against a copy of the catalog with the rows before
+	// them, so a variant or a kit can follow its parent or components in the file.
+	current, err := s.catalog.ListProducts(ctx)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	staged := s
+	staged.catalog = catalogmemory.NewProductRepository(current...)
+
+	result := models.ImportResult{DryRun: opts.DryRun}
+	products := make([]models.Product, 0, len(rows))
+	existing := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		product, exists, err := staged.importRow(ctx, row, opts, seen)
+		if errors.Is(err, errImportFailed) {
+			return models.ImportResult{}, err
+		}
+
+		if err != nil {
+			result.Errors = append(result.Errors, models.ImportError{
+				Row:   row.Line,
+				Code:  row.Product.Code,
+				Error: err.Error(),
+			})
+			continue
+		}
+
+		if exists {
+			_, err = staged.catalog.UpdateProduct(ctx, product)
+			result.Updated++
+		} else {
+			_, err = staged.catalog.CreateProduct(ctx, product)
+			result.Created++
+		}
+
+		if err != nil {
+			return models.ImportResult{}, err
+		}
+
+		seen[product.Code] = true
+		existing[product.Code] = exists
+		products = append(products, product)
+	}
+
+	if opts.DryRun || len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	for _, product := range products {
+		if existing[product.Code] {
+			if err = s.repriceNow(ctx, product); err != nil {
+				return models.ImportResult{}, err
+			}
+
+			_, err = s.catalog.UpdateProduct(ctx, product)
+		} else {
+			_, err = s.catalog.CreateProduct(ctx, product)
+		}
+
+		if err != nil {
+			return models.ImportResult{}, err
+		}
+	}
+
+	result.Applied = true
+	return result, nil
+}
+
+// errImportFailed wraps the errors of the catalog that stop an import.
+var errImportFailed = errors.New("import failed")
+
+// importRow returns the product to write for a row of an import file
+// and whether it already exists in the catalog.
+func (s Service) importRow(ctx context.Context, row catalog.Row, opts models.ImportOptions, seen map[string]bool) (models.Product, bool, error) {
+	if row.Err != nil {
+		return models.Product{}, false, row.Err
+	}
+
+	if seen[row.Product.Code] {
+		return models.Product{}, false, models.ErrDuplicatedRow
+	}
+
+	product, err := s.catalog.FindProductByCode(ctx, row.Product.Code)
+	exists := err == nil
+	switch {
+	case errors.Is(err, models.ErrProductNotFound):
+		product = row.Product
+	case err != nil:
+		return models.Product{}, false, fmt.Errorf("%w: %s", errImportFailed, err)
+	case !opts.Upsert:
+		return models.Product{}, false, models.ErrProductCreated
+	default:
+		// the fields that are not in the file are kept
+		product = row.Update(product)
+	}
+
+	if err = s.validateProduct(ctx, &product); err != nil {
+		return models.Product{}, false, err
+	}
+
+	return product, exists, nil
+}
+
+// ExportProducts write all the products of the catalog, active or not,
+// with their prices in force, in the given format, csv or json.
+// The variants follow the other products and the kits go last,
+// so the file can be imported back in order.
+func (s Service) ExportProducts(ctx context.Context, format string, w io.Writer) error {
+	products, err := s.currentProducts(ctx)
+	if err != nil {
+		return err
+	}
+
+	rank := func(p models.Product) int {
+		switch {
+		case p.IsKit():
+			return 2
+		case p.Parent != "":
+			return 1
+		default:
+			return 0
+		}
+	}
+	sort.SliceStable(products, func(i, j int) bool {
+		return rank(products[i]) < rank(products[j])
+	})
+
+	return catalog.Write(format, w, products)
+}
+
+// findProduct returns the product of a product code or of a barcode,
+// the codes with only digits are read as barcodes.
+func (s Service) findProduct(ctx context.Context, code string) (models.Product, error) {
+	if !models.IsBarcode(code) {
+		return s.catalog.FindProductByCode(ctx, code)
+	}
+
+	gtin, err := models.ParseGTIN(code)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	product, err := s.catalog.FindProductByBarcode(ctx, gtin)
+	if errors.Is(err, models.ErrProductNotFound) {
+		return models.Product{}, models.ErrUnknownBarcode
+	}
+
+	return product, err
+}
+
+// validateProduct checks the fields of a product and
+// keeps its barcodes as GTIN-13s that no other product has.
+func (s Service) validateProduct(ctx context.Context, product *models.Product) error {
+	if product.Code == "" || product.Name == "" || product.Price < 0 || models.IsBarcode(product.Code) {
+		return models.ErrInvalidProduct
+	}
+
+	if err := s.validateBarcodes(ctx, product); err != nil {
+		return err
+	}
+
+	if product.Currency != "" && !models.Currencies[product.Currency] {
+		return models.ErrInvalidCurrency
+	}
+
+	if product.TaxCategory != "" && !models.TaxCategories[product.TaxCategory] {
+		return models.ErrInvalidProduct
+	}
+
+	if product.Unit != "" && product.Unit != models.UnitEach && product.Unit != models.UnitKg {
+		return models.ErrInvalidProduct
+	}
+
+	if product.PLU != "" && (len(product.PLU) != 5 || !models.IsBarcode(product.PLU) || !product.IsWeighed()) {
+		return models.ErrInvalidProduct
+	}
+
+	if product.IsKit() {
+		return s.validateKit(ctx, *product)
+	}
+
+	if product.Parent != "" {
+		return s.validateVariant(ctx, *product)
+	}
+
+	return nil
+}
+
+// validateBarcodes keeps the barcodes of a product as GTIN-13s,
+// once each, and checks no other product of the catalog has them.
+// The catalog checks them again as it stores the product.
+func (s Service) validateBarcodes(ctx context.Context, product *models.Product) error {
+	if len(product.Barcodes) == 0 {
+		return nil
+	}
+
+	gtins := make([]string, 0, len(product.Barcodes))
+	seen := make(map[string]bool)
+	for _, barcode := range product.Barcodes {
+		gtin, err := models.ParseGTIN(barcode)
+		if err != nil {
+			return err
+		}
+
+		if seen[gtin] {
+			continue
+		}
+
+		other, err := s.catalog.FindProductByBarcode(ctx, gtin)
+		if err == nil && other.Code != product.Code {
+			return models.ErrBarcodeInUse
+		}
+
+		if err != nil && !errors.Is(err, models.ErrProductNotFound) {
+			return err
+		}
+
+		seen[gtin] = true
+		gtins = append(gtins, gtin)
+	}
+
+	product.Barcodes = gtins
+	return nil
+}
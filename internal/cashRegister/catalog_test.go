@@ -0,0 +1,323 @@
+package cashRegister
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/catalog"
+	"github.com/patriciabonaldy/cash_register/internal/catalog/catalogmocks"
+	catalogmemory "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func TestService_CreateProduct(t *testing.T) {
+	tests := []struct {
+		name    string
+		product models.Product
+		wantErr error
+	}{
+		{name: "valid product", product: models.Product{Code: "SOCKS", Name: "Summer Socks", Price: 450}},
+		{name: "without name", product: models.Product{Code: "SOCKS", Price: 450}, wantErr: models.ErrInvalidProduct},
+		{name: "negative price", product: models.Product{Code: "SOCKS", Name: "Summer Socks", Price: -1}, wantErr: models.ErrInvalidProduct},
+		{name: "unknown currency", product: models.Product{Code: "SOCKS", Name: "Summer Socks", Currency: "JPY"}, wantErr: models.ErrInvalidCurrency},
+		{name: "unknown tax category", product: models.Product{Code: "SOCKS", Name: "Summer Socks", TaxCategory: "luxury"}, wantErr: models.ErrInvalidProduct},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalogMock := new(catalogmocks.ProductRepository)
+			catalogMock.On("CreateProduct", mock.Anything, mock.Anything).
+				Return(func(_ context.Context, product models.Product) models.Product { return product }, nil)
+
+			service := NewService(RulesEngine, nil, WithCatalog(catalogMock))
+			product, err := service.CreateProduct(context.Background(), tt.product)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, product.Active)
+		})
+	}
+}
+
+func TestService_DeactivateProduct(t *testing.T) {
+	service := NewService(RulesEngine, nil, WithCatalog(catalogmemory.NewProductRepository(
+		models.ProductMap[models.Tshirt],
+		models.ProductMap[models.Pants],
+	)))
+
+	product, err := service.DeactivateProduct(context.Background(), models.Tshirt)
+	require.NoError(t, err)
+	assert.False(t, product.Active)
+
+	products, err := service.ListProducts(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Product{models.ProductMap[models.Pants]}, products)
+
+	products, err = service.ListProducts(context.Background(), true)
+	require.NoError(t, err)
+	assert.Len(t, products, 2)
+
+	_, err = service.DeactivateProduct(context.Background(), "SOCKS")
+	assert.Equal(t, models.ErrProductNotFound, err)
+}
+
+func TestService_AddProduct_Catalog(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+
+	socks := models.Product{Code: "SOCKS", Name: "Summer Socks", Price: 450, Active: true}
+	inactive := models.Product{Code: "HAT", Name: "Summer Hat", Price: 1000}
+
+	basketMock := models.Basket{Code: "1", Items: map[string]models.Item{}}
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("GetItem", mock.Anything, mock.Anything, mock.Anything).Return(models.Item{}, models.ErrItemNotFound)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock,
+		WithCatalog(catalogmemory.NewProductRepository(socks, inactive)))
+
+	basket, err := service.AddProduct(context.Background(), "1", "SOCKS")
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(450), basket.Items["SOCKS"].Total)
+
+	_, err = service.AddProduct(context.Background(), "1", "HAT")
+	assert.Equal(t, models.ErrProductInactive, err)
+
+	_, err = service.AddProduct(context.Background(), "1", models.Tshirt)
+	assert.Equal(t, models.ErrProductNotFound, err)
+}
+
+func TestService_ImportProducts(t *testing.T) {
+	file := "code,name,price,category\n" +
+		"SOCKS,Summer Socks,4.50,apparel\n" +
+		"TSHIRT,Summer T-Shirt,21.00,apparel\n"
+
+	tests := []struct {
+		name        string
+		file        string
+		opts        models.ImportOptions
+		want        models.ImportResult
+		wantTshirt  models.Money
+		wantCreated bool
+	}{
+		{
+			name:       "existing product without upsert",
+			file:       file,
+			want:       models.ImportResult{Created: 1, Errors: []models.ImportError{{Row: 3, Code: "TSHIRT", Error: models.ErrProductCreated.Error()}}},
+			wantTshirt: 2000,
+		},
+		{
+			name:        "upsert",
+			file:        file,
+			opts:        models.ImportOptions{Upsert: true},
+			want:        models.ImportResult{Applied: true, Created: 1, Updated: 1},
+			wantTshirt:  2100,
+			wantCreated: true,
+		},
+		{
+			name:       "dry run",
+			file:       file,
+			opts:       models.ImportOptions{Upsert: true, DryRun: true},
+			want:       models.ImportResult{DryRun: true, Created: 1, Updated: 1},
+			wantTshirt: 2000,
+		},
+		{
+			name: "invalid rows",
+			file: "code,name,price\nSOCKS,,4.50\nHAT,Summer Hat,1\nHAT,Summer Hat,2\n",
+			opts: models.ImportOptions{Upsert: true},
+			want: models.ImportResult{Created: 1, Errors: []models.ImportError{
+				{Row: 2, Code: "SOCKS", Error: models.ErrInvalidProduct.Error()},
+				{Row: 4, Code: "HAT", Error: models.ErrDuplicatedRow.Error()},
+			}},
+			wantTshirt: 2000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewService(RulesEngine, nil, WithCatalog(catalogmemory.NewProductRepository(models.ProductMap[models.Tshirt])))
+
+			result, err := service.ImportProducts(context.Background(), "csv", strings.NewReader(tt.file), tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+
+			tshirt, err := service.GetProduct(context.Background(), models.Tshirt)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTshirt, tshirt.Price)
+			assert.Equal(t, models.TaxStandard, tshirt.TaxCategory)
+
+			_, err = service.GetProduct(context.Background(), "SOCKS")
+			assert.Equal(t, tt.wantCreated, err == nil)
+		})
+	}
+}
+
+func TestService_ExportProducts(t *testing.T) {
+	service := NewService(RulesEngine, nil, WithCatalog(catalogmemory.NewProductRepository(
+		models.ProductMap[models.Tshirt],
+		models.ProductMap[models.Pants],
+	)))
+
+	var buf bytes.Buffer
+	err := service.ExportProducts(context.Background(), "csv", &buf)
+	require.NoError(t, err)
+	assert.Equal(t, "code,name,price,category,barcodes,currency,tax_category,unit,plu,parent,size,colour,components,active\n"+
+		"PANTS,Summer Pants ,7.50,,8412345000034,EUR,standard,,,,,,,true\n"+
+		"TSHIRT,Summer T-Shirt,20.00,,8412345000027,EUR,standard,,,,,,,true\n", buf.String())
+}
+
+func TestService_ExportProducts_RoundTrip(t *testing.T) {
+	products := []models.Product{
+		{Code: "APPLES", Name: "Golden Apples", Price: 299, Currency: models.EUR, TaxCategory: models.TaxReduced,
+			Unit: models.UnitKg, PLU: "20001", Active: true},
+		{Code: "HAT", Name: "Summer Hat", Price: 1000, Currency: models.USD, TaxCategory: models.TaxStandard},
+		{Code: "AKIT", Name: "Summer Kit", Price: 2500, Currency: models.EUR, TaxCategory: models.TaxStandard, Active: true,
+			Components: []models.Component{{ProductCode: "HAT", Quantity: 1}, {ProductCode: "BTSHIRT-S", Quantity: 2}}},
+		{Code: "BTSHIRT", Name: "Summer T-Shirt", Price: 2000, Currency: models.EUR, TaxCategory: models.TaxStandard, Active: true},
+		{Code: "BTSHIRT-S", Name: "Summer T-Shirt S", Currency: models.EUR, TaxCategory: models.TaxStandard, Active: true,
+			Parent: "BTSHIRT", Size: "S", Colour: "white"},
+	}
+
+	for _, format := range []string{"csv", "json"} {
+		t.Run(format, func(t *testing.T) {
+			source := NewService(RulesEngine, nil, WithCatalog(catalogmemory.NewProductRepository(products...)))
+			var buf bytes.Buffer
+			require.NoError(t, source.ExportProducts(context.Background(), format, &buf))
+
+			// the variant and the kit are imported after their parent and components
+			target := NewService(RulesEngine, nil, WithCatalog(catalogmemory.NewProductRepository()))
+			result, err := target.ImportProducts(context.Background(), format, &buf, models.ImportOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, models.ImportResult{Applied: true, Created: len(products)}, result)
+
+			want, err := source.ListProducts(context.Background(), true)
+			require.NoError(t, err)
+			got, err := target.ListProducts(context.Background(), true)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestService_ImportProducts_KeepsFields(t *testing.T) {
+	hat := models.Product{Code: "HAT", Name: "Summer Hat", Price: 1000, Currency: models.EUR,
+		TaxCategory: models.TaxReduced, Size: "M"}
+	service := NewService(RulesEngine, nil, WithCatalog(catalogmemory.NewProductRepository(hat)))
+
+	file := "code,name,price\nHAT,Summer Hat,12.00\n"
+	result, err := service.ImportProducts(context.Background(), "csv", strings.NewReader(file), models.ImportOptions{Upsert: true})
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportResult{Applied: true, Updated: 1}, result)
+
+	product, err := service.GetProduct(context.Background(), "HAT")
+	require.NoError(t, err)
+	hat.Price = 1200
+	assert.Equal(t, hat, product)
+}
+
+func TestService_AddProduct_Barcode(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+
+	basketMock := models.Basket{Code: "1", Items: map[string]models.Item{}}
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("GetItem", mock.Anything, mock.Anything, models.Tshirt).Return(models.Item{}, models.ErrItemNotFound)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+
+	basket, err := service.AddProduct(context.Background(), "1", "8412345000027")
+	require.NoError(t, err)
+	assert.Equal(t, 1, basket.Items[models.Tshirt].Quantity)
+
+	_, err = service.AddProduct(context.Background(), "1", "8412345000028")
+	assert.Equal(t, models.ErrMalformedBarcode, err)
+
+	_, err = service.AddProduct(context.Background(), "1", "4006381333931")
+	assert.Equal(t, models.ErrUnknownBarcode, err)
+}
+
+func TestService_CreateProduct_Barcode(t *testing.T) {
+	service := NewService(RulesEngine, nil)
+
+	product, err := service.CreateProduct(context.Background(), models.Product{
+		Code: "SOCKS", Name: "Summer Socks", Price: 450, Barcodes: []string{"036000291452", "4006381333931", "0036000291452"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0036000291452", "4006381333931"}, product.Barcodes)
+
+	// every barcode of the product finds it
+	for _, barcode := range product.Barcodes {
+		found, err := service.findProduct(context.Background(), barcode)
+		require.NoError(t, err)
+		assert.Equal(t, "SOCKS", found.Code)
+	}
+
+	_, err = service.CreateProduct(context.Background(), models.Product{
+		Code: "HAT", Name: "Summer Hat", Price: 1000, Barcodes: []string{"4012345000016", "8412345000027"},
+	})
+	assert.Equal(t, models.ErrBarcodeInUse, err)
+
+	_, err = service.CreateProduct(context.Background(), models.Product{
+		Code: "HAT", Name: "Summer Hat", Price: 1000, Barcodes: []string{"12345"},
+	})
+	assert.Equal(t, models.ErrMalformedBarcode, err)
+}
+
+// barcodeBarrier makes the lookups of a barcode wait until all of them are made.
+type barcodeBarrier struct {
+	catalog.ProductRepository
+	sync.WaitGroup
+}
+
+func (r *barcodeBarrier) FindProductByBarcode(ctx context.Context, barcode string) (models.Product, error) {
+	product, err := r.ProductRepository.FindProductByBarcode(ctx, barcode)
+	r.Done()
+	r.Wait()
+
+	return product, err
+}
+
+func TestService_CreateProduct_ConcurrentBarcode(t *testing.T) {
+	service := NewService(RulesEngine, nil)
+	ctx := context.Background()
+
+	// every create checks the barcode before any of them stores its product
+	lookups := &barcodeBarrier{ProductRepository: service.catalog}
+	lookups.Add(10)
+	service.catalog = lookups
+
+	var wg sync.WaitGroup
+	var created int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := service.CreateProduct(ctx, models.Product{
+				Code: fmt.Sprintf("SOCKS-%d", i), Name: "Summer Socks", Price: 450, Barcodes: []string{"4006381333931"},
+			})
+			if err == nil {
+				atomic.AddInt32(&created, 1)
+				return
+			}
+
+			assert.Equal(t, models.ErrBarcodeInUse, err)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), created)
+}
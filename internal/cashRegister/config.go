@@ -3,6 +3,7 @@ package cashRegister
 import (
 	_ "embed"
 	"fmt"
+	"time"
 
 	"github.com/patriciabonaldy/cash_register/internal/models"
 
@@ -14,24 +15,134 @@ var data []byte
 
 // Config represents the structure to store all about limit configuration.
 type Config struct {
-	Rules rules `yaml:"rules"`
+	Rules           rules           `yaml:"rules"`
+	Experiments     experiments     `yaml:"experiments"`
+	Loyalty         Loyalty         `yaml:"loyalty"`
+	Employee        Employee        `yaml:"employeeDiscount"`
+	Approvals       Approvals       `yaml:"approvals"`
+	Taxes           Taxes           `yaml:"taxes"`
+	CashRounding    CashRounding    `yaml:"cashRounding"`
+	VariableMeasure VariableMeasure `yaml:"variableMeasure"`
+	Inventory       Inventory       `yaml:"inventory"`
+	Limits          Limits          `yaml:"limits"`
+	Baskets         Baskets         `yaml:"baskets"`
 }
 
 type (
-	ruleName string
-	rules    map[ruleName]Rule
+	ruleName    string
+	rules       map[ruleName]Rule
+	experiments map[string]Experiment
 )
 
 // Rule represents the structure to store the details of a rule by default.
 type Rule struct {
-	Name     ruleName `yaml:"name"`
-	Desc     string   `yaml:"desc"`
-	Product  string   `yaml:"product"`
-	Quantity int      `yaml:"quantity"`
-	NewPrice float64  `yaml:"newPrice,omitempty"`
+	Name     ruleName     `yaml:"name"`
+	Desc     string       `yaml:"desc"`
+	Product  string       `yaml:"product"`
+	Quantity int          `yaml:"quantity"`
+	NewPrice models.Money `yaml:"newPrice,omitempty"`
 	fn       func(item models.Item, rule Rule) models.Item
 }
 
+// Experiment represents an A/B test over the parameters of a rule.
+// Baskets are split between its variants according to their weights.
+type Experiment struct {
+	Name     string    `yaml:"name"`
+	Desc     string    `yaml:"desc"`
+	Rule     ruleName  `yaml:"rule"`
+	Active   bool      `yaml:"active"`
+	Variants []Variant `yaml:"variants"`
+}
+
+// Variant represents the values of a rule that are overridden
+// for the baskets assigned to it.
+type Variant struct {
+	Name     string       `yaml:"name"`
+	Weight   int          `yaml:"weight"`
+	NewPrice models.Money `yaml:"newPrice,omitempty"`
+}
+
+// Loyalty represents how points are earned and redeemed.
+// Points earned are the euros paid by pointsPerEuro and the multiplier of the customer tier,
+// each redeemed point is worth pointValue.
+type Loyalty struct {
+	PointsPerEuro float64            `yaml:"pointsPerEuro"`
+	PointValue    models.Money       `yaml:"pointValue"`
+	Tiers         map[string]float64 `yaml:"tiers"`
+}
+
+// Employee represents the discount of staff purchases.
+// Lines where any of the excluded rules applies keep the promotion
+// and don't get the employee discount on top.
+type Employee struct {
+	Percent       float64    `yaml:"percent"`
+	ExcludedRules []ruleName `yaml:"excludedRules"`
+}
+
+// Approvals represents the operations that require a manager,
+// the managers allowed to approve them are loaded by LoadManagers.
+// Refunds only require approval when they are over largeRefund.
+// A manager is locked out for lockout after maxAttempts wrong PINs in a row.
+type Approvals struct {
+	Restricted  []string      `yaml:"restricted"`
+	LargeRefund models.Money  `yaml:"largeRefund"`
+	MaxAttempts int           `yaml:"maxAttempts"`
+	Lockout     time.Duration `yaml:"lockout"`
+}
+
+// Manager represents a supervisor, only the bcrypt hash of the PIN is kept.
+type Manager struct {
+	ID      string `yaml:"id"`
+	Name    string `yaml:"name"`
+	PinHash string `yaml:"pinHash"`
+}
+
+// Taxes represents the VAT rate in percent of each tax category
+// and whether the prices of the products already include it.
+type Taxes struct {
+	PricesIncludeTax bool               `yaml:"pricesIncludeTax"`
+	Rates            map[string]float64 `yaml:"rates"`
+}
+
+// CashRounding represents how the payable total is rounded at checkout,
+// to the nearest increment, for cash payments only, always or never.
+type CashRounding struct {
+	Increment models.Money `yaml:"increment"`
+	Policy    string       `yaml:"policy"`
+}
+
+// VariableMeasure represents the in-store EAN-13 barcodes printed by the scales,
+// 2X IIIII VVVVV C: the prefix, the PLU of the product, the embedded value and the check digit.
+// The value is the weight in grams for the weight prefixes and the price in cents for the price ones.
+type VariableMeasure struct {
+	WeightPrefixes []string `yaml:"weightPrefixes"`
+	PricePrefixes  []string `yaml:"pricePrefixes"`
+}
+
+// Inventory represents what to do when a product has not enough stock,
+// block the sale or allow a negative stock, and how long the units added
+// to an open basket are held for it.
+type Inventory struct {
+	Policy         string        `yaml:"policy"`
+	ReservationTTL time.Duration `yaml:"reservationTTL"`
+}
+
+// Limits represents the purchase limits of a basket: the units of a product by code,
+// which count the units of its variants too, the units of the products without
+// a limit of their own, and the total over which a manager must approve the basket.
+// Zero is no limit.
+type Limits struct {
+	Products      map[string]int `yaml:"products"`
+	MaxUnits      int            `yaml:"maxUnits"`
+	ApprovalTotal models.Money   `yaml:"approvalTotal"`
+}
+
+// Baskets represents how long an open basket can go without scans before
+// it's expired. Zero never expires them.
+type Baskets struct {
+	ExpireAfter time.Duration `yaml:"expireAfter"`
+}
+
 // configRules are by default
 var configRules Config
 
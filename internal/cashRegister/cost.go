@@ -0,0 +1,184 @@
+package cashRegister
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// ScheduleCostChange set a new cost price of a product from a given time on.
+// require a product code, the cost and the time it takes effect, now when it's zero.
+// it will return the cost change if this is ok.
+// otherwise will return  error
+func (s Service) ScheduleCostChange(ctx context.Context, change models.CostChange) (models.CostChange, error) {
+	now := time.Now()
+	if change.EffectiveFrom.IsZero() {
+		change.EffectiveFrom = now
+	}
+
+	if change.Cost < 0 || change.EffectiveFrom.Before(now.Add(-time.Minute)) {
+		return models.CostChange{}, models.ErrInvalidCostChange
+	}
+
+	if _, err := s.catalog.FindProductByCode(ctx, change.ProductCode); err != nil {
+		return models.CostChange{}, err
+	}
+
+	change.CreatedAt = now
+
+	return s.catalog.SaveCostChange(ctx, change)
+}
+
+// CostHistory return the cost changes of a product, the past and the scheduled ones,
+// sorted by the time they take effect.
+// require a product code
+// it will return the cost changes if this is ok.
+// otherwise will return  error
+func (s Service) CostHistory(ctx context.Context, code string) ([]models.CostChange, error) {
+	if _, err := s.catalog.FindProductByCode(ctx, code); err != nil {
+		return nil, err
+	}
+
+	return s.catalog.ListCostChanges(ctx, code)
+}
+
+// Margins return the gross margin of the lines of the baskets checked out in a date range,
+// grouped by product, category or promotion. A kit line is attributed to its components,
+// but by promotion. The lines without promotion are grouped under an empty key,
+// and a line with several promotions under all their names joined by "+".
+// require the group and the range, from is inclusive and to exclusive, a zero time leaves it open
+// it will return the margins sorted by key if this is ok.
+// otherwise will return  error
+func (s Service) Margins(ctx context.Context, groupBy string, from, to time.Time) ([]models.Margin, error) {
+	if groupBy != models.MarginByProduct && groupBy != models.MarginByCategory && groupBy != models.MarginByPromotion {
+		return nil, models.ErrInvalidMarginGroup
+	}
+
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, models.ErrInvalidDateRange
+	}
+
+	baskets, err := s.repository.ListBaskets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*models.Margin)
+	for _, basket := range baskets {
+		if !basket.IsSold() || basket.CheckedOutAt.Before(from) || (!to.IsZero() && !basket.CheckedOutAt.Before(to)) {
+			continue
+		}
+
+		for _, item := range basket.Items {
+			for _, line := range marginLines(groupBy, item) {
+				margin, ok := byKey[line.key]
+				if !ok {
+					margin = &models.Margin{Key: line.key}
+					byKey[line.key] = margin
+				}
+
+				revenue := line.revenue
+				if basket.PricesIncludeTax {
+					revenue -= line.tax
+				}
+
+				margin.Units += line.units
+				margin.Revenue += revenue
+				margin.Cost += line.cost
+				margin.Margin += revenue - line.cost
+			}
+		}
+	}
+
+	report := make([]models.Margin, 0, len(byKey))
+	for _, margin := range byKey {
+		report = append(report, *margin)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Key < report[j].Key
+	})
+
+	return report, nil
+}
+
+type marginLine struct {
+	key          string
+	units        int
+	revenue, tax models.Money
+	cost         models.Money
+}
+
+// marginLines returns the parts of a line grouped in the margins,
+// a kit line is attributed to its components by product and category.
+func marginLines(groupBy string, item models.Item) []marginLine {
+	if groupBy == models.MarginByPromotion {
+		return []marginLine{{key: strings.Join(item.Promotions, "+"), units: item.Quantity,
+			revenue: item.Taxable(), tax: item.Tax, cost: item.Cost}}
+	}
+
+	if len(item.Allocations) == 0 {
+		key := item.Product.Code
+		if groupBy == models.MarginByCategory {
+			key = item.Product.Category
+		}
+
+		return []marginLine{{key: key, units: item.Quantity, revenue: item.Taxable(), tax: item.Tax, cost: item.Cost}}
+	}
+
+	lines := make([]marginLine, 0, len(item.Allocations))
+	for _, a := range item.Allocations {
+		key := a.ProductCode
+		if groupBy == models.MarginByCategory {
+			key = a.Category
+		}
+
+		lines = append(lines, marginLine{key: key, units: a.Quantity, revenue: a.Revenue, tax: a.Tax, cost: a.Cost})
+	}
+
+	return lines
+}
+
+// costItem sets the cost of the units of a line at the cost price in force at the given time,
+// the one of its parent for a variant without its own. The pieces of a weighed product
+// priced by the scale have no weight, so only the weighed ones are costed.
+func (s Service) costItem(ctx context.Context, item models.Item, at time.Time) (models.Item, error) {
+	cost, err := s.costAt(ctx, item.Product.Code, at)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	if cost == nil && item.Product.Parent != "" {
+		if cost, err = s.costAt(ctx, item.Product.Parent, at); err != nil {
+			return models.Item{}, err
+		}
+	}
+
+	item.Cost = 0
+	switch {
+	case cost == nil:
+	case item.Product.IsWeighed():
+		item.Cost = item.Weight.Price(cost.Cost)
+	default:
+		item.Cost = cost.Cost.Mul(item.Quantity)
+	}
+
+	return item, nil
+}
+
+func (s Service) costAt(ctx context.Context, code string, at time.Time) (*models.CostChange, error) {
+	changes, err := s.catalog.ListCostChanges(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	change, ok := models.EffectiveCost(changes, at)
+	if !ok {
+		return nil, nil
+	}
+
+	return &change, nil
+}
@@ -0,0 +1,96 @@
+package cashRegister
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	catalogmemory "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func TestService_ScheduleCostChange(t *testing.T) {
+	service := NewService(RulesEngine, nil, WithCatalog(catalogmemory.NewProductRepository(models.ProductMap[models.Voucher])))
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		change  models.CostChange
+		wantErr error
+	}{
+		{name: "past", change: models.CostChange{ProductCode: models.Voucher, Cost: 300, EffectiveFrom: time.Now().Add(-time.Hour)}, wantErr: models.ErrInvalidCostChange},
+		{name: "negative", change: models.CostChange{ProductCode: models.Voucher, Cost: -1}, wantErr: models.ErrInvalidCostChange},
+		{name: "unknown product", change: models.CostChange{ProductCode: "SOCKS", Cost: 200}, wantErr: models.ErrProductNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ScheduleCostChange(ctx, tt.change)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+
+	_, err := service.ScheduleCostChange(ctx, models.CostChange{ProductCode: models.Voucher, Cost: 300})
+	require.NoError(t, err)
+
+	history, err := service.CostHistory(ctx, models.Voucher)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, models.Money(300), history[0].Cost)
+}
+
+func TestService_Margins(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	service := NewService(RulesEngine, memory.NewRepository(), WithCatalog(catalogmemory.NewProductRepository(
+		models.ProductMap[models.Voucher], models.ProductMap[models.Pants])))
+	ctx := context.Background()
+
+	_, err := service.ScheduleCostChange(ctx, models.CostChange{ProductCode: models.Voucher, Cost: 300})
+	require.NoError(t, err)
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	for _, code := range []string{models.Voucher, models.Voucher, models.Pants} {
+		basket, err = service.AddProduct(ctx, basket.Code, code)
+		require.NoError(t, err)
+	}
+
+	before := time.Now()
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.False(t, basket.CheckedOutAt.Before(before))
+
+	voucher := basket.Items[models.Voucher]
+	assert.Equal(t, []string{"buy_two_by_one_free"}, voucher.Promotions)
+	assert.Equal(t, models.Money(600), voucher.Cost)
+
+	// the 2-for-1 sells two vouchers of 3.00€ cost for 5.00€
+	margins, err := service.Margins(ctx, models.MarginByPromotion, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, margins, 2)
+	assert.Equal(t, "", margins[0].Key)
+	assert.Equal(t, models.Margin{Key: "buy_two_by_one_free", Units: 2, Revenue: 500, Cost: 600, Margin: -100}, margins[1])
+	assert.Equal(t, float64(-20), margins[1].Percent())
+
+	// prices include tax, so the revenue of the pants is net of it
+	margins, err = service.Margins(ctx, models.MarginByProduct, before, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, margins, 2)
+	assert.Equal(t, models.Margin{Key: models.Pants, Units: 1, Revenue: 620, Margin: 620}, margins[0])
+
+	margins, err = service.Margins(ctx, models.MarginByCategory, time.Time{}, before)
+	require.NoError(t, err)
+	assert.Empty(t, margins)
+
+	_, err = service.Margins(ctx, "cashier", time.Time{}, time.Time{})
+	assert.Equal(t, models.ErrInvalidMarginGroup, err)
+
+	_, err = service.Margins(ctx, models.MarginByProduct, before, before)
+	assert.Equal(t, models.ErrInvalidDateRange, err)
+}
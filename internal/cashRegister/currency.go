@@ -0,0 +1,176 @@
+package cashRegister
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed exchange_rates.yml
+var exchangeRatesData []byte
+
+// ExchangeRates represents the base currency of the store,
+// the one of the catalog and of all the amounts of a basket,
+// and the table of rates to convert between currencies.
+type ExchangeRates struct {
+	Base  string         `yaml:"base"`
+	Rates []ExchangeRate `yaml:"rates"`
+}
+
+// ExchangeRate represents the units of To that one unit of From is worth,
+// it's in force from EffectiveFrom until a newer rate of the same pair.
+type ExchangeRate struct {
+	From          string      `yaml:"from"`
+	To            string      `yaml:"to"`
+	Rate          models.Rate `yaml:"rate"`
+	EffectiveFrom time.Time   `yaml:"effectiveFrom"`
+}
+
+// exchangeRates are by default
+var exchangeRates ExchangeRates
+
+// LoadExchangeRates function load the exchange rates through a yaml file,
+// the rates by default are loaded when path is empty.
+func LoadExchangeRates(path string) error {
+	content := exchangeRatesData
+	if path != "" {
+		var err error
+		content, err = os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("couldn't read exchange rates file.: %s", err)
+		}
+	}
+
+	var rates ExchangeRates
+	err := yaml.Unmarshal(content, &rates)
+	if err != nil {
+		return fmt.Errorf("couldn't parse exchange rates file.: %s", err)
+	}
+
+	if !models.Currencies[rates.Base] {
+		return models.ErrInvalidExchangeRates
+	}
+
+	for _, r := range rates.Rates {
+		if !models.Currencies[r.From] || !models.Currencies[r.To] || r.From == r.To || r.Rate.IsZero() {
+			return models.ErrInvalidExchangeRates
+		}
+	}
+
+	exchangeRates = rates
+	return nil
+}
+
+// baseCurrency returns the currency of the catalog and of the amounts of the baskets.
+func baseCurrency() string {
+	if exchangeRates.Base == "" {
+		return models.EUR
+	}
+
+	return exchangeRates.Base
+}
+
+// SetPaymentCurrency set the currency a basket is paid in.
+// require a basket id and the currency code
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) SetPaymentCurrency(ctx context.Context, basketID, currency string) (models.Basket, error) {
+	if !models.Currencies[currency] {
+		return models.Basket{}, models.ErrInvalidCurrency
+	}
+
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if !basket.IsOpen() {
+		return models.Basket{}, models.ErrBasketIsClosed
+	}
+
+	if _, err = exchangeRate(baseCurrency(), currency, time.Now()); err != nil {
+		return models.Basket{}, err
+	}
+
+	basket.PaymentCurrency = currency
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
+
+// exchangeRate returns the rate in force at the given time to convert
+// an amount between two currencies. When the table only has the opposite
+// pair its inverse is used.
+func exchangeRate(from, to string, at time.Time) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	var found *ExchangeRate
+	var inverse bool
+	for i, r := range exchangeRates.Rates {
+		direct := r.From == from && r.To == to
+		opposite := r.From == to && r.To == from
+		if (!direct && !opposite) || r.EffectiveFrom.After(at) {
+			continue
+		}
+
+		if found == nil || r.EffectiveFrom.After(found.EffectiveFrom) {
+			found = &exchangeRates.Rates[i]
+			inverse = opposite
+		}
+	}
+
+	if found == nil {
+		return nil, models.ErrExchangeRateNotFound
+	}
+
+	rate := found.Rate.Rat()
+	if inverse {
+		rate.Inv(rate)
+	}
+
+	return rate, nil
+}
+
+// convert returns an amount in another currency with the rate in force at the given time,
+// an empty currency is the base one. The exact converted amount is rounded half up to the cent.
+func convert(amount models.Money, from, to string, at time.Time) (models.Money, error) {
+	if from == "" {
+		from = baseCurrency()
+	}
+
+	rate, err := exchangeRate(from, to, at)
+	if err != nil {
+		return 0, err
+	}
+
+	return amount.MulRat(rate, models.RoundHalfUp), nil
+}
+
+// settlePayment converts the total of a basket to the currency it's paid in.
+func settlePayment(basket *models.Basket, at time.Time) error {
+	if basket.Currency == "" {
+		basket.Currency = baseCurrency()
+	}
+
+	if basket.PaymentCurrency == "" {
+		basket.PaymentCurrency = basket.Currency
+	}
+
+	rate, err := exchangeRate(basket.Currency, basket.PaymentCurrency, at)
+	if err != nil {
+		return err
+	}
+
+	basket.ExchangeRate = models.NewRate(rate)
+	basket.PaymentTotal = basket.Total.MulRat(rate, models.RoundHalfUp)
+
+	return nil
+}
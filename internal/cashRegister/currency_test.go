@@ -0,0 +1,187 @@
+package cashRegister
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func TestLoadExchangeRates(t *testing.T) {
+	err := LoadExchangeRates("")
+	require.NoError(t, err)
+	assert.Equal(t, models.EUR, baseCurrency())
+	assert.NotEmpty(t, exchangeRates.Rates)
+
+	path := filepath.Join(t.TempDir(), "rates.yml")
+	err = os.WriteFile(path, []byte("base: EUR\nrates:\n  - from: EUR\n    to: JPY\n    rate: 140\n"), 0o600)
+	require.NoError(t, err)
+	assert.Equal(t, models.ErrInvalidExchangeRates, LoadExchangeRates(path))
+
+	assert.Error(t, LoadExchangeRates(filepath.Join(t.TempDir(), "missing.yml")))
+}
+
+func TestExchangeRate(t *testing.T) {
+	defer func() { require.NoError(t, LoadExchangeRates("")) }()
+
+	day := func(d int) time.Time { return time.Date(2022, time.May, d, 0, 0, 0, 0, time.UTC) }
+	exchangeRates = ExchangeRates{
+		Base: models.EUR,
+		Rates: []ExchangeRate{
+			{From: models.EUR, To: models.USD, Rate: models.NewRate(big.NewRat(105, 100)), EffectiveFrom: day(1)},
+			{From: models.EUR, To: models.USD, Rate: models.NewRate(big.NewRat(11, 10)), EffectiveFrom: day(10)},
+			{From: models.EUR, To: models.GBP, Rate: models.NewRate(big.NewRat(4, 5)), EffectiveFrom: day(1)},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		at       time.Time
+		want     *big.Rat
+		wantErr  error
+	}{
+		{name: "same currency", from: models.EUR, to: models.EUR, at: day(5), want: big.NewRat(1, 1)},
+		{name: "rate in force", from: models.EUR, to: models.USD, at: day(5), want: big.NewRat(105, 100)},
+		{name: "newer rate", from: models.EUR, to: models.USD, at: day(10), want: big.NewRat(11, 10)},
+		{name: "inverse rate", from: models.GBP, to: models.EUR, at: day(5), want: big.NewRat(5, 4)},
+		{name: "before any rate", from: models.EUR, to: models.USD, at: day(0), wantErr: models.ErrExchangeRateNotFound},
+		{name: "missing pair", from: models.USD, to: models.GBP, at: day(5), wantErr: models.ErrExchangeRateNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := exchangeRate(tt.from, tt.to, tt.at)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, 0, tt.want.Cmp(got))
+		})
+	}
+}
+
+func TestService_SetPaymentCurrency(t *testing.T) {
+	require.NoError(t, LoadExchangeRates(""))
+
+	basketMock := models.Basket{Code: "1", Items: map[string]models.Item{}}
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.SetPaymentCurrency(context.Background(), "1", models.USD)
+	require.NoError(t, err)
+	assert.Equal(t, models.USD, basket.PaymentCurrency)
+
+	_, err = service.SetPaymentCurrency(context.Background(), "1", "JPY")
+	assert.Equal(t, models.ErrInvalidCurrency, err)
+}
+
+func TestService_CheckoutBasket_PaymentCurrency(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	basketMock := models.Basket{
+		Code: "1",
+		Items: map[string]models.Item{
+			"VOUCHER": {Product: models.ProductMap["VOUCHER"], Quantity: 1, Total: 500},
+			"TSHIRT":  {Product: models.ProductMap["TSHIRT"], Quantity: 1, Total: 2000},
+			"PANTS":   {Product: models.ProductMap["PANTS"], Quantity: 1, Total: 750},
+		},
+		PaymentCurrency: models.USD,
+	}
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.CheckoutBasket(context.Background(), "1")
+	require.NoError(t, err)
+
+	// 32.50 EUR at 1.05 is 34.125 USD, rounded half up
+	assert.Equal(t, models.EUR, basket.Currency)
+	assert.Equal(t, models.Money(3250), basket.Total)
+	assert.Equal(t, "1.05", basket.ExchangeRate.String())
+	assert.Equal(t, models.Money(3413), basket.PaymentTotal)
+}
+
+func TestService_AddProduct_PriceListCurrency(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	basketMock := models.Basket{Code: "1", Items: map[string]models.Item{}, PriceList: "usd"}
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("GetItem", mock.Anything, mock.Anything, mock.Anything).Return(models.Item{}, models.ErrItemNotFound)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	priceListsMock := new(storagemocks.PriceListRepository)
+	priceListsMock.On("FindPriceList", mock.Anything, "usd").Return(models.PriceList{
+		Name:     "usd",
+		Currency: models.USD,
+		Prices:   map[string]models.Money{"TSHIRT": 2100},
+	}, nil)
+
+	service := NewService(RulesEngine, repositoryMock, WithPriceLists(priceListsMock))
+	basket, err := service.AddProduct(context.Background(), "1", "TSHIRT")
+	require.NoError(t, err)
+
+	// 21.00 USD at 1/1.05 is 20.00 EUR
+	assert.Equal(t, models.Money(2000), basket.Items["TSHIRT"].Product.Price)
+	assert.Equal(t, models.EUR, basket.Items["TSHIRT"].Product.Currency)
+	assert.Equal(t, models.EUR, basket.Currency)
+}
+
+func TestService_CheckoutBasket_MissingRateKeepsPoints(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+	defer func() { require.NoError(t, LoadExchangeRates("")) }()
+
+	service := NewService(RulesEngine, memory.NewRepository(), WithCustomers(memory.NewCustomerRepository()))
+	ctx := context.Background()
+
+	customer, err := service.CreateCustomer(ctx, "Pepito", models.TierGold)
+	require.NoError(t, err)
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AttachCustomer(ctx, basket.Code, customer.ID)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+	_, err = service.SetPaymentCurrency(ctx, basket.Code, models.USD)
+	require.NoError(t, err)
+
+	// the payment fails before the points are saved
+	exchangeRates = ExchangeRates{Base: models.EUR}
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	assert.Equal(t, models.ErrExchangeRateNotFound, err)
+
+	customer, err = service.GetCustomer(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, customer.Points)
+
+	// so the retry credits them once
+	require.NoError(t, LoadExchangeRates(""))
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 40, basket.PointsEarned)
+
+	customer, err = service.GetCustomer(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 40, customer.Points)
+}
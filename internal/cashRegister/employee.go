@@ -0,0 +1,85 @@
+package cashRegister
+
+import (
+	"context"
+	"sort"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// SetEmployee mark a basket as a staff purchase.
+// require a basket id and employee id, an empty employee id removes the mark
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) SetEmployee(ctx context.Context, basketID, employeeID string) (models.Basket, error) {
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if !basket.IsOpen() {
+		return models.Basket{}, models.ErrBasketIsClosed
+	}
+
+	basket.EmployeeID = employeeID
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
+
+// StaffPurchases return the checked out staff purchases grouped by employee.
+func (s Service) StaffPurchases(ctx context.Context) ([]models.StaffPurchases, error) {
+	baskets, err := s.repository.ListBaskets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byEmployee := make(map[string]*models.StaffPurchases)
+	for _, basket := range baskets {
+		if !basket.IsSold() || basket.EmployeeID == "" {
+			continue
+		}
+
+		purchases, ok := byEmployee[basket.EmployeeID]
+		if !ok {
+			purchases = &models.StaffPurchases{EmployeeID: basket.EmployeeID}
+			byEmployee[basket.EmployeeID] = purchases
+		}
+
+		purchases.Baskets++
+		purchases.Gross += basket.Total + basket.EmployeeDiscount
+		purchases.Discount += basket.EmployeeDiscount
+		purchases.Total += basket.Total
+		purchases.Rounding += basket.BaseRoundingAdjustment
+		purchases.Tendered += basket.Tendered()
+	}
+
+	report := make([]models.StaffPurchases, 0, len(byEmployee))
+	for _, purchases := range byEmployee {
+		report = append(report, *purchases)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].EmployeeID < report[j].EmployeeID
+	})
+
+	return report, nil
+}
+
+// employeeDiscount applies the staff discount to an item
+// unless one of the excluded rules was applied to it.
+func employeeDiscount(item models.Item, applied []Rule) models.Item {
+	config := configRules.Employee
+	for _, r := range applied {
+		for _, excluded := range config.ExcludedRules {
+			if r.Name == excluded {
+				return item
+			}
+		}
+	}
+
+	discount := item.Total.Percent(config.Percent, models.RoundHalfUp)
+	item.Total -= discount
+	item.EmployeeDiscount = discount
+
+	return item
+}
@@ -0,0 +1,84 @@
+package cashRegister
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func TestService_CheckoutBasket_Employee(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"VOUCHER": {
+				Product:  models.Product{Code: "VOUCHER", Name: "Gift Card", Price: 500},
+				Quantity: 2,
+				Total:    1000,
+			},
+			"TSHIRT": {
+				Product:  models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000},
+				Quantity: 3,
+				Total:    6000,
+			},
+			"PANTS": {
+				Product:  models.Product{Code: "PANTS", Name: "Summer Pants", Price: 750},
+				Quantity: 1,
+				Total:    750,
+			},
+		},
+		EmployeeID: "E-001",
+	}
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.CheckoutBasket(context.Background(), basketMock.Code)
+	require.NoError(t, err)
+
+	// the 2-for-1 on VOUCHER doesn't stack with the employee discount
+	assert.Equal(t, models.Money(500), basket.Items["VOUCHER"].Total)
+	assert.Equal(t, models.Money(0), basket.Items["VOUCHER"].EmployeeDiscount)
+	assert.Equal(t, models.Money(4560), basket.Items["TSHIRT"].Total)
+	assert.Equal(t, models.Money(1140), basket.Items["TSHIRT"].EmployeeDiscount)
+	assert.Equal(t, models.Money(600), basket.Items["PANTS"].Total)
+	assert.Equal(t, models.Money(150), basket.Items["PANTS"].EmployeeDiscount)
+	assert.Equal(t, models.Money(1290), basket.EmployeeDiscount)
+	assert.Equal(t, models.Money(5660), basket.Total)
+}
+
+func TestService_StaffPurchases(t *testing.T) {
+	baskets := []models.Basket{
+		{Code: "1", Total: 1600, EmployeeDiscount: 400, EmployeeID: "E-002", State: models.StateCheckedOut},
+		// paid in cash, rounded to 6.00 from 5.98
+		{Code: "2", Total: 598, EmployeeDiscount: 150, EmployeeID: "E-001", State: models.StateCheckedOut,
+			Currency: models.EUR, PaymentCurrency: models.EUR, ExchangeRate: models.NewRate(big.NewRat(1, 1)), RoundingAdjustment: 2, BaseRoundingAdjustment: 2},
+		{Code: "3", Total: 800, EmployeeDiscount: 200, EmployeeID: "E-002", State: models.StateCheckedOut},
+		{Code: "4", Total: 2000, EmployeeID: "E-001"},
+		{Code: "5", Total: 2000, State: models.StateCheckedOut},
+	}
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("ListBaskets", mock.Anything).Return(baskets, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	report, err := service.StaffPurchases(context.Background())
+	require.NoError(t, err)
+
+	want := []models.StaffPurchases{
+		{EmployeeID: "E-001", Baskets: 1, Gross: 748, Discount: 150, Total: 598, Rounding: 2, Tendered: 600},
+		{EmployeeID: "E-002", Baskets: 2, Gross: 3000, Discount: 600, Total: 2400, Tendered: 2400},
+	}
+	assert.Equal(t, want, report)
+}
@@ -0,0 +1,121 @@
+package cashRegister
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// AssignVariants returns the variant of every active experiment
+// for a basket. The assignment is deterministic: the same basket id
+// always falls into the same variant.
+func AssignVariants(basketID string) map[string]string {
+	variants := make(map[string]string)
+	for name, experiment := range configRules.Experiments {
+		if !experiment.Active {
+			continue
+		}
+
+		if variant, ok := experiment.assign(basketID); ok {
+			variants[name] = variant.Name
+		}
+	}
+
+	return variants
+}
+
+// assign picks a variant by hashing the basket id
+// into the cumulative weights of the variants.
+func (e Experiment) assign(basketID string) (Variant, bool) {
+	var total int
+	for _, v := range e.Variants {
+		total += v.Weight
+	}
+
+	if total <= 0 {
+		return Variant{}, false
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(e.Name + ":" + basketID))
+	bucket := int(h.Sum32() % uint32(total))
+
+	for _, v := range e.Variants {
+		if bucket < v.Weight {
+			return v, true
+		}
+		bucket -= v.Weight
+	}
+
+	return Variant{}, false
+}
+
+// applyVariants overrides the values of a rule with
+// the ones of the variant the basket was assigned to.
+func applyVariants(basket models.Basket, rule Rule) Rule {
+	for name, variantName := range basket.Variants {
+		experiment, ok := configRules.Experiments[name]
+		if !ok || experiment.Rule != rule.Name {
+			continue
+		}
+
+		for _, v := range experiment.Variants {
+			if v.Name != variantName {
+				continue
+			}
+
+			if v.NewPrice > 0 {
+				rule.NewPrice = v.NewPrice
+			}
+		}
+	}
+
+	return rule
+}
+
+// experimentResults summarizes the baskets assigned to each variant of an experiment.
+func experimentResults(experiment Experiment, baskets []models.Basket) models.ExperimentResult {
+	byVariant := make(map[string]*models.VariantResult)
+	for _, v := range experiment.Variants {
+		byVariant[v.Name] = &models.VariantResult{Variant: v.Name}
+	}
+
+	for _, basket := range baskets {
+		result, ok := byVariant[basket.Variants[experiment.Name]]
+		if !ok {
+			continue
+		}
+
+		result.Baskets++
+		if basket.IsSold() {
+			result.CheckedOut++
+			result.Revenue += basket.Total
+			result.Rounding += basket.BaseRoundingAdjustment
+			result.Tendered += basket.Tendered()
+		}
+	}
+
+	result := models.ExperimentResult{
+		Experiment: experiment.Name,
+		Rule:       string(experiment.Rule),
+		Variants:   []models.VariantResult{},
+	}
+	for _, v := range byVariant {
+		if v.Baskets > 0 {
+			v.ConversionRate = float64(v.CheckedOut) / float64(v.Baskets)
+		}
+
+		if v.CheckedOut > 0 {
+			v.AverageBasket = v.Revenue.Div(int64(v.CheckedOut), models.RoundHalfUp)
+		}
+
+		result.Variants = append(result.Variants, *v)
+	}
+
+	sort.Slice(result.Variants, func(i, j int) bool {
+		return result.Variants[i].Variant < result.Variants[j].Variant
+	})
+
+	return result
+}
@@ -0,0 +1,119 @@
+package cashRegister
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func activateExperiment(t *testing.T) Experiment {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	experiment := configRules.Experiments["tshirt_new_price"]
+	experiment.Active = true
+	configRules.Experiments["tshirt_new_price"] = experiment
+	t.Cleanup(func() {
+		experiment.Active = false
+		configRules.Experiments["tshirt_new_price"] = experiment
+	})
+
+	return experiment
+}
+
+func TestAssignVariants(t *testing.T) {
+	activateExperiment(t)
+
+	counts := map[string]int{}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		variants := AssignVariants(id)
+		require.Len(t, variants, 1)
+		assert.Equal(t, variants, AssignVariants(id))
+		counts[variants["tshirt_new_price"]]++
+	}
+
+	assert.Len(t, counts, 2)
+}
+
+func TestExperiment_assign(t *testing.T) {
+	experiment := Experiment{
+		Name: "all_in_b",
+		Variants: []Variant{
+			{Name: "A", Weight: 0},
+			{Name: "B", Weight: 100},
+		},
+	}
+
+	variant, ok := experiment.assign("4200f350-4fa5-11ec-a386-1e003b1e5256")
+	assert.True(t, ok)
+	assert.Equal(t, "B", variant.Name)
+
+	_, ok = Experiment{Name: "empty"}.assign("4200f350-4fa5-11ec-a386-1e003b1e5256")
+	assert.False(t, ok)
+}
+
+func TestService_CheckoutBasket_Variant(t *testing.T) {
+	activateExperiment(t)
+
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"TSHIRT": {
+				Product:  models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000},
+				Quantity: 3,
+				Total:    6000,
+			},
+		},
+		Variants: map[string]string{"tshirt_new_price": "B"},
+	}
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.CheckoutBasket(context.Background(), basketMock.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(5400), basket.Total)
+}
+
+func TestService_ExperimentResults(t *testing.T) {
+	activateExperiment(t)
+
+	baskets := []models.Basket{
+		{Code: "1", Total: 5700, State: models.StateCheckedOut, Variants: map[string]string{"tshirt_new_price": "A"}},
+		{Code: "2", Variants: map[string]string{"tshirt_new_price": "A"}},
+		// paid in USD at 1.1, rounded by 0.02 USD
+		{Code: "3", Total: 5400, State: models.StateCheckedOut, Variants: map[string]string{"tshirt_new_price": "B"},
+			Currency: models.EUR, PaymentCurrency: "USD", ExchangeRate: models.NewRate(big.NewRat(11, 10)), RoundingAdjustment: -2, BaseRoundingAdjustment: -2},
+		{Code: "4", Total: 2000},
+	}
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("ListBaskets", mock.Anything).Return(baskets, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	result, err := service.ExperimentResults(context.Background(), "tshirt_new_price")
+	require.NoError(t, err)
+
+	want := models.ExperimentResult{
+		Experiment: "tshirt_new_price",
+		Rule:       "buy_three_or_more_new_price",
+		Variants: []models.VariantResult{
+			{Variant: "A", Baskets: 2, CheckedOut: 1, ConversionRate: 0.5, Revenue: 5700, AverageBasket: 5700, Tendered: 5700},
+			{Variant: "B", Baskets: 1, CheckedOut: 1, ConversionRate: 1, Revenue: 5400, AverageBasket: 5400,
+				Rounding: -2, Tendered: 5398},
+		},
+	}
+	assert.Equal(t, want, result)
+
+	_, err = service.ExperimentResults(context.Background(), "unknown")
+	assert.Equal(t, models.ErrExperimentNotFound, err)
+}
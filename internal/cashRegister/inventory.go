@@ -0,0 +1,298 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
+)
+
+const (
+	// StockBlock refuses to sell more units than the available ones.
+	StockBlock = "block"
+	// StockAllowNegative sells the units even if the stock goes negative.
+	StockAllowNegative = "allow_negative"
+)
+
+// WithInventory enables the stock ledger stored in the given repository,
+// the products sold by weight are not tracked.
+func WithInventory(inventory storage.InventoryRepository) Option {
+	return func(s *Service) {
+		s.inventory = inventory
+	}
+}
+
+// GetStock return the stock of a product.
+// require a product code
+// it will return the stock if this is ok.
+// otherwise will return  error
+func (s Service) GetStock(ctx context.Context, code string) (models.Stock, error) {
+	if s.inventory == nil {
+		return models.Stock{}, models.ErrInventoryDisabled
+	}
+
+	if _, err := s.catalog.FindProductByCode(ctx, code); err != nil {
+		return models.Stock{}, err
+	}
+
+	return s.inventory.FindStock(ctx, code)
+}
+
+// ReleaseExpiredReservations release the stock held by the open baskets
+// whose last reservation is older than the reservation TTL.
+// it will return the number of baskets released if this is ok.
+// otherwise will return  error
+func (s Service) ReleaseExpiredReservations(ctx context.Context, now time.Time) (int, error) {
+	if s.inventory == nil {
+		return 0, models.ErrInventoryDisabled
+	}
+
+	baskets, err := s.repository.ListBaskets(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var released int
+	for _, basket := range baskets {
+		if !basket.IsOpen() || len(basket.Reservations) == 0 ||
+			now.Sub(basket.ReservedAt) < configRules.Inventory.ReservationTTL {
+			continue
+		}
+
+		// the basket is stored without its reservations first, so they're released once.
+		held := basket.Reservations
+		basket.Reservations = nil
+		if _, err = s.repository.UpdateBasket(ctx, basket); err != nil {
+			if errors.Is(err, models.ErrBasketChanged) {
+				continue
+			}
+
+			return released, err
+		}
+
+		if err = s.moveHeld(ctx, basket.Code, models.MovementRelease, held); err != nil {
+			return released, err
+		}
+
+		released++
+	}
+
+	return released, nil
+}
+
+// reserve holds units of stock by product code for a basket,
+// nothing is held unless all the units are available.
+func (s Service) reserve(ctx context.Context, basket *models.Basket, units map[string]int) error {
+	if s.inventory == nil || len(units) == 0 {
+		return nil
+	}
+
+	movements := make([]models.StockMovement, 0, len(units))
+	for _, code := range sortedCodes(units) {
+		movements = append(movements, models.StockMovement{
+			ProductCode:   code,
+			Type:          models.MovementReserve,
+			ReservedDelta: units[code],
+			BasketID:      basket.Code,
+			CreatedAt:     time.Now(),
+		})
+	}
+
+	if err := s.moveAll(ctx, movements, models.MovementRelease); err != nil {
+		return err
+	}
+
+	if basket.Reservations == nil {
+		basket.Reservations = make(map[string]int)
+	}
+
+	for code, quantity := range units {
+		basket.Reservations[code] += quantity
+	}
+
+	basket.ReservedAt = time.Now()
+
+	return nil
+}
+
+// moveAll applies the movements, each one checked against the stock with the
+// policy of the inventory. When one can't be applied the ones before it are
+// undone with movements of the given type, so either all of them are applied or none.
+func (s Service) moveAll(ctx context.Context, movements []models.StockMovement, undo string) error {
+	allowNegative := configRules.Inventory.Policy == StockAllowNegative
+	for i, movement := range movements {
+		if _, err := s.inventory.Reserve(ctx, movement, allowNegative); err != nil {
+			if undoErr := s.undoAll(ctx, movements[:i], undo); undoErr != nil {
+				return undoErr
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// undoAll undoes the movements applied with movements of the given type.
+func (s Service) undoAll(ctx context.Context, movements []models.StockMovement, undo string) error {
+	for _, applied := range movements {
+		applied.Type = undo
+		applied.OnHandDelta, applied.ReservedDelta = -applied.OnHandDelta, -applied.ReservedDelta
+		applied.CreatedAt = time.Now()
+		if _, err := s.inventory.Move(ctx, applied); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moveHeld holds again, with a reserve, or gives back, with a release,
+// units of stock by product code for a basket.
+func (s Service) moveHeld(ctx context.Context, basketID, movementType string, units map[string]int) error {
+	if s.inventory == nil {
+		return nil
+	}
+
+	for _, code := range sortedCodes(units) {
+		delta := units[code]
+		if movementType == models.MovementRelease {
+			delta = -delta
+		}
+
+		_, err := s.inventory.Move(ctx, models.StockMovement{
+			ProductCode:   code,
+			Type:          movementType,
+			ReservedDelta: delta,
+			BasketID:      basketID,
+			CreatedAt:     time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// release gives back up to the given units of stock by product code held for a basket.
+func (s Service) release(ctx context.Context, basket *models.Basket, units map[string]int) error {
+	if s.inventory == nil {
+		return nil
+	}
+
+	for _, code := range sortedCodes(units) {
+		quantity := units[code]
+		if quantity > basket.Reservations[code] {
+			quantity = basket.Reservations[code]
+		}
+
+		if quantity <= 0 {
+			continue
+		}
+
+		_, err := s.inventory.Move(ctx, models.StockMovement{
+			ProductCode:   code,
+			Type:          models.MovementRelease,
+			ReservedDelta: -quantity,
+			BasketID:      basket.Code,
+			CreatedAt:     time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		basket.Reservations[code] -= quantity
+		if basket.Reservations[code] == 0 {
+			delete(basket.Reservations, code)
+		}
+	}
+
+	return nil
+}
+
+// releaseAll gives back all the units held for a basket.
+func (s Service) releaseAll(ctx context.Context, basket *models.Basket) error {
+	return s.release(ctx, basket, basket.Reservations)
+}
+
+// commitStock takes out of the stock the units sold in a basket, the components
+// of the kits instead of the kits, and the units
+// held for it. The units that are not held anymore, because their reservation
+// expired, are checked again. It returns the movements applied.
+func (s Service) commitStock(ctx context.Context, basket *models.Basket) ([]models.StockMovement, error) {
+	if s.inventory == nil {
+		return nil, nil
+	}
+
+	units := make(map[string]int)
+	for _, item := range basket.Items {
+		for code, quantity := range item.Product.StockUnits(item.Quantity) {
+			units[code] += quantity
+		}
+	}
+
+	movements := make([]models.StockMovement, 0, len(units))
+	for _, code := range sortedCodes(units) {
+		movements = append(movements, models.StockMovement{
+			ProductCode:   code,
+			Type:          models.MovementSale,
+			OnHandDelta:   -units[code],
+			ReservedDelta: -basket.Reservations[code],
+			BasketID:      basket.Code,
+			CreatedAt:     time.Now(),
+		})
+	}
+
+	if err := s.moveAll(ctx, movements, models.MovementReturn); err != nil {
+		return nil, err
+	}
+
+	basket.Reservations = nil
+
+	return movements, nil
+}
+
+// returnStock puts back the units sold in a basket,
+// the components of the kits instead of the kits.
+func (s Service) returnStock(ctx context.Context, basket models.Basket) error {
+	if s.inventory == nil {
+		return nil
+	}
+
+	units := make(map[string]int)
+	for _, item := range basket.Items {
+		for code, quantity := range item.Product.StockUnits(item.Quantity) {
+			units[code] += quantity
+		}
+	}
+
+	for _, code := range sortedCodes(units) {
+		_, err := s.inventory.Move(ctx, models.StockMovement{
+			ProductCode: code,
+			Type:        models.MovementReturn,
+			OnHandDelta: units[code],
+			BasketID:    basket.Code,
+			CreatedAt:   time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sortedCodes(quantities map[string]int) []string {
+	codes := make([]string, 0, len(quantities))
+	for code := range quantities {
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+
+	return codes
+}
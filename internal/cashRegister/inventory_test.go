@@ -0,0 +1,311 @@
+package cashRegister
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func newInventoryService(t *testing.T, onHand int) (Service, storage.InventoryRepository) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	inventory := memory.NewInventoryRepository(models.Stock{ProductCode: models.Tshirt, OnHand: onHand})
+	return NewService(RulesEngine, memory.NewRepository(), WithInventory(inventory)), inventory
+}
+
+func TestService_AddProduct_Reserve(t *testing.T) {
+	service, inventory := newInventoryService(t, 2)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	configRules.Approvals.Restricted = nil
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+	basket, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{models.Tshirt: 2}, basket.Reservations)
+
+	other, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	_, err = service.AddProduct(ctx, other.Code, models.Tshirt)
+	assert.Equal(t, models.ErrInsufficientStock, err)
+
+	_, err = service.RemoveProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 2}, stock)
+
+	_, err = service.AddProduct(ctx, other.Code, models.Tshirt)
+	require.NoError(t, err)
+}
+
+func TestService_AddProduct_AllowNegativeStock(t *testing.T) {
+	service, _ := newInventoryService(t, 0)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	configRules.Inventory.Policy = StockAllowNegative
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+
+	stock, err := service.GetStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, -1, stock.OnHand)
+}
+
+func TestService_CheckoutBasket_Stock(t *testing.T) {
+	service, inventory := newInventoryService(t, 3)
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Empty(t, basket.Reservations)
+
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	assert.ErrorIs(t, err, models.ErrBasketIsClosed)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 1}, stock)
+
+	movements, err := inventory.ListMovements(ctx, models.Tshirt)
+	require.NoError(t, err)
+	require.Len(t, movements, 3)
+	assert.Equal(t, models.MovementSale, movements[2].Type)
+	assert.Equal(t, -2, movements[2].OnHandDelta)
+	assert.Equal(t, -2, movements[2].ReservedDelta)
+}
+
+func TestService_ReleaseExpiredReservations(t *testing.T) {
+	service, inventory := newInventoryService(t, 1)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	configRules.Approvals.Restricted = nil
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	released, err := service.ReleaseExpiredReservations(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, released)
+
+	released, err = service.ReleaseExpiredReservations(ctx, time.Now().Add(configRules.Inventory.ReservationTTL))
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stock.Reserved)
+
+	// the unit is sold to another basket, so the expired one can't be paid
+	other, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	_, err = service.AddProduct(ctx, other.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	assert.Equal(t, models.ErrInsufficientStock, err)
+
+	require.NoError(t, service.RemoveBasket(ctx, other.Code))
+
+	stock, err = inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 1}, stock)
+}
+
+func TestService_CheckoutBasket_RetryAfterStock(t *testing.T) {
+	service, _ := newInventoryService(t, 1)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	WithCustomers(memory.NewCustomerRepository())(&service)
+	configRules.Approvals.Restricted = nil
+	ctx := context.Background()
+
+	customer, err := service.CreateCustomer(ctx, "Pepito", models.TierGold)
+	require.NoError(t, err)
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AttachCustomer(ctx, basket.Code, customer.ID)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	// the reservation expires and the unit is held by another basket
+	_, err = service.ReleaseExpiredReservations(ctx, time.Now().Add(configRules.Inventory.ReservationTTL))
+	require.NoError(t, err)
+	other, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, other.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	assert.Equal(t, models.ErrInsufficientStock, err)
+
+	customer, err = service.GetCustomer(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, customer.Points)
+
+	// once the unit is free again the retry credits the points once
+	_, err = service.RemoveProduct(ctx, other.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+
+	customer, err = service.GetCustomer(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, basket.PointsEarned, customer.Points)
+	assert.Positive(t, customer.Points)
+}
+
+func TestService_AddProduct_ConcurrentStock(t *testing.T) {
+	service, inventory := newInventoryService(t, 3)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var added int32
+	for i := 0; i < 10; i++ {
+		basket, err := service.CreateBasket(ctx)
+		require.NoError(t, err)
+
+		wg.Add(1)
+		go func(code string) {
+			defer wg.Done()
+			if _, err := service.AddProduct(ctx, code, models.Tshirt); err == nil {
+				atomic.AddInt32(&added, 1)
+			}
+		}(basket.Code)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(3), added)
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 3, Reserved: 3}, stock)
+}
+
+// failedUpdates is a repository that can't store any basket.
+type failedUpdates struct {
+	storage.Repository
+	err error
+}
+
+func (r failedUpdates) UpdateBasket(ctx context.Context, basket models.Basket) (models.Basket, error) {
+	return models.Basket{}, r.err
+}
+
+func TestService_CheckoutBasket_StockWhenNotStored(t *testing.T) {
+	service, inventory := newInventoryService(t, 10)
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	// the basket was voided meanwhile, so the units sold are put back
+	stored := service.repository
+	service.repository = failedUpdates{Repository: stored, err: models.ErrBasketChanged}
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	assert.Equal(t, models.ErrBasketChanged, err)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 10, Reserved: 1}, stock)
+
+	// so the checkout can be retried
+	service.repository = stored
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+
+	stock, err = inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 9}, stock)
+}
+
+func TestService_AddProduct_StockWhenNotStored(t *testing.T) {
+	service, inventory := newInventoryService(t, 10)
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	service.repository = failedUpdates{Repository: service.repository, err: models.ErrBasketChanged}
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	assert.Equal(t, models.ErrBasketChanged, err)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 10}, stock)
+}
+
+func TestService_ReleaseExpiredReservations_NotStored(t *testing.T) {
+	service, inventory := newInventoryService(t, 10)
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	// a basket changed meanwhile is skipped and keeps its units
+	stored := service.repository
+	service.repository = failedUpdates{Repository: stored, err: models.ErrBasketChanged}
+	released, err := service.ReleaseExpiredReservations(ctx, time.Now().Add(configRules.Inventory.ReservationTTL))
+	require.NoError(t, err)
+	assert.Equal(t, 0, released)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stock.Reserved)
+
+	// the units are released once, and the checkout checks them again
+	service.repository = stored
+	released, err = service.ReleaseExpiredReservations(ctx, time.Now().Add(configRules.Inventory.ReservationTTL))
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+
+	stock, err = inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 9}, stock)
+}
@@ -0,0 +1,111 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// validateKit checks the components of a kit are products of the catalog
+// that are sold by units, and aren't kits or sold by their variants.
+func (s Service) validateKit(ctx context.Context, product models.Product) error {
+	if product.Parent != "" || product.IsWeighed() {
+		return models.ErrInvalidKit
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range product.Components {
+		if c.Quantity <= 0 || c.ProductCode == product.Code || seen[c.ProductCode] {
+			return models.ErrInvalidKit
+		}
+
+		seen[c.ProductCode] = true
+		component, err := s.catalog.FindProductByCode(ctx, c.ProductCode)
+		if errors.Is(err, models.ErrProductNotFound) {
+			return models.ErrInvalidKit
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if component.IsKit() || component.IsWeighed() {
+			return models.ErrInvalidKit
+		}
+
+		variants, err := s.catalog.ListVariants(ctx, component.Code)
+		if err != nil {
+			return err
+		}
+
+		if len(variants) > 0 {
+			return models.ErrInvalidKit
+		}
+	}
+
+	return nil
+}
+
+// allocateKit attributes the total, tax and cost of a kit line to its components,
+// in proportion to their prices in force at the given time. The cost of a kit
+// without its own cost price is the one of its components.
+func (s Service) allocateKit(ctx context.Context, item models.Item, at time.Time) (models.Item, error) {
+	components := make([]models.Product, len(item.Product.Components))
+	weights := make([]models.Money, len(item.Product.Components))
+	costs := make([]models.Money, len(item.Product.Components))
+	for i, c := range item.Product.Components {
+		product, err := s.catalog.FindProductByCode(ctx, c.ProductCode)
+		if err != nil {
+			return models.Item{}, err
+		}
+
+		if product, err = s.resolveVariant(ctx, product); err != nil {
+			return models.Item{}, err
+		}
+
+		if product, err = s.priceAt(ctx, product, at); err != nil {
+			return models.Item{}, err
+		}
+
+		price, err := convert(product.Price, product.Currency, baseCurrency(), at)
+		if err != nil {
+			return models.Item{}, err
+		}
+
+		costed, err := s.costItem(ctx, models.Item{Product: product, Quantity: c.Quantity * item.Quantity}, at)
+		if err != nil {
+			return models.Item{}, err
+		}
+
+		components[i], weights[i], costs[i] = product, price.Mul(c.Quantity), costed.Cost
+	}
+
+	own, err := s.costAt(ctx, item.Product.Code, at)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	if own != nil {
+		costs = item.Cost.Allocate(weights)
+	}
+
+	revenues := item.Taxable().Allocate(weights)
+	taxes := item.Tax.Allocate(weights)
+	item.Allocations = make([]models.Allocation, len(components))
+	item.Cost = 0
+	for i, product := range components {
+		item.Allocations[i] = models.Allocation{
+			ProductCode: product.Code,
+			Category:    product.Category,
+			Quantity:    item.Product.Components[i].Quantity * item.Quantity,
+			Revenue:     revenues[i],
+			Tax:         taxes[i],
+			Cost:        costs[i],
+		}
+		item.Cost += costs[i]
+	}
+
+	return item, nil
+}
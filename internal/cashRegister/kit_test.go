@@ -0,0 +1,118 @@
+package cashRegister
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	catalogmemory "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+var outfit = models.Product{Code: "OUTFIT", Name: "Summer Outfit", Price: 2500, Category: "apparel", Active: true,
+	Components: []models.Component{{ProductCode: models.Tshirt, Quantity: 1}, {ProductCode: models.Pants, Quantity: 1}}}
+
+func TestService_CreateProduct_Kit(t *testing.T) {
+	service := NewService(RulesEngine, nil, WithCatalog(catalogmemory.NewProductRepository(
+		models.ProductMap[models.Tshirt], models.ProductMap[models.Pants],
+		models.Product{Code: "TSHIRT-M", Name: "Summer T-Shirt M", Parent: models.Tshirt},
+		models.Product{Code: "BANANAS", Name: "Bananas", Price: 199, Unit: models.UnitKg})))
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		components []models.Component
+	}{
+		{name: "unknown component", components: []models.Component{{ProductCode: "SOCKS", Quantity: 1}}},
+		{name: "no units", components: []models.Component{{ProductCode: models.Pants}}},
+		{name: "repeated component", components: []models.Component{{ProductCode: models.Pants, Quantity: 1}, {ProductCode: models.Pants, Quantity: 1}}},
+		{name: "weighed component", components: []models.Component{{ProductCode: "BANANAS", Quantity: 1}}},
+		{name: "sold by its variants", components: []models.Component{{ProductCode: models.Tshirt, Quantity: 1}}},
+		{name: "kit of itself", components: []models.Component{{ProductCode: "OUTFIT", Quantity: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateProduct(ctx, models.Product{Code: "OUTFIT", Name: "Summer Outfit", Price: 2500, Components: tt.components})
+			assert.Equal(t, models.ErrInvalidKit, err)
+		})
+	}
+
+	kit, err := service.CreateProduct(ctx, models.Product{Code: "OUTFIT", Name: "Summer Outfit", Price: 2500,
+		Components: []models.Component{{ProductCode: "TSHIRT-M", Quantity: 1}, {ProductCode: models.Pants, Quantity: 1}}})
+	require.NoError(t, err)
+
+	_, err = service.CreateProduct(ctx, models.Product{Code: "OUTFITS", Name: "Summer Outfits", Price: 4800,
+		Components: []models.Component{{ProductCode: kit.Code, Quantity: 2}}})
+	assert.Equal(t, models.ErrInvalidKit, err)
+}
+
+func TestService_CheckoutBasket_Kit(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	configRules.Approvals.Restricted = nil
+	inventory := memory.NewInventoryRepository(
+		models.Stock{ProductCode: models.Tshirt, OnHand: 3}, models.Stock{ProductCode: models.Pants, OnHand: 2})
+	service := NewService(RulesEngine, memory.NewRepository(), WithInventory(inventory), WithCatalog(
+		catalogmemory.NewProductRepository(models.ProductMap[models.Tshirt], models.ProductMap[models.Pants], outfit)))
+	ctx := context.Background()
+
+	_, err := service.ScheduleCostChange(ctx, models.CostChange{ProductCode: models.Tshirt, Cost: 800})
+	require.NoError(t, err)
+	_, err = service.ScheduleCostChange(ctx, models.CostChange{ProductCode: models.Pants, Cost: 300})
+	require.NoError(t, err)
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	for _, code := range []string{"OUTFIT", "OUTFIT", models.Tshirt} {
+		basket, err = service.AddProduct(ctx, basket.Code, code)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]int{models.Tshirt: 3, models.Pants: 2}, basket.Reservations)
+	_, err = service.AddProduct(ctx, basket.Code, "OUTFIT")
+	assert.Equal(t, models.ErrInsufficientStock, err)
+
+	// removing the kit gives back only its units
+	basket, err = service.RemoveProduct(ctx, basket.Code, "OUTFIT")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{models.Tshirt: 1}, basket.Reservations)
+
+	for i := 0; i < 2; i++ {
+		basket, err = service.AddProduct(ctx, basket.Code, "OUTFIT")
+		require.NoError(t, err)
+	}
+
+	before := time.Now()
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	require.Len(t, basket.Items, 2)
+
+	kit := basket.Items["OUTFIT"]
+	assert.Equal(t, models.Money(5000), kit.Total)
+	assert.Equal(t, models.Money(2200), kit.Cost)
+	require.Len(t, kit.Allocations, 2)
+	assert.Equal(t, models.Allocation{ProductCode: models.Tshirt, Quantity: 2, Revenue: 3636, Tax: 631, Cost: 1600}, kit.Allocations[0])
+	assert.Equal(t, models.Allocation{ProductCode: models.Pants, Quantity: 2, Revenue: 1364, Tax: 237, Cost: 600}, kit.Allocations[1])
+
+	for code, onHand := range map[string]int{models.Tshirt: 0, models.Pants: 0} {
+		stock, err := inventory.FindStock(ctx, code)
+		require.NoError(t, err)
+		assert.Equal(t, models.Stock{ProductCode: code, OnHand: onHand}, stock)
+	}
+
+	margins, err := service.Margins(ctx, models.MarginByProduct, before, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, margins, 2)
+	assert.Equal(t, models.Pants, margins[0].Key)
+	assert.Equal(t, 2, margins[0].Units)
+	assert.Equal(t, models.Tshirt, margins[1].Key)
+	assert.Equal(t, 3, margins[1].Units)
+	assert.Equal(t, models.Money(2400), margins[1].Cost)
+}
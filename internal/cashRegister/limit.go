@@ -0,0 +1,60 @@
+package cashRegister
+
+import (
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// checkLimits checks a basket is within its purchase limits after adding a product.
+// The limit of the total is lifted by the approval of a manager, used
+// the first time it's exceeded and kept for the rest of the basket.
+func checkLimits(basket *models.Basket, product models.Product) error {
+	limits := configRules.Limits
+	code, max := unitsLimit(product)
+	if max > 0 {
+		var units int
+		for _, item := range basket.Items {
+			if item.Product.Code == code || item.Product.Parent == code {
+				units += item.Quantity
+			}
+		}
+
+		if units > max {
+			return &models.PurchaseLimitError{ProductCode: code, MaxUnits: max}
+		}
+	}
+
+	if limits.ApprovalTotal == 0 || basket.Total <= limits.ApprovalTotal || hasUsedApproval(*basket, models.OperationBasketTotal) {
+		return nil
+	}
+
+	if err := useApproval(basket, models.OperationBasketTotal); err != nil {
+		return &models.PurchaseLimitError{MaxTotal: limits.ApprovalTotal}
+	}
+
+	return nil
+}
+
+// unitsLimit returns the code the units of a product are limited by and its limit,
+// the one of the product, of its parent, or the one of any product.
+func unitsLimit(product models.Product) (string, int) {
+	limits := configRules.Limits
+	if max, ok := limits.Products[product.Code]; ok {
+		return product.Code, max
+	}
+
+	if max, ok := limits.Products[product.Parent]; ok && product.Parent != "" {
+		return product.Parent, max
+	}
+
+	return product.Code, limits.MaxUnits
+}
+
+func hasUsedApproval(basket models.Basket, operation string) bool {
+	for _, approval := range basket.Approvals {
+		if approval.Operation == operation && !approval.UsedAt.IsZero() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func TestService_AddProduct_UnitsLimit(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	inventory := memory.NewInventoryRepository(models.Stock{ProductCode: models.Voucher, OnHand: 20})
+	service := NewService(RulesEngine, memory.NewRepository(), WithInventory(inventory))
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, err = service.AddProduct(ctx, basket.Code, models.Voucher)
+		require.NoError(t, err)
+	}
+
+	_, err = service.AddProduct(ctx, basket.Code, models.Voucher)
+	assert.Equal(t, &models.PurchaseLimitError{ProductCode: models.Voucher, MaxUnits: 10}, err)
+	assert.True(t, errors.Is(err, models.ErrPurchaseLimit))
+	assert.False(t, errors.Is(err, models.ErrApprovalRequired))
+	assert.False(t, errors.Is(err, models.ErrProductNotFound))
+
+	// the units over the limit are neither added nor held
+	basket, err = service.GetBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 10, basket.Items[models.Voucher].Quantity)
+
+	stock, err := inventory.FindStock(ctx, models.Voucher)
+	require.NoError(t, err)
+	assert.Equal(t, 10, stock.Reserved)
+}
+
+func TestService_AddProduct_TotalLimit(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadManagers("testdata/managers.yml"))
+	require.NoError(t, LoadExchangeRates(""))
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	configRules.Limits = Limits{MaxUnits: 4, ApprovalTotal: 5000}
+	service := NewService(RulesEngine, memory.NewRepository())
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+		require.NoError(t, err)
+	}
+
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	assert.Equal(t, &models.PurchaseLimitError{MaxTotal: 5000}, err)
+	assert.True(t, errors.Is(err, models.ErrPurchaseLimit))
+	assert.False(t, errors.Is(err, models.ErrApprovalRequired))
+
+	_, err = service.Approve(ctx, basket.Code, models.OperationBasketTotal, "M-001", "1234")
+	require.NoError(t, err)
+
+	// the approval lifts the limit of the total for the rest of the basket
+	for i := 0; i < 2; i++ {
+		basket, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 4, basket.Items[models.Tshirt].Quantity)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	assert.Equal(t, &models.PurchaseLimitError{ProductCode: models.Tshirt, MaxUnits: 4}, err)
+}
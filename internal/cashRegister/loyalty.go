@@ -0,0 +1,196 @@
+package cashRegister
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// CreateCustomer create a loyalty account.
+// require a name and a tier, standard tier is used when it is empty
+// it will return a new customer if this is ok.
+// otherwise will return error
+func (s Service) CreateCustomer(ctx context.Context, name, tier string) (models.Customer, error) {
+	if s.customers == nil {
+		return models.Customer{}, models.ErrLoyaltyDisabled
+	}
+
+	if tier == "" {
+		tier = models.TierStandard
+	}
+
+	if _, ok := configRules.Loyalty.Tiers[tier]; !ok {
+		return models.Customer{}, models.ErrInvalidTier
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	return s.customers.CreateCustomer(ctx, models.Customer{
+		ID:   id.String(),
+		Name: name,
+		Tier: tier,
+	})
+}
+
+// GetCustomer return a loyalty account.
+// require a customer id
+// it will return a customer if this is ok.
+// otherwise will return  error
+func (s Service) GetCustomer(ctx context.Context, id string) (models.Customer, error) {
+	if s.customers == nil {
+		return models.Customer{}, models.ErrLoyaltyDisabled
+	}
+
+	return s.customers.FindCustomerByID(ctx, id)
+}
+
+// AttachCustomer link a loyalty account to a basket.
+// require a basket id and customer id
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) AttachCustomer(ctx context.Context, basketID, customerID string) (models.Basket, error) {
+	if s.customers == nil {
+		return models.Basket{}, models.ErrLoyaltyDisabled
+	}
+
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if !basket.IsOpen() {
+		return models.Basket{}, models.ErrBasketIsClosed
+	}
+
+	customer, err := s.customers.FindCustomerByID(ctx, customerID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if basket.CustomerID != customer.ID {
+		basket.PointsRedeemed = 0
+		basket.PointsDiscount = 0
+	}
+
+	basket.CustomerID = customer.ID
+	calculateTotal(&basket)
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
+
+// RedeemPoints use points of the attached customer as a discount on the basket.
+// require a basket id and the number of points, zero cancels a previous redemption
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) RedeemPoints(ctx context.Context, basketID string, points int) (models.Basket, error) {
+	if s.customers == nil {
+		return models.Basket{}, models.ErrLoyaltyDisabled
+	}
+
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if !basket.IsOpen() {
+		return models.Basket{}, models.ErrBasketIsClosed
+	}
+
+	if basket.CustomerID == "" {
+		return models.Basket{}, models.ErrCustomerNotAttached
+	}
+
+	customer, err := s.customers.FindCustomerByID(ctx, basket.CustomerID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if points < 0 || points > customer.Points {
+		return models.Basket{}, models.ErrNotEnoughPoints
+	}
+
+	discount := configRules.Loyalty.PointValue.Mul(points)
+	if discount > basket.Subtotal() {
+		return models.Basket{}, models.ErrRedemptionExceedsTotal
+	}
+
+	basket.PointsRedeemed = points
+	basket.PointsDiscount = discount
+	calculateTotal(&basket)
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
+
+// settleLoyalty computes the points redeemed and earned by the customer attached
+// to a basket being checked out. They're saved by creditLoyalty before it's stored.
+func (s Service) settleLoyalty(ctx context.Context, basket models.Basket) (models.Basket, error) {
+	if s.customers == nil {
+		return models.Basket{}, models.ErrLoyaltyDisabled
+	}
+
+	customer, err := s.customers.FindCustomerByID(ctx, basket.CustomerID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	// promotions could have lowered the subtotal under the points discount,
+	// so only the points needed to pay the basket are used.
+	loyalty := configRules.Loyalty
+	if subtotal := basket.Subtotal(); basket.PointsDiscount > subtotal {
+		basket.PointsRedeemed = int(subtotal.Div(int64(loyalty.PointValue), models.RoundUp))
+		basket.PointsDiscount = subtotal
+		calculateTotal(&basket)
+	}
+
+	if basket.PointsRedeemed > customer.Points {
+		return models.Basket{}, models.ErrNotEnoughPoints
+	}
+
+	basket.PointsEarned = int(basket.Total.MulRat(pointsRate(customer.Tier), models.RoundDown))
+
+	return basket, nil
+}
+
+// creditLoyalty debit the redeemed points and credit the earned ones to the customer
+// of a basket being checked out, the redeemed points are checked again as they're debited.
+func (s Service) creditLoyalty(ctx context.Context, basket models.Basket) error {
+	if basket.CustomerID == "" {
+		return nil
+	}
+
+	_, err := s.customers.AddPoints(ctx, basket.CustomerID, basket.PointsEarned-basket.PointsRedeemed)
+
+	return err
+}
+
+// reverseLoyalty gives back to the customer of a basket voided or refunded after
+// its checkout the points redeemed, and takes out the ones earned.
+func (s Service) reverseLoyalty(ctx context.Context, basket models.Basket) error {
+	if basket.CustomerID == "" {
+		return nil
+	}
+
+	if s.customers == nil {
+		return models.ErrLoyaltyDisabled
+	}
+
+	_, err := s.customers.AddPoints(ctx, basket.CustomerID, basket.PointsRedeemed-basket.PointsEarned)
+
+	return err
+}
+
+// pointsRate returns the exact points earned by each cent paid by a customer of the tier.
+func pointsRate(tier string) *big.Rat {
+	loyalty := configRules.Loyalty
+	rate, _ := new(big.Rat).SetString(strconv.FormatFloat(loyalty.PointsPerEuro, 'f', -1, 64))
+	multiplier, _ := new(big.Rat).SetString(strconv.FormatFloat(loyalty.Tiers[tier], 'f', -1, 64))
+	rate.Mul(rate, multiplier)
+
+	return rate.Quo(rate, big.NewRat(100, 1))
+}
@@ -0,0 +1,238 @@
+package cashRegister
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func TestService_CreateCustomer(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	customersMock := new(storagemocks.CustomerRepository)
+	customersMock.On("CreateCustomer", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, customer models.Customer) models.Customer { return customer }, nil)
+
+	service := NewService(nil, nil, WithCustomers(customersMock))
+	customer, err := service.CreateCustomer(context.Background(), "Pepito", "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, customer.ID)
+	assert.Equal(t, models.TierStandard, customer.Tier)
+
+	_, err = service.CreateCustomer(context.Background(), "Pepito", "platinum")
+	assert.Equal(t, models.ErrInvalidTier, err)
+
+	_, err = NewService(nil, nil).CreateCustomer(context.Background(), "Pepito", "")
+	assert.Equal(t, models.ErrLoyaltyDisabled, err)
+}
+
+func TestService_RedeemPoints(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"PANTS": {
+				Product:  models.Product{Code: "PANTS", Name: "Summer Pants", Price: 750},
+				Quantity: 1,
+				Total:    750,
+			},
+		},
+		Total:      750,
+		CustomerID: "c1",
+	}
+	customer := models.Customer{ID: "c1", Tier: models.TierStandard, Points: 1000}
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+	customersMock := new(storagemocks.CustomerRepository)
+	customersMock.On("FindCustomerByID", mock.Anything, "c1").Return(customer, nil)
+
+	service := NewService(nil, repositoryMock, WithCustomers(customersMock))
+
+	basket, err := service.RedeemPoints(context.Background(), basketMock.Code, 500)
+	require.NoError(t, err)
+	assert.Equal(t, 500, basket.PointsRedeemed)
+	assert.Equal(t, models.Money(500), basket.PointsDiscount)
+	assert.Equal(t, models.Money(250), basket.Total)
+
+	_, err = service.RedeemPoints(context.Background(), basketMock.Code, 1001)
+	assert.Equal(t, models.ErrNotEnoughPoints, err)
+
+	_, err = service.RedeemPoints(context.Background(), basketMock.Code, 800)
+	assert.Equal(t, models.ErrRedemptionExceedsTotal, err)
+}
+
+func TestService_CheckoutBasket_Loyalty(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"TSHIRT": {
+				Product:  models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000},
+				Quantity: 1,
+				Total:    2000,
+			},
+		},
+		Total:          1000,
+		CustomerID:     "c1",
+		PointsRedeemed: 1000,
+		PointsDiscount: 1000,
+	}
+	customer := models.Customer{ID: "c1", Tier: models.TierGold, Points: 1500}
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+	customersMock := new(storagemocks.CustomerRepository)
+	customersMock.On("FindCustomerByID", mock.Anything, "c1").Return(customer, nil)
+	customersMock.On("AddPoints", mock.Anything, "c1", -980).
+		Return(models.Customer{ID: "c1", Tier: models.TierGold, Points: 520}, nil).Once()
+
+	service := NewService(RulesEngine, repositoryMock, WithCustomers(customersMock))
+	basket, err := service.CheckoutBasket(context.Background(), basketMock.Code)
+	require.NoError(t, err)
+
+	customersMock.AssertExpectations(t)
+	assert.Equal(t, models.StateCheckedOut, basket.State)
+	assert.Equal(t, models.Money(1000), basket.Total)
+	assert.Equal(t, 20, basket.PointsEarned)
+}
+
+func TestService_CheckoutBasket_LoyaltyOnce(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+
+	customers := memory.NewCustomerRepository()
+	service := NewService(RulesEngine, memory.NewRepository(), WithCustomers(customers))
+	ctx := context.Background()
+
+	customer, err := service.CreateCustomer(ctx, "Pepito", models.TierGold)
+	require.NoError(t, err)
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AttachCustomer(ctx, basket.Code, customer.ID)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 40, basket.PointsEarned)
+
+	// a second checkout doesn't credit the points again
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	assert.ErrorIs(t, err, models.ErrBasketIsClosed)
+
+	customer, err = service.GetCustomer(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 40, customer.Points)
+}
+
+func TestPointsRate(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	// 55.00 * 0.7 * 2 is 76.99999999999999 as float
+	configRules.Loyalty.PointsPerEuro = 0.7
+	assert.Equal(t, models.Money(77), models.Money(5500).MulRat(pointsRate(models.TierGold), models.RoundDown))
+	assert.Equal(t, models.Money(38), models.Money(5500).MulRat(pointsRate(models.TierStandard), models.RoundDown))
+}
+
+// staleCustomers is a customers repository read before its points changed.
+type staleCustomers struct {
+	storage.CustomerRepository
+	customer models.Customer
+}
+
+func (r staleCustomers) FindCustomerByID(ctx context.Context, id string) (models.Customer, error) {
+	return r.customer, nil
+}
+
+func TestService_CheckoutBasket_PointsSpentMeanwhile(t *testing.T) {
+	service, inventory, customers := newStateService(t)
+	ctx := context.Background()
+
+	customer, err := service.CreateCustomer(ctx, "Pepito", models.TierStandard)
+	require.NoError(t, err)
+	customer, err = customers.AddPoints(ctx, customer.ID, 1000)
+	require.NoError(t, err)
+
+	var codes []string
+	for i := 0; i < 2; i++ {
+		basket, err := service.CreateBasket(ctx)
+		require.NoError(t, err)
+		_, err = service.AttachCustomer(ctx, basket.Code, customer.ID)
+		require.NoError(t, err)
+		_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+		require.NoError(t, err)
+		_, err = service.RedeemPoints(ctx, basket.Code, 1000)
+		require.NoError(t, err)
+		codes = append(codes, basket.Code)
+	}
+
+	basket, err := service.CheckoutBasket(ctx, codes[0])
+	require.NoError(t, err)
+
+	// the other checkout read the points before they were spent
+	service.customers = staleCustomers{CustomerRepository: customers, customer: customer}
+	_, err = service.CheckoutBasket(ctx, codes[1])
+	assert.Equal(t, models.ErrNotEnoughPoints, err)
+
+	got, err := customers.FindCustomerByID(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, basket.PointsEarned, got.Points)
+
+	// the units of the basket not checked out are still held
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 9, Reserved: 1}, stock)
+}
+
+func TestService_CheckoutBasket_PointsWhenNotStored(t *testing.T) {
+	service, _, customers := newStateService(t)
+	ctx := context.Background()
+
+	customer, err := service.CreateCustomer(ctx, "Pepito", models.TierGold)
+	require.NoError(t, err)
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AttachCustomer(ctx, basket.Code, customer.ID)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	stored := service.repository
+	service.repository = failedUpdates{Repository: stored, err: models.ErrBasketChanged}
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	assert.Equal(t, models.ErrBasketChanged, err)
+
+	customer, err = customers.FindCustomerByID(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, customer.Points)
+
+	// the retry credits the points once
+	service.repository = stored
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+
+	customer, err = customers.FindCustomerByID(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, basket.PointsEarned, customer.Points)
+	assert.Equal(t, 40, customer.Points)
+}
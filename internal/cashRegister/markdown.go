@@ -0,0 +1,128 @@
+package cashRegister
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
+)
+
+// WithMarkdowns enables the markdown policies stored in the given repository.
+func WithMarkdowns(markdowns storage.MarkdownRepository) Option {
+	return func(s *Service) {
+		s.markdowns = markdowns
+	}
+}
+
+// SaveMarkdown create or replace a markdown policy.
+// require a name, the products or categories it's attached to and its steps
+// it will return the markdown if this is ok.
+// otherwise will return error
+func (s Service) SaveMarkdown(ctx context.Context, markdown models.Markdown) (models.Markdown, error) {
+	if s.markdowns == nil {
+		return models.Markdown{}, models.ErrMarkdownsDisabled
+	}
+
+	if markdown.Name == "" || len(markdown.Steps) == 0 ||
+		len(markdown.Products)+len(markdown.Categories) == 0 {
+		return models.Markdown{}, models.ErrInvalidMarkdown
+	}
+
+	for _, step := range markdown.Steps {
+		if step.AfterDays < 0 || step.Percent <= 0 || step.Percent > 100 {
+			return models.Markdown{}, models.ErrInvalidMarkdown
+		}
+	}
+
+	for _, code := range markdown.Products {
+		if _, err := s.catalog.FindProductByCode(ctx, code); err != nil {
+			return models.Markdown{}, err
+		}
+	}
+
+	return s.markdowns.SaveMarkdown(ctx, markdown)
+}
+
+// GetMarkdown return a markdown policy.
+// require a markdown name
+// it will return the markdown if this is ok.
+// otherwise will return  error
+func (s Service) GetMarkdown(ctx context.Context, name string) (models.Markdown, error) {
+	if s.markdowns == nil {
+		return models.Markdown{}, models.ErrMarkdownsDisabled
+	}
+
+	return s.markdowns.FindMarkdown(ctx, name)
+}
+
+// ProductDiscounts return the units sold of every product in the checked out baskets,
+// with the markdowns apart from the discounts of the promotions.
+func (s Service) ProductDiscounts(ctx context.Context) ([]models.ProductDiscounts, error) {
+	baskets, err := s.repository.ListBaskets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[string]*models.ProductDiscounts)
+	for _, basket := range baskets {
+		if !basket.IsSold() {
+			continue
+		}
+
+		for code, item := range basket.Items {
+			discounts, ok := byProduct[code]
+			if !ok {
+				discounts = &models.ProductDiscounts{ProductCode: code}
+				byProduct[code] = discounts
+			}
+
+			discounts.Units += item.Quantity
+			discounts.Markdown += item.MarkdownAmount()
+			discounts.PromotionDiscount += item.PromotionDiscount
+			discounts.Total += item.Total
+		}
+	}
+
+	report := make([]models.ProductDiscounts, 0, len(byProduct))
+	for _, discounts := range byProduct {
+		report = append(report, *discounts)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].ProductCode < report[j].ProductCode
+	})
+
+	return report, nil
+}
+
+// applyMarkdown marks down the price of a new item with the greatest
+// of the markdowns in force for its product.
+func (s Service) applyMarkdown(ctx context.Context, item models.Item, at time.Time) (models.Item, error) {
+	if s.markdowns == nil {
+		return item, nil
+	}
+
+	markdowns, err := s.markdowns.ListMarkdowns(ctx)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	var percent float64
+	for _, markdown := range markdowns {
+		if p := markdown.Percent(at); markdown.AppliesTo(item.Product) && p > percent {
+			percent = p
+		}
+	}
+
+	if percent == 0 {
+		return item, nil
+	}
+
+	item.Markdown = item.Product.Price.Percent(percent, models.RoundHalfUp)
+	item.MarkdownPercent = percent
+	item.Product.Price -= item.Markdown
+
+	return item, nil
+}
@@ -0,0 +1,118 @@
+package cashRegister
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	catalogmemory "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func newMarkdownService() Service {
+	return NewService(RulesEngine, memory.NewRepository(),
+		WithCatalog(catalogmemory.NewProductRepository(models.ProductMap[models.Tshirt], models.ProductMap[models.Pants])),
+		WithMarkdowns(memory.NewMarkdownRepository()))
+}
+
+func TestService_SaveMarkdown(t *testing.T) {
+	service := newMarkdownService()
+	ctx := context.Background()
+
+	steps := []models.MarkdownStep{{AfterDays: 60, Percent: 20}}
+	tests := []struct {
+		name     string
+		markdown models.Markdown
+		wantErr  error
+	}{
+		{name: "without name", markdown: models.Markdown{Products: []string{models.Tshirt}, Steps: steps}, wantErr: models.ErrInvalidMarkdown},
+		{name: "without steps", markdown: models.Markdown{Name: "summer", Products: []string{models.Tshirt}}, wantErr: models.ErrInvalidMarkdown},
+		{name: "without products", markdown: models.Markdown{Name: "summer", Steps: steps}, wantErr: models.ErrInvalidMarkdown},
+		{name: "over 100 percent", markdown: models.Markdown{Name: "summer", Products: []string{models.Tshirt},
+			Steps: []models.MarkdownStep{{Percent: 120}}}, wantErr: models.ErrInvalidMarkdown},
+		{name: "unknown product", markdown: models.Markdown{Name: "summer", Products: []string{"SOCKS"}, Steps: steps}, wantErr: models.ErrProductNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SaveMarkdown(ctx, tt.markdown)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+
+	markdown := models.Markdown{Name: "summer", Products: []string{models.Tshirt}, Steps: steps}
+	_, err := service.SaveMarkdown(ctx, markdown)
+	require.NoError(t, err)
+
+	got, err := service.GetMarkdown(ctx, "summer")
+	require.NoError(t, err)
+	assert.Equal(t, markdown, got)
+
+	_, err = NewService(RulesEngine, nil).GetMarkdown(ctx, "summer")
+	assert.Equal(t, models.ErrMarkdownsDisabled, err)
+}
+
+func TestService_CheckoutBasket_Markdown(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	service := newMarkdownService()
+	ctx := context.Background()
+
+	_, err := service.SaveMarkdown(ctx, models.Markdown{
+		Name: "summer", Products: []string{models.Tshirt}, Start: time.Now().AddDate(0, 0, -91),
+		Steps: []models.MarkdownStep{{AfterDays: 60, Percent: 20}, {AfterDays: 90, Percent: 50}},
+	})
+	require.NoError(t, err)
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		basket, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+		require.NoError(t, err)
+	}
+
+	// the promotion at 19.00€ would raise the marked down price
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+
+	item := basket.Items[models.Tshirt]
+	assert.Equal(t, float64(50), item.MarkdownPercent)
+	assert.Equal(t, models.Money(3000), item.MarkdownAmount())
+	assert.Equal(t, models.Money(0), item.PromotionDiscount)
+	assert.Equal(t, models.Money(3000), item.Total)
+	assert.Equal(t, models.Money(3000), basket.Markdown)
+
+	_, err = service.SaveMarkdown(ctx, models.Markdown{
+		Name: "summer", Products: []string{models.Tshirt}, Start: time.Now(),
+		Steps: []models.MarkdownStep{{Percent: 2.5}},
+	})
+	require.NoError(t, err)
+
+	basket, err = service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		basket, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+		require.NoError(t, err)
+	}
+
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+
+	// the promotion is cheaper and it's reported apart from the markdown
+	item = basket.Items[models.Tshirt]
+	assert.Equal(t, models.Money(150), item.MarkdownAmount())
+	assert.Equal(t, models.Money(5700), item.Total)
+	assert.Equal(t, models.Money(150), item.PromotionDiscount)
+
+	report, err := service.ProductDiscounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ProductDiscounts{{
+		ProductCode: models.Tshirt, Units: 6, Markdown: 3150, PromotionDiscount: 150, Total: 8700,
+	}}, report)
+}
@@ -0,0 +1,43 @@
+package cashRegister
+
+import (
+	"context"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// OverrideItem change manually the price of a line of the basket.
+// require a basket id, product code and the override with its reason code
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) OverrideItem(ctx context.Context, basketID, productCode string, override models.Override) (models.Basket, error) {
+	if err := override.Validate(); err != nil {
+		return models.Basket{}, err
+	}
+
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if !basket.IsOpen() {
+		return models.Basket{}, models.ErrBasketIsClosed
+	}
+
+	item, ok := basket.Items[productCode]
+	if !ok {
+		return models.Basket{}, models.ErrItemNotFound
+	}
+
+	if err = useApproval(&basket, models.OperationOverride); err != nil {
+		return models.Basket{}, err
+	}
+
+	item.Override = &override
+	item.WithOutDiscount()
+	item.ApplyManualDiscount()
+	basket.Items[productCode] = item
+	calculateTotal(&basket)
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
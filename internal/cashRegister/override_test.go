@@ -0,0 +1,128 @@
+package cashRegister
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func TestService_OverrideItem(t *testing.T) {
+	basketMock := func() models.Basket {
+		return models.Basket{
+			Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+			Items: map[string]models.Item{
+				"TSHIRT": {
+					Product:  models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000},
+					Quantity: 2,
+					Total:    4000,
+				},
+			},
+			Total:     4000,
+			Approvals: []models.Approval{{Operation: models.OperationOverride, ManagerID: "M-001"}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		override  models.Override
+		wantErr   error
+		wantTotal models.Money
+	}{
+		{
+			name:      "unit price",
+			override:  models.Override{Type: models.OverrideUnitPrice, Amount: 1500, Reason: models.ReasonDamaged},
+			wantTotal: 3000,
+		},
+		{
+			name:      "discount amount",
+			override:  models.Override{Type: models.OverrideDiscountAmount, Amount: 500, Reason: models.ReasonPriceMatch},
+			wantTotal: 3500,
+		},
+		{
+			name:      "discount percent",
+			override:  models.Override{Type: models.OverrideDiscountPercent, Percent: 10, Reason: models.ReasonCustomerService},
+			wantTotal: 3600,
+		},
+		{
+			name:     "missing reason",
+			override: models.Override{Type: models.OverrideDiscountPercent, Percent: 10},
+			wantErr:  models.ErrInvalidReasonCode,
+		},
+		{
+			name:     "percent over 100",
+			override: models.Override{Type: models.OverrideDiscountPercent, Percent: 110, Reason: models.ReasonDamaged},
+			wantErr:  models.ErrInvalidOverride,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := new(storagemocks.Repository)
+			repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock(), nil)
+			repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+				Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+			service := NewService(RulesEngine, repositoryMock)
+			basket, err := service.OverrideItem(context.Background(), "4200f350-4fa5-11ec-a386-1e003b1e5256", "TSHIRT", tt.override)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, basket.Total)
+			assert.Equal(t, tt.override, *basket.Items["TSHIRT"].Override)
+		})
+	}
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock(), nil)
+	service := NewService(RulesEngine, repositoryMock)
+	_, err := service.OverrideItem(context.Background(), "4200f350-4fa5-11ec-a386-1e003b1e5256", "PANTS",
+		models.Override{Type: models.OverrideUnitPrice, Amount: 500, Reason: models.ReasonDamaged})
+	assert.Equal(t, models.ErrItemNotFound, err)
+}
+
+func TestService_CheckoutBasket_Override(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"TSHIRT": {
+				Product:  models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000},
+				Quantity: 3,
+				Total:    5130,
+				Override: &models.Override{Type: models.OverrideDiscountPercent, Percent: 10, Reason: models.ReasonDamaged},
+			},
+			"PANTS": {
+				Product:  models.Product{Code: "PANTS", Name: "Summer Pants", Price: 750},
+				Quantity: 2,
+				Total:    1000,
+				Override: &models.Override{Type: models.OverrideUnitPrice, Amount: 500, Reason: models.ReasonMissingTag},
+			},
+		},
+	}
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.CheckoutBasket(context.Background(), basketMock.Code)
+	require.NoError(t, err)
+
+	// the manual discount applies after the 3 or more promotion
+	assert.Equal(t, models.Money(5130), basket.Items["TSHIRT"].Total)
+	assert.Equal(t, models.Money(570), basket.Items["TSHIRT"].ManualDiscount)
+	assert.Equal(t, models.Money(1000), basket.Items["PANTS"].Total)
+	assert.Equal(t, models.Money(6130), basket.Total)
+}
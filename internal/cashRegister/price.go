@@ -0,0 +1,94 @@
+package cashRegister
+
+import (
+	"context"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// SchedulePriceChange set a new price of a product from a given time on.
+// require a product code, the price and the time it takes effect,
+// now when it's zero. The currency is the one of the product when it's empty.
+// it will return the price change if this is ok.
+// otherwise will return  error
+func (s Service) SchedulePriceChange(ctx context.Context, change models.PriceChange) (models.PriceChange, error) {
+	now := time.Now()
+	if change.EffectiveFrom.IsZero() {
+		change.EffectiveFrom = now
+	}
+
+	if change.Price < 0 || change.EffectiveFrom.Before(now.Add(-time.Minute)) {
+		return models.PriceChange{}, models.ErrInvalidPriceChange
+	}
+
+	if change.Currency != "" && !models.Currencies[change.Currency] {
+		return models.PriceChange{}, models.ErrInvalidCurrency
+	}
+
+	if _, err := s.catalog.FindProductByCode(ctx, change.ProductCode); err != nil {
+		return models.PriceChange{}, err
+	}
+
+	change.CreatedAt = now
+
+	return s.catalog.SavePriceChange(ctx, change)
+}
+
+// PriceHistory return the price changes of a product, the past and the scheduled ones,
+// sorted by the time they take effect.
+// require a product code
+// it will return the price changes if this is ok.
+// otherwise will return  error
+func (s Service) PriceHistory(ctx context.Context, code string) ([]models.PriceChange, error) {
+	if _, err := s.catalog.FindProductByCode(ctx, code); err != nil {
+		return nil, err
+	}
+
+	return s.catalog.ListPriceChanges(ctx, code)
+}
+
+// priceAt returns the product with the price in force at the given time,
+// the one of the catalog when no price change took effect.
+func (s Service) priceAt(ctx context.Context, product models.Product, at time.Time) (models.Product, error) {
+	changes, err := s.catalog.ListPriceChanges(ctx, product.Code)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	change, ok := models.EffectivePrice(changes, at)
+	if !ok {
+		return product, nil
+	}
+
+	product.Price = change.Price
+	if change.Currency != "" {
+		product.Currency = change.Currency
+	}
+
+	return product, nil
+}
+
+// repriceNow records a price change effective now when a product of the catalog
+// is given a price that is not the one in force, so it's not hidden by a past change.
+func (s Service) repriceNow(ctx context.Context, product models.Product) error {
+	now := time.Now()
+	current, err := s.priceAt(ctx, product, now)
+	if err != nil {
+		return err
+	}
+
+	if current.Price == product.Price && current.Currency == product.Currency {
+		return nil
+	}
+
+	_, err = s.catalog.SavePriceChange(ctx, models.PriceChange{
+		ProductCode:   product.Code,
+		Price:         product.Price,
+		Currency:      product.Currency,
+		EffectiveFrom: now,
+		CreatedAt:     now,
+	})
+
+	return err
+}
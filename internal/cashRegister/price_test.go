@@ -0,0 +1,97 @@
+package cashRegister
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	catalogmemory "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func TestService_SchedulePriceChange(t *testing.T) {
+	service := NewService(RulesEngine, nil, WithCatalog(catalogmemory.NewProductRepository(models.ProductMap[models.Tshirt])))
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		change  models.PriceChange
+		wantErr error
+	}{
+		{name: "past", change: models.PriceChange{ProductCode: models.Tshirt, Price: 1800, EffectiveFrom: time.Now().Add(-time.Hour)}, wantErr: models.ErrInvalidPriceChange},
+		{name: "negative", change: models.PriceChange{ProductCode: models.Tshirt, Price: -1}, wantErr: models.ErrInvalidPriceChange},
+		{name: "unknown currency", change: models.PriceChange{ProductCode: models.Tshirt, Price: 1800, Currency: "XXX"}, wantErr: models.ErrInvalidCurrency},
+		{name: "unknown product", change: models.PriceChange{ProductCode: "SOCKS", Price: 400}, wantErr: models.ErrProductNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SchedulePriceChange(ctx, tt.change)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+
+	_, err := service.SchedulePriceChange(ctx, models.PriceChange{
+		ProductCode: models.Tshirt, Price: 1800, EffectiveFrom: time.Now().Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	// a scheduled change is not in force yet
+	product, err := service.GetProduct(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(2000), product.Price)
+
+	_, err = service.SchedulePriceChange(ctx, models.PriceChange{ProductCode: models.Tshirt, Price: 2200})
+	require.NoError(t, err)
+
+	product, err = service.GetProduct(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(2200), product.Price)
+
+	history, err := service.PriceHistory(ctx, models.Tshirt)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, models.Money(2200), history[0].Price)
+	assert.Equal(t, models.Money(1800), history[1].Price)
+
+	// a new price of the product is not hidden by the past change
+	product.Price = 2100
+	_, err = service.UpdateProduct(ctx, product)
+	require.NoError(t, err)
+
+	product, err = service.GetProduct(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(2100), product.Price)
+}
+
+func TestService_AddProduct_PriceInForce(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	service := NewService(RulesEngine, memory.NewRepository(),
+		WithCatalog(catalogmemory.NewProductRepository(models.ProductMap[models.Pants])))
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, models.Pants)
+	require.NoError(t, err)
+
+	_, err = service.SchedulePriceChange(ctx, models.PriceChange{ProductCode: models.Pants, Price: 600})
+	require.NoError(t, err)
+
+	basket, err = service.AddProduct(ctx, basket.Code, models.Pants)
+	require.NoError(t, err)
+
+	item := basket.Items[models.Pants]
+	assert.Equal(t, []models.Money{750, 600}, item.UnitPrices)
+	assert.Equal(t, models.Money(1350), item.Total)
+
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(1350), basket.Total)
+}
@@ -0,0 +1,165 @@
+package cashRegister
+
+import (
+	"context"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// SavePriceList create or replace a price list.
+// require a name and the price by product code
+// it will return the price list if this is ok.
+// otherwise will return error
+func (s Service) SavePriceList(ctx context.Context, priceList models.PriceList) (models.PriceList, error) {
+	if s.priceLists == nil {
+		return models.PriceList{}, models.ErrPriceListsDisabled
+	}
+
+	if priceList.Name == "" {
+		return models.PriceList{}, models.ErrInvalidPriceList
+	}
+
+	if priceList.Currency != "" && !models.Currencies[priceList.Currency] {
+		return models.PriceList{}, models.ErrInvalidCurrency
+	}
+
+	if !priceList.ValidFrom.IsZero() && !priceList.ValidTo.IsZero() && !priceList.ValidTo.After(priceList.ValidFrom) {
+		return models.PriceList{}, models.ErrInvalidPriceList
+	}
+
+	for code, price := range priceList.Prices {
+		if _, err := s.catalog.FindProductByCode(ctx, code); err != nil {
+			return models.PriceList{}, err
+		}
+
+		if price < 0 {
+			return models.PriceList{}, models.ErrInvalidPriceList
+		}
+	}
+
+	return s.priceLists.SavePriceList(ctx, priceList)
+}
+
+// GetPriceList return a price list.
+// require a price list name
+// it will return the price list if this is ok.
+// otherwise will return  error
+func (s Service) GetPriceList(ctx context.Context, name string) (models.PriceList, error) {
+	if s.priceLists == nil {
+		return models.PriceList{}, models.ErrPriceListsDisabled
+	}
+
+	return s.priceLists.FindPriceList(ctx, name)
+}
+
+// AssignBasketPriceList set the price list used for the new lines of a basket.
+// require a basket id and price list name
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) AssignBasketPriceList(ctx context.Context, basketID, name string) (models.Basket, error) {
+	if s.priceLists == nil {
+		return models.Basket{}, models.ErrPriceListsDisabled
+	}
+
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if !basket.IsOpen() {
+		return models.Basket{}, models.ErrBasketIsClosed
+	}
+
+	if _, err = s.priceLists.FindPriceList(ctx, name); err != nil {
+		return models.Basket{}, err
+	}
+
+	basket.PriceList = name
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
+
+// AssignCustomerPriceList set the price list negotiated with a customer.
+// require a customer id and price list name
+// it will return the customer if this is ok.
+// otherwise will return  error
+func (s Service) AssignCustomerPriceList(ctx context.Context, customerID, name string) (models.Customer, error) {
+	if s.priceLists == nil {
+		return models.Customer{}, models.ErrPriceListsDisabled
+	}
+
+	if s.customers == nil {
+		return models.Customer{}, models.ErrLoyaltyDisabled
+	}
+
+	customer, err := s.customers.FindCustomerByID(ctx, customerID)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	if _, err = s.priceLists.FindPriceList(ctx, name); err != nil {
+		return models.Customer{}, err
+	}
+
+	customer.PriceList = name
+
+	return s.customers.UpdateCustomer(ctx, customer)
+}
+
+// priceListOf return the price list that applies to a basket,
+// the one of the basket has precedence over the one of the customer.
+func (s Service) priceListOf(ctx context.Context, basket models.Basket) (models.PriceList, bool, error) {
+	if s.priceLists == nil {
+		return models.PriceList{}, false, nil
+	}
+
+	name := basket.PriceList
+	if name == "" && basket.CustomerID != "" && s.customers != nil {
+		customer, err := s.customers.FindCustomerByID(ctx, basket.CustomerID)
+		if err != nil {
+			return models.PriceList{}, false, err
+		}
+
+		name = customer.PriceList
+	}
+
+	if name == "" {
+		return models.PriceList{}, false, nil
+	}
+
+	priceList, err := s.priceLists.FindPriceList(ctx, name)
+	if err != nil {
+		return models.PriceList{}, false, err
+	}
+
+	return priceList, true, nil
+}
+
+// applyPriceList replace the price of the product of a new item
+// when the price list of the basket has one.
+// Prices in another currency are converted to the base currency.
+func applyPriceList(item models.Item, priceList models.PriceList, at time.Time) (models.Item, error) {
+	price, ok := priceList.Price(item.Product.Code, at)
+	if !ok && item.Product.Parent != "" {
+		price, ok = priceList.Price(item.Product.Parent, at)
+	}
+
+	if !ok {
+		return item, nil
+	}
+
+	if priceList.Currency != "" && priceList.Currency != baseCurrency() {
+		var err error
+		price, err = convert(price, priceList.Currency, baseCurrency(), at)
+		if err != nil {
+			return models.Item{}, err
+		}
+	}
+
+	item.Product.Price = price
+	item.PriceList = priceList.Name
+	item.PromotionsDisabled = priceList.DisablePromotions
+
+	return item, nil
+}
@@ -0,0 +1,143 @@
+package cashRegister
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func TestService_SavePriceList(t *testing.T) {
+	priceListsMock := new(storagemocks.PriceListRepository)
+	priceListsMock.On("SavePriceList", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, priceList models.PriceList) models.PriceList { return priceList }, nil)
+
+	service := NewService(nil, nil, WithPriceLists(priceListsMock))
+	_, err := service.SavePriceList(context.Background(), models.PriceList{
+		Name:   "wholesale",
+		Prices: map[string]models.Money{"TSHIRT": 1200},
+	})
+	assert.NoError(t, err)
+
+	_, err = service.SavePriceList(context.Background(), models.PriceList{
+		Name:   "wholesale",
+		Prices: map[string]models.Money{"DRESS": 1200},
+	})
+	assert.Equal(t, models.ErrProductNotFound, err)
+
+	_, err = service.SavePriceList(context.Background(), models.PriceList{
+		Name:      "wholesale",
+		ValidFrom: time.Now(),
+		ValidTo:   time.Now().Add(-time.Hour),
+	})
+	assert.Equal(t, models.ErrInvalidPriceList, err)
+
+	_, err = service.SavePriceList(context.Background(), models.PriceList{
+		Name:     "wholesale",
+		Currency: "JPY",
+	})
+	assert.Equal(t, models.ErrInvalidCurrency, err)
+
+	_, err = NewService(nil, nil).SavePriceList(context.Background(), models.PriceList{Name: "wholesale"})
+	assert.Equal(t, models.ErrPriceListsDisabled, err)
+}
+
+func TestService_AddProduct_PriceList(t *testing.T) {
+	priceList := models.PriceList{
+		Name:              "wholesale",
+		Prices:            map[string]models.Money{"TSHIRT": 1200},
+		ValidFrom:         time.Now().Add(-time.Hour),
+		DisablePromotions: true,
+	}
+	tests := []struct {
+		name      string
+		basket    models.Basket
+		customer  models.Customer
+		priceList models.PriceList
+		wantPrice models.Money
+		wantList  string
+	}{
+		{
+			name:      "basket price list",
+			basket:    models.Basket{Code: "1", Items: map[string]models.Item{}, PriceList: "wholesale"},
+			priceList: priceList,
+			wantPrice: 1200,
+			wantList:  "wholesale",
+		},
+		{
+			name:      "customer price list",
+			basket:    models.Basket{Code: "1", Items: map[string]models.Item{}, CustomerID: "c1"},
+			customer:  models.Customer{ID: "c1", PriceList: "wholesale"},
+			priceList: priceList,
+			wantPrice: 1200,
+			wantList:  "wholesale",
+		},
+		{
+			name:   "expired price list",
+			basket: models.Basket{Code: "1", Items: map[string]models.Item{}, PriceList: "wholesale"},
+			priceList: models.PriceList{
+				Name:    "wholesale",
+				Prices:  map[string]models.Money{"TSHIRT": 1200},
+				ValidTo: time.Now().Add(-time.Hour),
+			},
+			wantPrice: 2000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := new(storagemocks.Repository)
+			repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(tt.basket, nil)
+			repositoryMock.On("GetItem", mock.Anything, mock.Anything, mock.Anything).Return(models.Item{}, models.ErrItemNotFound)
+			repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+				Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+			customersMock := new(storagemocks.CustomerRepository)
+			customersMock.On("FindCustomerByID", mock.Anything, "c1").Return(tt.customer, nil)
+			priceListsMock := new(storagemocks.PriceListRepository)
+			priceListsMock.On("FindPriceList", mock.Anything, "wholesale").Return(tt.priceList, nil)
+
+			service := NewService(RulesEngine, repositoryMock, WithCustomers(customersMock), WithPriceLists(priceListsMock))
+			basket, err := service.AddProduct(context.Background(), tt.basket.Code, "TSHIRT")
+			require.NoError(t, err)
+
+			item := basket.Items["TSHIRT"]
+			assert.Equal(t, tt.wantPrice, item.Product.Price)
+			assert.Equal(t, tt.wantPrice, item.Total)
+			assert.Equal(t, tt.wantList, item.PriceList)
+		})
+	}
+}
+
+func TestService_CheckoutBasket_PromotionsDisabled(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"TSHIRT": {
+				Product:            models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 1200},
+				Quantity:           3,
+				Total:              3600,
+				PriceList:          "wholesale",
+				PromotionsDisabled: true,
+			},
+		},
+	}
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.CheckoutBasket(context.Background(), basketMock.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(3600), basket.Total)
+}
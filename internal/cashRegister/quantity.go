@@ -0,0 +1,215 @@
+package cashRegister
+
+import (
+	"context"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// SetQuantity set the units of a product inside basket, adding or removing
+// the last units added, and removing the line when the quantity is zero.
+// The product is found by its code or barcodes, and weighed products can only
+// be set to zero. Removing units is a void.
+// require a basket id, product code and the quantity
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) SetQuantity(ctx context.Context, basketID, productCode string, quantity int) (models.Basket, error) {
+	if quantity < 0 {
+		return models.Basket{}, models.ErrInvalidQuantity
+	}
+
+	basket, err := s.openBasket(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	scanned, err := s.scanProduct(ctx, productCode)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	// the units of a weighed product, or of a scale barcode, can only be removed with the whole line
+	measured := scanned.product.IsWeighed() || scanned.weight > 0 || scanned.amount > 0
+	if measured && quantity > 0 {
+		return models.Basket{}, models.ErrInvalidQuantity
+	}
+
+	product, err := s.resolveVariant(ctx, scanned.product)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	current := basket.Items[product.Code].Quantity
+	switch {
+	case quantity > current:
+		return s.AddProducts(ctx, basketID, []models.ProductQuantity{{ProductCode: product.Code, Quantity: quantity - current}})
+	case quantity < current:
+		return s.removeUnits(ctx, basket, product.Code, current-quantity)
+	default:
+		return basket, nil
+	}
+}
+
+// RemoveUnit remove the last unit added of a product inside basket, the line
+// is removed with its last unit. Removing a unit is a void.
+// require a basket id and product code
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) RemoveUnit(ctx context.Context, basketID, productCode string) (models.Basket, error) {
+	product, err := s.findProduct(ctx, productCode)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	basket, err := s.openBasket(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	return s.removeUnits(ctx, basket, product.Code, 1)
+}
+
+// AddProducts add units of many products into basket at once,
+// either all of them are added or none is.
+// require a basket id and the product codes with their quantities
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) AddProducts(ctx context.Context, basketID string, products []models.ProductQuantity) (models.Basket, error) {
+	if len(products) == 0 {
+		return models.Basket{}, models.ErrInvalidQuantity
+	}
+
+	basket, err := s.openBasket(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	units := make(map[string]int)
+	for _, p := range products {
+		if p.Quantity <= 0 {
+			return models.Basket{}, models.ErrInvalidQuantity
+		}
+
+		product, err := s.addUnits(ctx, &basket, p.ProductCode, p.Quantity, 0)
+		if err != nil {
+			return models.Basket{}, err
+		}
+
+		for code, quantity := range product.StockUnits(p.Quantity) {
+			units[code] += quantity
+		}
+	}
+
+	if err = s.reserve(ctx, &basket, units); err != nil {
+		return models.Basket{}, err
+	}
+
+	return s.saveUnits(ctx, basket, units, nil)
+}
+
+// removeUnits takes out of a line the last units added and stores the basket.
+func (s Service) removeUnits(ctx context.Context, basket models.Basket, code string, quantity int) (models.Basket, error) {
+	released, err := s.takeUnits(ctx, &basket, code, quantity)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	return s.saveUnits(ctx, basket, nil, released)
+}
+
+// takeUnits takes out of a line the last units added, their prices and markdowns,
+// or the whole line with all its units, and gives back the stock held for them,
+// without storing the basket. It returns the units of stock given back.
+// The units of a weighed product can only be taken with the whole line.
+func (s Service) takeUnits(ctx context.Context, basket *models.Basket, code string, quantity int) (map[string]int, error) {
+	item, ok := basket.Items[code]
+	if !ok {
+		return nil, models.ErrItemNotFound
+	}
+
+	line := quantity == item.Quantity
+	if quantity <= 0 || quantity > item.Quantity || (item.Product.IsWeighed() && !line) {
+		return nil, models.ErrInvalidQuantity
+	}
+
+	if err := useApproval(basket, models.OperationVoid); err != nil {
+		return nil, err
+	}
+
+	held := make(map[string]int, len(basket.Reservations))
+	for c, units := range basket.Reservations {
+		held[c] = units
+	}
+
+	if err := s.release(ctx, basket, item.Product.StockUnits(quantity)); err != nil {
+		return nil, err
+	}
+
+	released := make(map[string]int)
+	for c, units := range held {
+		if units > basket.Reservations[c] {
+			released[c] = units - basket.Reservations[c]
+		}
+	}
+
+	recordScan(basket, code, -quantity)
+	if line {
+		delete(basket.Items, code)
+		calculateTotal(basket)
+		return released, nil
+	}
+
+	if n := len(item.UnitPrices) - quantity; n >= 0 {
+		item.UnitPrices = item.UnitPrices[:n]
+	}
+
+	if n := len(item.UnitMarkdowns) - quantity; n >= 0 {
+		for _, markdown := range item.UnitMarkdowns[n:] {
+			item.Markdown -= markdown
+		}
+
+		item.UnitMarkdowns = item.UnitMarkdowns[:n]
+	}
+
+	item.Quantity -= quantity
+	item.WithOutDiscount()
+	item.ApplyManualDiscount()
+	basket.Items[code] = item
+	calculateTotal(basket)
+
+	return released, nil
+}
+
+// saveUnits stores a basket after changing the units of its lines. When it can't be
+// stored the units of stock held for it are given back, and the ones given back are
+// held for it again, as the stored basket is left as it was.
+func (s Service) saveUnits(ctx context.Context, basket models.Basket, held, released map[string]int) (models.Basket, error) {
+	saved, err := s.repository.UpdateBasket(ctx, basket)
+	if err == nil {
+		return saved, nil
+	}
+
+	if undoErr := s.moveHeld(ctx, basket.Code, models.MovementRelease, held); undoErr != nil {
+		return models.Basket{}, undoErr
+	}
+
+	if undoErr := s.moveHeld(ctx, basket.Code, models.MovementReserve, released); undoErr != nil {
+		return models.Basket{}, undoErr
+	}
+
+	return models.Basket{}, err
+}
+
+// openBasket returns a basket that is not closed.
+func (s Service) openBasket(ctx context.Context, basketID string) (models.Basket, error) {
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if !basket.IsOpen() {
+		return models.Basket{}, models.ErrBasketIsClosed
+	}
+
+	return basket, nil
+}
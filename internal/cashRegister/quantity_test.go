@@ -0,0 +1,237 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	catalogmemory "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func newQuantityService(t *testing.T) (Service, storage.InventoryRepository) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	inventory := memory.NewInventoryRepository(
+		models.Stock{ProductCode: models.Tshirt, OnHand: 10}, models.Stock{ProductCode: models.Pants, OnHand: 3})
+	service := NewService(RulesEngine, memory.NewRepository(), WithInventory(inventory),
+		WithCatalog(catalogmemory.NewProductRepository(models.ProductMap[models.Tshirt], models.ProductMap[models.Pants])),
+		WithMarkdowns(memory.NewMarkdownRepository()))
+
+	return service, inventory
+}
+
+func TestService_AddProducts(t *testing.T) {
+	service, inventory := newQuantityService(t)
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		products []models.ProductQuantity
+		wantErr  error
+	}{
+		{name: "no products", wantErr: models.ErrInvalidQuantity},
+		{name: "no units", products: []models.ProductQuantity{{ProductCode: models.Tshirt, Quantity: 2}, {ProductCode: models.Pants}}, wantErr: models.ErrInvalidQuantity},
+		{name: "unknown product", products: []models.ProductQuantity{{ProductCode: models.Tshirt, Quantity: 2}, {ProductCode: "SOCKS", Quantity: 1}}, wantErr: models.ErrProductNotFound},
+		{name: "not enough stock", products: []models.ProductQuantity{{ProductCode: models.Tshirt, Quantity: 2}, {ProductCode: models.Pants, Quantity: 4}}, wantErr: models.ErrInsufficientStock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.AddProducts(ctx, basket.Code, tt.products)
+			assert.Equal(t, tt.wantErr, err)
+
+			// nothing is added nor held
+			got, err := service.GetBasket(ctx, basket.Code)
+			require.NoError(t, err)
+			assert.Empty(t, got.Items)
+
+			stock, err := inventory.FindStock(ctx, models.Tshirt)
+			require.NoError(t, err)
+			assert.Equal(t, 0, stock.Reserved)
+		})
+	}
+
+	basket, err = service.AddProducts(ctx, basket.Code, []models.ProductQuantity{
+		{ProductCode: models.Tshirt, Quantity: 2}, {ProductCode: models.Pants, Quantity: 3},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, basket.Items[models.Tshirt].Quantity)
+	assert.Equal(t, []models.Money{750, 750, 750}, basket.Items[models.Pants].UnitPrices)
+	assert.Equal(t, map[string]int{models.Tshirt: 2, models.Pants: 3}, basket.Reservations)
+	assert.Equal(t, models.Money(6250), basket.Total)
+}
+
+func TestService_SetQuantity(t *testing.T) {
+	service, inventory := newQuantityService(t)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	ctx := context.Background()
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	basket, err = service.SetQuantity(ctx, basket.Code, models.Tshirt, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, basket.Items[models.Tshirt].Quantity)
+
+	// removing units is a void
+	_, err = service.SetQuantity(ctx, basket.Code, models.Tshirt, 1)
+	assert.True(t, errors.Is(err, models.ErrApprovalRequired))
+
+	configRules.Approvals.Restricted = nil
+	basket, err = service.SetQuantity(ctx, basket.Code, models.Tshirt, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, basket.Items[models.Tshirt].Quantity)
+	assert.Equal(t, []models.Money{2000}, basket.Items[models.Tshirt].UnitPrices)
+	assert.Equal(t, map[string]int{models.Tshirt: 1}, basket.Reservations)
+
+	_, err = service.SetQuantity(ctx, basket.Code, models.Tshirt, -1)
+	assert.Equal(t, models.ErrInvalidQuantity, err)
+
+	basket, err = service.SetQuantity(ctx, basket.Code, models.Tshirt, 0)
+	require.NoError(t, err)
+	assert.Empty(t, basket.Items)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 10}, stock)
+}
+
+func TestService_RemoveUnit(t *testing.T) {
+	service, inventory := newQuantityService(t)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	configRules.Approvals.Restricted = nil
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, models.Pants)
+	require.NoError(t, err)
+
+	_, err = service.SaveMarkdown(ctx, models.Markdown{Name: "summer", Products: []string{models.Pants},
+		Start: time.Now(), Steps: []models.MarkdownStep{{Percent: 20}}})
+	require.NoError(t, err)
+
+	basket, err = service.AddProduct(ctx, basket.Code, models.Pants)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Money{750, 600}, basket.Items[models.Pants].UnitPrices)
+	assert.Equal(t, models.Money(150), basket.Items[models.Pants].Markdown)
+
+	// the last unit added is removed with its price and markdown
+	basket, err = service.RemoveUnit(ctx, basket.Code, models.Pants)
+	require.NoError(t, err)
+
+	item := basket.Items[models.Pants]
+	assert.Equal(t, 1, item.Quantity)
+	assert.Equal(t, []models.Money{750}, item.UnitPrices)
+	assert.Equal(t, models.Money(0), item.Markdown)
+	assert.Equal(t, models.Money(750), basket.Total)
+
+	stock, err := inventory.FindStock(ctx, models.Pants)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stock.Reserved)
+
+	basket, err = service.RemoveUnit(ctx, basket.Code, models.Pants)
+	require.NoError(t, err)
+	assert.Empty(t, basket.Items)
+
+	_, err = service.RemoveUnit(ctx, basket.Code, models.Pants)
+	assert.Equal(t, models.ErrItemNotFound, err)
+}
+
+func TestService_RemoveProduct_KeepsStockWhenNotStored(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+
+	basketMock := models.Basket{
+		Code: "1",
+		Items: map[string]models.Item{
+			models.Tshirt: {Product: models.ProductMap[models.Tshirt], Quantity: 2, Total: 4000, UnitPrices: []models.Money{2000, 2000}},
+		},
+		Reservations: map[string]int{models.Tshirt: 2},
+		Approvals:    []models.Approval{{Operation: models.OperationVoid, ManagerID: "M-001"}},
+	}
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).Return(models.Basket{}, errors.New("storage is down"))
+
+	inventory := memory.NewInventoryRepository(models.Stock{ProductCode: models.Tshirt, OnHand: 10, Reserved: 2})
+	service := NewService(RulesEngine, repositoryMock, WithInventory(inventory))
+	ctx := context.Background()
+
+	_, err := service.RemoveProduct(ctx, "1", models.Tshirt)
+	assert.EqualError(t, err, "storage is down")
+
+	// the basket still has the units, so they're held again
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 10, Reserved: 2}, stock)
+	repositoryMock.AssertNotCalled(t, "RemoveProduct", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_SetQuantity_Barcode(t *testing.T) {
+	service, inventory := newQuantityService(t)
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	// setting the same quantity twice adds the units once
+	for i := 0; i < 2; i++ {
+		basket, err = service.SetQuantity(ctx, basket.Code, "8412345000027", 2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, basket.Items[models.Tshirt].Quantity)
+	}
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stock.Reserved)
+}
+
+func TestService_SetQuantity_Weighed(t *testing.T) {
+	service, basket := newWeighedService(t)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	configRules.Approvals.Restricted = nil
+	ctx := context.Background()
+
+	_, err := service.AddProduct(ctx, basket.Code, "2100123012503")
+	require.NoError(t, err)
+
+	_, err = service.SetQuantity(ctx, basket.Code, "2100123012503", 2)
+	assert.Equal(t, models.ErrInvalidQuantity, err)
+	_, err = service.SetQuantity(ctx, basket.Code, "BANANAS", 2)
+	assert.Equal(t, models.ErrInvalidQuantity, err)
+
+	basket, err = service.SetQuantity(ctx, basket.Code, "2100123012503", 0)
+	require.NoError(t, err)
+	assert.Empty(t, basket.Items)
+}
+
+func TestService_AddProducts_StockWhenNotStored(t *testing.T) {
+	service, inventory := newQuantityService(t)
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	service.repository = failedUpdates{Repository: service.repository, err: models.ErrBasketChanged}
+	_, err = service.AddProducts(ctx, basket.Code, []models.ProductQuantity{{ProductCode: models.Tshirt, Quantity: 2}})
+	assert.Equal(t, models.ErrBasketChanged, err)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stock.Reserved)
+}
@@ -0,0 +1,75 @@
+package cashRegister
+
+import (
+	"context"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+const (
+	// RoundCash rounds only the payments in cash.
+	RoundCash = "cash"
+	// RoundAlways rounds every payment.
+	RoundAlways = "always"
+	// RoundNever doesn't round the payments.
+	RoundNever = "never"
+)
+
+// SetTender set how a basket is paid.
+// require a basket id and the tender, cash or card
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) SetTender(ctx context.Context, basketID, tender string) (models.Basket, error) {
+	if !models.Tenders[tender] {
+		return models.Basket{}, models.ErrInvalidTender
+	}
+
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if !basket.IsOpen() {
+		return models.Basket{}, models.ErrBasketIsClosed
+	}
+
+	basket.Tender = tender
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
+
+// applyCashRounding rounds the payment total of a basket to the nearest
+// increment, halves up, when the policy applies to its tender.
+// The difference is kept as the rounding adjustment of the basket, and converted
+// back to the currency of the basket with the exact exchange rate, halves up.
+func applyCashRounding(basket *models.Basket) {
+	basket.RoundingAdjustment = 0
+	basket.BaseRoundingAdjustment = 0
+
+	rounding := configRules.CashRounding
+	if rounding.Increment <= 0 || !rounding.appliesTo(basket.Tender) {
+		return
+	}
+
+	increment := int64(rounding.Increment)
+	rounded := basket.PaymentTotal.Div(increment, models.RoundHalfUp) * models.Money(increment)
+	basket.RoundingAdjustment = rounded - basket.PaymentTotal
+	basket.PaymentTotal = rounded
+
+	basket.BaseRoundingAdjustment = basket.RoundingAdjustment
+	if rate := basket.ExchangeRate.Rat(); rate != nil {
+		basket.BaseRoundingAdjustment = basket.RoundingAdjustment.MulRat(rate.Inv(rate), models.RoundHalfUp)
+	}
+}
+
+// appliesTo reports whether the payments with the given tender are rounded.
+func (r CashRounding) appliesTo(tender string) bool {
+	switch r.Policy {
+	case RoundAlways:
+		return true
+	case RoundCash:
+		return tender == models.TenderCash
+	default:
+		return false
+	}
+}
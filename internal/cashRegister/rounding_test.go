@@ -0,0 +1,103 @@
+package cashRegister
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func TestApplyCashRounding(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	tests := []struct {
+		name           string
+		policy         string
+		tender         string
+		total          models.Money
+		wantTotal      models.Money
+		wantAdjustment models.Money
+	}{
+		{name: "cash rounded down", policy: RoundCash, tender: models.TenderCash, total: 3412, wantTotal: 3410, wantAdjustment: -2},
+		{name: "cash rounded up", policy: RoundCash, tender: models.TenderCash, total: 3413, wantTotal: 3415, wantAdjustment: 2},
+		{name: "cash already rounded", policy: RoundCash, tender: models.TenderCash, total: 3250, wantTotal: 3250},
+		{name: "card not rounded", policy: RoundCash, tender: models.TenderCard, total: 3413, wantTotal: 3413},
+		{name: "card rounded always", policy: RoundAlways, tender: models.TenderCard, total: 3417, wantTotal: 3415, wantAdjustment: -2},
+		{name: "never rounded", policy: RoundNever, tender: models.TenderCash, total: 3413, wantTotal: 3413},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configRules.CashRounding.Policy = tt.policy
+			basket := models.Basket{Tender: tt.tender, PaymentTotal: tt.total}
+
+			applyCashRounding(&basket)
+			assert.Equal(t, tt.wantTotal, basket.PaymentTotal)
+			assert.Equal(t, tt.wantAdjustment, basket.RoundingAdjustment)
+		})
+	}
+}
+
+func TestApplyCashRounding_BaseAdjustment(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+
+	// 0.02 at 4 per EUR is 0.005 EUR, rounded half up to the cent
+	basket := models.Basket{Tender: models.TenderCash, PaymentTotal: 3413, ExchangeRate: models.NewRate(big.NewRat(4, 1))}
+	applyCashRounding(&basket)
+	assert.Equal(t, models.Money(2), basket.RoundingAdjustment)
+	assert.Equal(t, models.Money(1), basket.BaseRoundingAdjustment)
+}
+
+func TestService_CheckoutBasket_CashRounding(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	basketMock := models.Basket{
+		Code: "1",
+		Items: map[string]models.Item{
+			"VOUCHER": {Product: models.ProductMap["VOUCHER"], Quantity: 1, Total: 500},
+			"TSHIRT":  {Product: models.ProductMap["TSHIRT"], Quantity: 1, Total: 2000},
+			"PANTS":   {Product: models.ProductMap["PANTS"], Quantity: 1, Total: 750},
+		},
+		PaymentCurrency: models.USD,
+		Tender:          models.TenderCash,
+	}
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.CheckoutBasket(context.Background(), "1")
+	require.NoError(t, err)
+
+	// 34.13 USD paid in cash is rounded to 34.15
+	assert.Equal(t, models.Money(3250), basket.Total)
+	assert.Equal(t, models.Money(2), basket.RoundingAdjustment)
+	assert.Equal(t, models.Money(3415), basket.PaymentTotal)
+	// and the 0.02 USD are 0.0190 EUR, 0.02 halves up
+	assert.Equal(t, models.Money(2), basket.BaseRoundingAdjustment)
+	assert.Equal(t, models.Money(3252), basket.Tendered())
+}
+
+func TestService_SetTender(t *testing.T) {
+	basketMock := models.Basket{Code: "1", Items: map[string]models.Item{}}
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.SetTender(context.Background(), "1", models.TenderCash)
+	require.NoError(t, err)
+	assert.Equal(t, models.TenderCash, basket.Tender)
+
+	_, err = service.SetTender(context.Background(), "1", "cheque")
+	assert.Equal(t, models.ErrInvalidTender, err)
+}
@@ -50,9 +50,27 @@ func RulesEngine(request models.Item) []Rule {
 
 // discountBuyingTwoGetOneFree function
 // Check if client buy 1 or more the same type
-// gift one free
-func discountBuyingTwoGetOneFree(item models.Item, _ Rule) models.Item {
-	item.Total = item.Product.Price * float64(item.Quantity-1)
+// gift one free, on the lines of a variant with less units nothing is free.
+// The units keep the prices they were scanned at, and the cheapest one is the free one.
+func discountBuyingTwoGetOneFree(item models.Item, rule Rule) models.Item {
+	if item.Quantity < rule.Quantity {
+		return item
+	}
+
+	if item.HasPriceOverride() || len(item.UnitPrices) != item.Quantity {
+		item.Total = item.Product.Price.Mul(item.Quantity - 1)
+		return item
+	}
+
+	var total, free models.Money
+	for i, price := range item.UnitPrices {
+		total += price
+		if i == 0 || price < free {
+			free = price
+		}
+	}
+
+	item.Total = total - free
 
 	return item
 }
@@ -61,8 +79,8 @@ func discountBuyingTwoGetOneFree(item models.Item, _ Rule) models.Item {
 // Check if client buy 3 or more the same type
 // then we will apply a new price
 func discount3OrMore(item models.Item, rule Rule) models.Item {
-	var discountAmount float64
-	discountAmount = rule.NewPrice * float64(item.Quantity)
+	var discountAmount models.Money
+	discountAmount = rule.NewPrice.Mul(item.Quantity)
 	item.Total = discountAmount
 
 	return item
@@ -21,10 +21,10 @@ func Test_getRules(t *testing.T) {
 				Product: models.Product{
 					Code:  "UNKNOWN",
 					Name:  "UNKNOWN",
-					Price: 5,
+					Price: 500,
 				},
 				Quantity: 1,
-				Total:    5,
+				Total:    500,
 			},
 			want: []Rule{},
 		},
@@ -63,7 +63,7 @@ func Test_getRules(t *testing.T) {
 					Desc:     "If you buy 3 or more, the price per unit should be 19.00€.",
 					Product:  "TSHIRT",
 					Quantity: 3,
-					NewPrice: 19,
+					NewPrice: 1900,
 					fn:       discount3OrMore,
 				},
 			},
@@ -84,3 +84,41 @@ func Test_getRules(t *testing.T) {
 		})
 	}
 }
+
+func TestDiscountBuyingTwoGetOneFree(t *testing.T) {
+	rule := Rule{Name: "buy_two_by_one_free", Product: "VOUCHER", Quantity: 2}
+	voucher := models.Product{Code: "VOUCHER", Name: "VOUCHER", Price: 500}
+
+	tests := []struct {
+		name string
+		item models.Item
+		want models.Money
+	}{
+		{
+			name: "one unit",
+			item: models.Item{Product: voucher, Quantity: 1, UnitPrices: []models.Money{500}, Total: 500},
+			want: 500,
+		},
+		{
+			name: "units at the same price",
+			item: models.Item{Product: voucher, Quantity: 3, UnitPrices: []models.Money{500, 500, 500}, Total: 1500},
+			want: 1000,
+		},
+		{
+			name: "units scanned at different prices",
+			item: models.Item{Product: voucher, Quantity: 3, UnitPrices: []models.Money{500, 400, 450}, Total: 1350},
+			want: 950,
+		},
+		{
+			name: "units without their prices",
+			item: models.Item{Product: voucher, Quantity: 2, Total: 1000},
+			want: 500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := discountBuyingTwoGetOneFree(tt.item, rule)
+			assert.Equal(t, tt.want, got.Total)
+		})
+	}
+}
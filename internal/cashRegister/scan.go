@@ -0,0 +1,87 @@
+package cashRegister
+
+import (
+	"context"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// SetCashier set the cashier at the till of a basket, the scans made from then on are recorded with it.
+// require a basket id and cashier id
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) SetCashier(ctx context.Context, basketID, cashierID string) (models.Basket, error) {
+	basket, err := s.openBasket(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	basket.Cashier = cashierID
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
+
+// UndoScan undo the last scan of a basket that was not undone, the units
+// it added are removed, which is a void, and the units it removed are added again
+// at the price in force. The scan is kept in the basket marked as undone.
+// require a basket id
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) UndoScan(ctx context.Context, basketID string) (models.Basket, error) {
+	basket, err := s.openBasket(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	i := basket.LastScan()
+	if i < 0 {
+		return models.Basket{}, models.ErrNoScans
+	}
+
+	scan := basket.Scans[i]
+	product, err := s.findProduct(ctx, scan.ProductCode)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	// the weight of the pieces is not known once they are removed
+	if product.IsWeighed() && (scan.Quantity < 0 || scan.Quantity != basket.Items[scan.ProductCode].Quantity) {
+		return models.Basket{}, models.ErrScanNotUndoable
+	}
+
+	var held, released map[string]int
+	if scan.Quantity > 0 {
+		released, err = s.takeUnits(ctx, &basket, scan.ProductCode, scan.Quantity)
+		if err != nil {
+			return models.Basket{}, err
+		}
+	} else {
+		product, err = s.addUnits(ctx, &basket, scan.ProductCode, -scan.Quantity, 0)
+		if err != nil {
+			return models.Basket{}, err
+		}
+
+		held = product.StockUnits(-scan.Quantity)
+		if err = s.reserve(ctx, &basket, held); err != nil {
+			return models.Basket{}, err
+		}
+	}
+
+	// the units changed back are the undone scan, not a new one
+	basket.Scans = basket.Scans[:len(basket.Scans)-1]
+	basket.Scans[i].UndoneAt = time.Now()
+
+	return s.saveUnits(ctx, basket, held, released)
+}
+
+// recordScan adds to a basket a scan of the units added to
+// or removed from a line, with the cashier of the basket.
+func recordScan(basket *models.Basket, code string, quantity int) {
+	basket.Scans = append(basket.Scans, models.Scan{
+		ProductCode: code,
+		Quantity:    quantity,
+		Cashier:     basket.Cashier,
+		ScannedAt:   time.Now(),
+	})
+}
@@ -0,0 +1,129 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestService_Scans(t *testing.T) {
+	service, inventory := newQuantityService(t)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	ctx := context.Background()
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	_, err = service.UndoScan(ctx, basket.Code)
+	assert.Equal(t, models.ErrNoScans, err)
+
+	_, err = service.SetCashier(ctx, basket.Code, "C-001")
+	require.NoError(t, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, models.Pants)
+	require.NoError(t, err)
+	_, err = service.AddProducts(ctx, basket.Code, []models.ProductQuantity{{ProductCode: models.Tshirt, Quantity: 2}})
+	require.NoError(t, err)
+
+	_, err = service.SetCashier(ctx, basket.Code, "C-002")
+	require.NoError(t, err)
+	basket, err = service.AddProduct(ctx, basket.Code, models.Pants)
+	require.NoError(t, err)
+
+	require.Len(t, basket.Scans, 3)
+	assert.Equal(t, models.Scan{ProductCode: models.Pants, Quantity: 1, Cashier: "C-001", ScannedAt: basket.Scans[0].ScannedAt}, basket.Scans[0])
+	assert.Equal(t, models.Scan{ProductCode: models.Tshirt, Quantity: 2, Cashier: "C-001", ScannedAt: basket.Scans[1].ScannedAt}, basket.Scans[1])
+	assert.Equal(t, models.Scan{ProductCode: models.Pants, Quantity: 1, Cashier: "C-002", ScannedAt: basket.Scans[2].ScannedAt}, basket.Scans[2])
+
+	// undoing the units added is a void
+	_, err = service.UndoScan(ctx, basket.Code)
+	assert.True(t, errors.Is(err, models.ErrApprovalRequired))
+
+	configRules.Approvals.Restricted = nil
+	basket, err = service.UndoScan(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 1, basket.Items[models.Pants].Quantity)
+	assert.True(t, basket.Scans[2].IsUndone())
+	assert.Len(t, basket.Scans, 3)
+
+	basket, err = service.UndoScan(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.NotContains(t, basket.Items, models.Tshirt)
+	assert.Equal(t, map[string]int{models.Pants: 1}, basket.Reservations)
+
+	// the removal of a line is a scan too, undoing it adds the units again
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+	basket, err = service.RemoveProduct(ctx, basket.Code, models.Pants)
+	require.NoError(t, err)
+	assert.Equal(t, -1, basket.Scans[len(basket.Scans)-1].Quantity)
+
+	basket, err = service.UndoScan(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 1, basket.Items[models.Pants].Quantity)
+	assert.Equal(t, models.Money(2750), basket.Total)
+
+	// the first PANTS scan keeps its line first
+	var codes []string
+	for _, item := range basket.OrderedItems() {
+		codes = append(codes, item.Product.Code)
+	}
+	assert.Equal(t, []string{models.Pants, models.Tshirt}, codes)
+
+	stock, err := inventory.FindStock(ctx, models.Pants)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stock.Reserved)
+}
+
+func TestService_UndoScan_Weighed(t *testing.T) {
+	service, basket := newWeighedService(t)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	configRules.Approvals.Restricted = nil
+	ctx := context.Background()
+
+	_, err := service.AddWeighedProduct(ctx, basket.Code, "BANANAS", 1250)
+	require.NoError(t, err)
+	_, err = service.AddWeighedProduct(ctx, basket.Code, "BANANAS", 500)
+	require.NoError(t, err)
+
+	// the pieces of a line can't be taken apart
+	_, err = service.UndoScan(ctx, basket.Code)
+	assert.Equal(t, models.ErrScanNotUndoable, err)
+
+	basket, err = service.RemoveProduct(ctx, basket.Code, "BANANAS")
+	require.NoError(t, err)
+	assert.Empty(t, basket.Items)
+
+	_, err = service.UndoScan(ctx, basket.Code)
+	assert.Equal(t, models.ErrScanNotUndoable, err)
+}
+
+func TestService_UndoScan_StockWhenNotStored(t *testing.T) {
+	service, inventory := newQuantityService(t)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+
+	configRules.Approvals.Restricted = nil
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+	_, err = service.RemoveProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	// the units added again by the undo are not held when the basket can't be stored
+	service.repository = failedUpdates{Repository: service.repository, err: models.ErrBasketChanged}
+	_, err = service.UndoScan(ctx, basket.Code)
+	assert.Equal(t, models.ErrBasketChanged, err)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stock.Reserved)
+}
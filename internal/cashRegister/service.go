@@ -2,8 +2,10 @@ package cashRegister
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/patriciabonaldy/cash_register/internal/catalog"
 	"github.com/patriciabonaldy/cash_register/internal/models"
 	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
 )
@@ -13,11 +15,48 @@ import (
 type Service struct {
 	rulesEngine func(request models.Item) []Rule
 	repository  storage.Repository
+	customers   storage.CustomerRepository
+	priceLists  storage.PriceListRepository
+	markdowns   storage.MarkdownRepository
+	catalog     catalog.ProductRepository
+	inventory   storage.InventoryRepository
+	// lockouts of the managers with wrong PINs, shared by the copies of the service.
+	lockouts *lockouts
+}
+
+// Option configures the optional dependencies of a Service.
+type Option func(s *Service)
+
+// WithCustomers enables loyalty accounts stored in the given repository.
+func WithCustomers(customers storage.CustomerRepository) Option {
+	return func(s *Service) {
+		s.customers = customers
+	}
+}
+
+// WithPriceLists enables customer-specific price lists stored in the given repository.
+func WithPriceLists(priceLists storage.PriceListRepository) Option {
+	return func(s *Service) {
+		s.priceLists = priceLists
+	}
+}
+
+// WithCatalog sets the catalog the products are taken from,
+// by default it's a memory catalog with the products of models.ProductMap.
+func WithCatalog(products catalog.ProductRepository) Option {
+	return func(s *Service) {
+		s.catalog = products
+	}
 }
 
 // NewService returns the default Service interface implementation.
-func NewService(rules func(request models.Item) []Rule, repository storage.Repository) Service {
-	return Service{rulesEngine: rules, repository: repository}
+func NewService(rules func(request models.Item) []Rule, repository storage.Repository, opts ...Option) Service {
+	s := Service{rulesEngine: rules, repository: repository, catalog: defaultCatalog(), lockouts: newLockouts()}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	return s
 }
 
 // CreateBasket create a basket.
@@ -34,6 +73,17 @@ func (s Service) CreateBasket(ctx context.Context) (models.Basket, error) {
 		return models.Basket{}, err
 	}
 
+	variants := AssignVariants(basket.Code)
+	if len(variants) == 0 {
+		return basket, nil
+	}
+
+	basket.Variants = variants
+	basket, err = s.repository.UpdateBasket(ctx, basket)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
 	return basket, nil
 }
 
@@ -50,11 +100,31 @@ func (s Service) GetBasket(ctx context.Context, id string) (models.Basket, error
 	return basket, nil
 }
 
-// RemoveBasket remove a basket.
+// RemoveBasket remove an open basket and release the stock held for it.
+// Removing a basket with lines cancels them, so it needs the approval of a void.
 // it will remove basket if this is ok.
 // otherwise will return error
 func (s Service) RemoveBasket(ctx context.Context, id string) error {
-	err := s.repository.RemoveBasket(ctx, id)
+	basket, err := s.repository.FindBasketByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !basket.IsOpen() {
+		return models.ErrBasketIsClosed
+	}
+
+	if len(basket.Items) > 0 {
+		if err = useApproval(&basket, models.OperationVoid); err != nil {
+			return err
+		}
+	}
+
+	if err = s.releaseAll(ctx, &basket); err != nil {
+		return err
+	}
+
+	err = s.repository.RemoveBasket(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -67,35 +137,83 @@ func (s Service) RemoveBasket(ctx context.Context, id string) error {
 // it will return a basket if this is ok.
 // otherwise will return  error
 func (s Service) AddProduct(ctx context.Context, basketID, productCode string) (models.Basket, error) {
-	var basket, err = s.repository.FindBasketByID(ctx, basketID)
+	return s.addProduct(ctx, basketID, productCode, 0)
+}
+
+func (s Service) addProduct(ctx context.Context, basketID, productCode string, weight models.Weight) (models.Basket, error) {
+	basket, err := s.openBasket(ctx, basketID)
 	if err != nil {
 		return models.Basket{}, err
 	}
 
-	if basket.Close {
-		return models.Basket{}, models.ErrBasketIsClosed
+	product, err := s.addUnits(ctx, &basket, productCode, 1, weight)
+	if err != nil {
+		return models.Basket{}, err
 	}
 
-	item, err := s.repository.GetItem(ctx, basketID, productCode)
+	held := product.StockUnits(1)
+	if err = s.reserve(ctx, &basket, held); err != nil {
+		return models.Basket{}, err
+	}
+
+	return s.saveUnits(ctx, basket, held, nil)
+}
+
+// addUnits adds units of a product to a basket, without storing it, each one at the price
+// in force, and checks the purchase limits. It returns the product of the line.
+func (s Service) addUnits(ctx context.Context, basket *models.Basket, productCode string, quantity int, weight models.Weight) (models.Product, error) {
+	scanned, err := s.scanProduct(ctx, productCode)
 	if err != nil {
-		item, err = s.createItem(basket, productCode)
-		if err != nil {
-			return models.Basket{}, err
-		}
+		return models.Product{}, err
 	}
 
-	item.Quantity++
-	item.WithOutDiscount()
-	code := item.Product.Code
-	basket.Items[code] = item
-	basket.CalculateTotal()
+	scanned, err = scanned.measure(weight)
+	if err != nil {
+		return models.Product{}, err
+	}
 
-	basket, err = s.repository.UpdateBasket(ctx, basket)
+	product, err := s.resolveVariant(ctx, scanned.product)
 	if err != nil {
-		return models.Basket{}, err
+		return models.Product{}, err
 	}
 
-	return basket, nil
+	if !product.Active {
+		return models.Product{}, models.ErrProductInactive
+	}
+
+	priced, err := s.priceItem(ctx, *basket, product, time.Now())
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	item, ok := basket.Items[product.Code]
+	if !ok {
+		// the markdown of the units is added below with their prices
+		item = priced
+		item.Markdown = 0
+	}
+
+	for i := 0; i < quantity; i++ {
+		if product.IsWeighed() {
+			item.ScanAmounts = append(item.ScanAmounts, scanned.weight.Price(priced.Product.Price)+scanned.amount)
+		} else {
+			item.UnitPrices = append(item.UnitPrices, priced.Product.Price)
+			item.UnitMarkdowns = append(item.UnitMarkdowns, priced.Markdown)
+			item.Markdown += priced.Markdown
+		}
+
+		item.Quantity++
+		item.Weight += scanned.weight
+		item.PrintedAmount += scanned.amount
+	}
+
+	item.WithOutDiscount()
+	item.ApplyManualDiscount()
+	basket.Items[item.Product.Code] = item
+	calculateTotal(basket)
+	recordScan(basket, item.Product.Code, quantity)
+
+	return product, checkLimits(basket, product)
 }
 
 // RemoveProduct remove product inside basket.
@@ -103,52 +221,73 @@ func (s Service) AddProduct(ctx context.Context, basketID, productCode string) (
 // it will return a basket if this is ok.
 // otherwise will return  error
 func (s Service) RemoveProduct(ctx context.Context, basketID, productCode string) (models.Basket, error) {
-	_, ok := models.ProductMap[productCode]
-	if !ok {
-		return models.Basket{}, models.ErrProductNotFound
+	product, err := s.findProduct(ctx, productCode)
+	if err != nil {
+		return models.Basket{}, err
 	}
 
-	var basket, err = s.repository.FindBasketByID(ctx, basketID)
+	basket, err := s.openBasket(ctx, basketID)
 	if err != nil {
 		return models.Basket{}, err
 	}
 
-	if basket.Close {
-		return models.Basket{}, models.ErrBasketIsClosed
+	item, ok := basket.Items[product.Code]
+	if !ok {
+		return models.Basket{}, models.ErrItemNotFound
 	}
 
-	basket, err = s.repository.RemoveProduct(ctx, basketID, productCode)
+	released, err := s.takeUnits(ctx, &basket, product.Code, item.Quantity)
 	if err != nil {
 		return models.Basket{}, err
 	}
 
-	return basket, nil
+	return s.saveUnits(ctx, basket, nil, released)
 }
 
-func (s Service) createItem(basket models.Basket, productCode string) (models.Item, error) {
-	if basket.Close {
-		return models.Item{}, models.ErrBasketIsClosed
+// priceItem returns a new item of a product with the price in force at the given time,
+// in the base currency and from the price list of the basket if it has one.
+// Otherwise the price is marked down, with the markdown of a unit in the item.
+func (s Service) priceItem(ctx context.Context, basket models.Basket, product models.Product, at time.Time) (models.Item, error) {
+	product, err := s.priceAt(ctx, product, at)
+	if err != nil {
+		return models.Item{}, err
 	}
 
-	product, ok := models.ProductMap[productCode]
-	if !ok {
-		return models.Item{}, models.ErrProductNotFound
+	price, err := convert(product.Price, product.Currency, baseCurrency(), at)
+	if err != nil {
+		return models.Item{}, err
 	}
 
-	item, ok := basket.Items[product.Code]
-	if !ok {
-		_item := models.Item{
-			Product: product,
+	product.Price, product.Currency = price, baseCurrency()
+	item := models.Item{
+		Product: product,
+	}
+
+	priceList, found, err := s.priceListOf(ctx, basket)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	if found {
+		item, err = applyPriceList(item, priceList, at)
+		if err != nil {
+			return models.Item{}, err
 		}
-		_item.WithOutDiscount()
+	}
 
-		return _item, nil
+	if item.PriceList == "" && !product.IsWeighed() {
+		item, err = s.applyMarkdown(ctx, item, at)
+		if err != nil {
+			return models.Item{}, err
+		}
 	}
 
+	item.WithOutDiscount()
+
 	return item, nil
 }
 
-// CheckoutBasket close a basket.
+// CheckoutBasket close a basket, it goes from open to checked out.
 // require a basket id
 // it will return a basket if this is ok.
 // otherwise will return  error
@@ -159,21 +298,119 @@ func (s Service) CheckoutBasket(ctx context.Context, basketID string) (models.Ba
 		return models.Basket{}, err
 	}
 
+	now := time.Now()
+	if err = basket.MoveTo(models.StateCheckedOut, now); err != nil {
+		return models.Basket{}, err
+	}
+
+	basket.EmployeeDiscount = 0
+	units := make(map[string]int)
 	for _, item := range basket.Items {
-		rulesItem := s.rulesEngine(item)
+		units[item.Product.PromotionCode()] += item.Quantity
+	}
+
+	for _, item := range basket.Items {
+		item.WithOutDiscount()
+		var rulesItem, applied []Rule
+		if !item.PromotionsDisabled && !item.HasPriceOverride() {
+			rulesItem = s.rulesEngine(promotionItem(item, units))
+		}
+
+		regular := item.Total
+		item.Promotions = nil
 		for _, r := range rulesItem {
-			item = r.fn(item, r)
+			r = applyVariants(basket, r)
+			// a promotion is not applied to the units marked down below its price
+			if promoted := r.fn(item, r); promoted.Total < item.Total {
+				item = promoted
+				item.Promotions = append(item.Promotions, string(r.Name))
+				applied = append(applied, r)
+			}
+		}
+
+		item.PromotionDiscount = regular - item.Total
+		item.ApplyManualDiscount()
+		if basket.EmployeeID != "" {
+			item = employeeDiscount(item, applied)
+			basket.EmployeeDiscount += item.EmployeeDiscount
+		}
+
+		if item, err = s.costItem(ctx, item, now); err != nil {
+			return models.Basket{}, err
 		}
 
 		basket.Items[item.Product.Code] = item
 	}
 
-	basket.CalculateTotal()
-	basket.Close = true
-	basket, err = s.repository.UpdateBasket(ctx, basket)
+	calculateTotal(&basket)
+	for code, item := range basket.Items {
+		if !item.Product.IsKit() {
+			continue
+		}
+
+		if basket.Items[code], err = s.allocateKit(ctx, item, now); err != nil {
+			return models.Basket{}, err
+		}
+	}
+
+	if basket.CustomerID != "" {
+		basket, err = s.settleLoyalty(ctx, basket)
+		if err != nil {
+			return models.Basket{}, err
+		}
+	}
+
+	if err = settlePayment(&basket, now); err != nil {
+		return models.Basket{}, err
+	}
+
+	applyCashRounding(&basket)
+	sold, err := s.commitStock(ctx, &basket)
 	if err != nil {
 		return models.Basket{}, err
 	}
 
-	return basket, nil
+	// the points are saved before the basket, so the redeemed ones are checked again,
+	// and put back with the stock when it can't be stored, so the checkout can be retried.
+	if err = s.creditLoyalty(ctx, basket); err != nil {
+		if undoErr := s.undoAll(ctx, sold, models.MovementReturn); undoErr != nil {
+			return models.Basket{}, undoErr
+		}
+
+		return models.Basket{}, err
+	}
+
+	basket.CheckedOutAt = now
+	stored, err := s.repository.UpdateBasket(ctx, basket)
+	if err != nil {
+		if undoErr := s.reverseLoyalty(ctx, basket); undoErr != nil {
+			return models.Basket{}, undoErr
+		}
+
+		if undoErr := s.undoAll(ctx, sold, models.MovementReturn); undoErr != nil {
+			return models.Basket{}, undoErr
+		}
+
+		return models.Basket{}, err
+	}
+
+	return stored, nil
+}
+
+// ExperimentResults summarizes an experiment.
+// require an experiment name
+// it will return conversion and revenue per variant if this is ok.
+// otherwise will return  error
+func (s Service) ExperimentResults(ctx context.Context, name string) (models.ExperimentResult, error) {
+	experiment, ok := configRules.Experiments[name]
+	if !ok {
+		return models.ExperimentResult{}, models.ErrExperimentNotFound
+	}
+
+	baskets, err := s.repository.ListBaskets(ctx)
+	if err != nil {
+		return models.ExperimentResult{}, err
+	}
+
+	return experimentResults(experiment, baskets), nil
 }
@@ -2,6 +2,7 @@ package cashRegister
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
 	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
 )
 
@@ -58,6 +60,7 @@ func TestService_GetBasketBasket_Success(t *testing.T) {
 
 func TestService_Remove_Basket_Success(t *testing.T) {
 	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(models.NewBasket("4200f350-4fa5-11ec-a386-1e003b1e5256"), nil)
 	repositoryMock.On("RemoveBasket", mock.Anything, mock.Anything).Return(nil)
 
 	service := NewService(nil, repositoryMock)
@@ -69,7 +72,7 @@ func TestService_Remove_Basket_Success(t *testing.T) {
 
 func TestService_Remove_Basket_Unsuccess(t *testing.T) {
 	repositoryMock := new(storagemocks.Repository)
-	repositoryMock.On("RemoveBasket", mock.Anything, mock.Anything).Return(models.ErrBasketNotFound)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(models.Basket{}, models.ErrBasketNotFound)
 
 	service := NewService(nil, repositoryMock)
 	err := service.RemoveBasket(context.Background(), "4200f350-4fa5-11ec-a386-1e003b1e5256")
@@ -84,7 +87,7 @@ func TestService_AddProduct_First_Time_Success(t *testing.T) {
 		Product: models.Product{
 			Code:  "TSHIRT",
 			Name:  "Summer T-Shirt",
-			Price: 20,
+			Price: 2000,
 		},
 	}
 
@@ -95,7 +98,7 @@ func TestService_AddProduct_First_Time_Success(t *testing.T) {
 		Items: map[string]models.Item{
 			"TSHIRT": itemMock,
 		},
-		Total: 20,
+		Total: 2000,
 	}
 	basketmock := models.Basket{
 		Code:  "4200f350-4fa5-11ec-a386-1e003b1e5256",
@@ -121,10 +124,10 @@ func TestService_AddProduct_Success(t *testing.T) {
 		Product: models.Product{
 			Code:  "TSHIRT",
 			Name:  "Summer T-Shirt",
-			Price: 20,
+			Price: 2000,
 		},
 		Quantity: 2,
-		Total:    40,
+		Total:    4000,
 	}
 	basketExpected := models.Basket{
 		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
@@ -134,13 +137,13 @@ func TestService_AddProduct_Success(t *testing.T) {
 				Product: models.Product{
 					Code:  "PANTS",
 					Name:  "Summer Pants",
-					Price: 7.5,
+					Price: 750,
 				},
 				Quantity: 1,
-				Total:    7.5,
+				Total:    750,
 			},
 		},
-		Total: 20,
+		Total: 2000,
 	}
 	repositoryMock.On("GetItem", mock.Anything, mock.Anything, mock.Anything).Return(itemMock, nil)
 	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketExpected, nil)
@@ -152,22 +155,22 @@ func TestService_AddProduct_Success(t *testing.T) {
 				Product: models.Product{
 					Code:  "TSHIRT",
 					Name:  "Summer T-Shirt",
-					Price: 20,
+					Price: 2000,
 				},
 				Quantity: 3,
-				Total:    45,
+				Total:    4500,
 			},
 			"PANTS": {
 				Product: models.Product{
 					Code:  "PANTS",
 					Name:  "Summer Pants",
-					Price: 7.5,
+					Price: 750,
 				},
 				Quantity: 1,
-				Total:    7.5,
+				Total:    750,
 			},
 		},
-		Total: 52.5,
+		Total: 5250,
 	}
 	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(basketExpected, nil)
 
@@ -178,20 +181,31 @@ func TestService_AddProduct_Success(t *testing.T) {
 }
 
 func TestService_Remove_Product_Success(t *testing.T) {
-	basketExpected := models.Basket{
-		Code:  "4200f350-4fa5-11ec-a386-1e003b1e5256",
-		Items: make(map[string]models.Item),
-		Total: 0,
+	basketMock := models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"TSHIRT": {
+				Product:  models.Product{Code: "TSHIRT", Name: "Summer T-Shirt", Price: 2000},
+				Quantity: 1,
+				Total:    2000,
+			},
+		},
+		Total:     2000,
+		Approvals: []models.Approval{{Operation: models.OperationVoid, ManagerID: "M-001"}},
 	}
 	repositoryMock := new(storagemocks.Repository)
-	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketExpected, nil)
-	repositoryMock.On("RemoveProduct", mock.Anything, mock.Anything, mock.Anything).Return(basketExpected, nil).Once()
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	// the basket is stored once, without the line
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil).Once()
 
 	service := NewService(nil, repositoryMock)
 	basket, err := service.RemoveProduct(context.Background(), "4200f350-4fa5-11ec-a386-1e003b1e5256", "TSHIRT")
 	repositoryMock.AssertExpectations(t)
+	repositoryMock.AssertNotCalled(t, "RemoveProduct", mock.Anything, mock.Anything, mock.Anything)
 	assert.NoError(t, err)
-	assert.Equal(t, basketExpected, basket)
+	assert.Empty(t, basket.Items)
+	assert.Equal(t, models.Money(0), basket.Total)
 }
 
 func TestService_Remove_Product_UnSuccess(t *testing.T) {
@@ -207,7 +221,7 @@ func TestService_Remove_Product_UnSuccess(t *testing.T) {
 		Code:  "4200f350-4fa5-11ec-a386-1e003b1e5256",
 		Items: make(map[string]models.Item),
 		Total: 0,
-		Close: true,
+		State: models.StateCheckedOut,
 	}
 	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketExpected, nil)
 	repositoryMock.On("RemoveProduct", mock.Anything, mock.Anything, mock.Anything).Return(basketExpected, nil)
@@ -223,31 +237,31 @@ func TestService_CheckoutBasket(t *testing.T) {
 				Product: models.Product{
 					Code:  "VOUCHER",
 					Name:  "Gift Card",
-					Price: 5,
+					Price: 500,
 				},
 				Quantity: 2,
-				Total:    10,
+				Total:    1000,
 			},
 			"TSHIRT": {
 				Product: models.Product{
 					Code:  "TSHIRT",
 					Name:  "Summer T-Shirt",
-					Price: 20,
+					Price: 2000,
 				},
 				Quantity: 3,
-				Total:    60,
+				Total:    6000,
 			},
 			"PANTS": {
 				Product: models.Product{
 					Code:  "PANTS",
 					Name:  "Summer Pants",
-					Price: 7.5,
+					Price: 750,
 				},
 				Quantity: 1,
-				Total:    7.5,
+				Total:    750,
 			},
 		},
-		Total: 20,
+		Total: 2000,
 	}
 	basketExpected := models.Basket{
 		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
@@ -256,32 +270,32 @@ func TestService_CheckoutBasket(t *testing.T) {
 				Product: models.Product{
 					Code:  "VOUCHER",
 					Name:  "Gift Card",
-					Price: 5,
+					Price: 500,
 				},
 				Quantity: 2,
-				Total:    10,
+				Total:    1000,
 			},
 			"TSHIRT": {
 				Product: models.Product{
 					Code:  "TSHIRT",
 					Name:  "Summer T-Shirt",
-					Price: 20,
+					Price: 2000,
 				},
 				Quantity: 3,
-				Total:    45,
+				Total:    4500,
 			},
 			"PANTS": {
 				Product: models.Product{
 					Code:  "PANTS",
 					Name:  "Summer Pants",
-					Price: 7.5,
+					Price: 750,
 				},
 				Quantity: 1,
-				Total:    7.5,
+				Total:    750,
 			},
 		},
-		Total: 74.5,
-		Close: true,
+		Total: 7450,
+		State: models.StateCheckedOut,
 	}
 
 	repositoryMock := new(storagemocks.Repository)
@@ -298,3 +312,37 @@ func TestService_CheckoutBasket(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, basketExpected, basket)
 }
+
+func TestService_CheckoutBasket_Examples(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	tests := []struct {
+		items []string
+		want  models.Money
+	}{
+		{items: []string{"VOUCHER", "TSHIRT", "PANTS"}, want: models.NewMoney(32, 50)},
+		{items: []string{"VOUCHER", "TSHIRT", "VOUCHER"}, want: models.NewMoney(25, 0)},
+		{items: []string{"TSHIRT", "TSHIRT", "TSHIRT", "VOUCHER", "TSHIRT"}, want: models.NewMoney(81, 0)},
+		{items: []string{"VOUCHER", "TSHIRT", "VOUCHER", "VOUCHER", "PANTS", "TSHIRT", "TSHIRT"}, want: models.NewMoney(74, 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.items, ","), func(t *testing.T) {
+			ctx := context.Background()
+			service := NewService(RulesEngine, memory.NewRepository())
+
+			basket, err := service.CreateBasket(ctx)
+			require.NoError(t, err)
+
+			for _, code := range tt.items {
+				_, err = service.AddProduct(ctx, basket.Code, code)
+				require.NoError(t, err)
+			}
+
+			basket, err = service.CheckoutBasket(ctx, basket.Code)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, basket.Total)
+		})
+	}
+}
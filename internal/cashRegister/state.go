@@ -0,0 +1,176 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// PayBasket mark a checked out basket as paid.
+// require a basket id
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) PayBasket(ctx context.Context, basketID string) (models.Basket, error) {
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if err = basket.MoveTo(models.StatePaid, time.Now()); err != nil {
+		return models.Basket{}, err
+	}
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
+
+// VoidBasket cancel a basket before it's paid, which is a void when it has lines.
+// The stock held for an open basket is released, and the one sold to a checked out basket
+// is put back with the loyalty points of its customer.
+// require a basket id
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) VoidBasket(ctx context.Context, basketID string) (models.Basket, error) {
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	from := basket.CurrentState()
+	if err = basket.MoveTo(models.StateVoided, time.Now()); err != nil {
+		return models.Basket{}, err
+	}
+
+	// cancelling a basket without lines is not a void, as in RemoveBasket
+	if len(basket.Items) > 0 {
+		if err = useApproval(&basket, models.OperationVoid); err != nil {
+			return models.Basket{}, err
+		}
+	}
+
+	if from != models.StateOpen {
+		return s.undoSale(ctx, basket)
+	}
+
+	// the voided basket is stored first, so only one void releases its stock.
+	if basket, err = s.repository.UpdateBasket(ctx, basket); err != nil {
+		return models.Basket{}, err
+	}
+
+	return s.releaseStored(ctx, basket)
+}
+
+// RefundBasket give back the payment of a paid basket, its stock is put back
+// with the loyalty points of its customer. Refunds over the large refund
+// amount require the approval of a manager.
+// require a basket id
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) RefundBasket(ctx context.Context, basketID string) (models.Basket, error) {
+	basket, err := s.repository.FindBasketByID(ctx, basketID)
+	if err != nil {
+		return models.Basket{}, err
+	}
+
+	if err = basket.MoveTo(models.StateRefunded, time.Now()); err != nil {
+		return models.Basket{}, err
+	}
+
+	if basket.Total > configRules.Approvals.LargeRefund {
+		if err = useApproval(&basket, models.OperationRefund); err != nil {
+			return models.Basket{}, err
+		}
+	}
+
+	return s.undoSale(ctx, basket)
+}
+
+// ExpireBaskets expire the open baskets without scans for longer than
+// the expiration of the baskets, and release the stock held for them.
+// it will return the number of baskets expired if this is ok.
+// otherwise will return  error
+func (s Service) ExpireBaskets(ctx context.Context, now time.Time) (int, error) {
+	expireAfter := configRules.Baskets.ExpireAfter
+	if expireAfter <= 0 {
+		return 0, nil
+	}
+
+	baskets, err := s.repository.ListBaskets(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var expired int
+	for _, basket := range baskets {
+		active := lastActivity(basket)
+		if !basket.IsOpen() || active.IsZero() || now.Sub(active) < expireAfter {
+			continue
+		}
+
+		if err = basket.MoveTo(models.StateExpired, now); err != nil {
+			return expired, err
+		}
+
+		if basket, err = s.repository.UpdateBasket(ctx, basket); err != nil {
+			if errors.Is(err, models.ErrBasketChanged) {
+				continue
+			}
+
+			return expired, err
+		}
+
+		if _, err = s.releaseStored(ctx, basket); err != nil {
+			return expired, err
+		}
+
+		expired++
+	}
+
+	return expired, nil
+}
+
+// releaseStored releases the stock held for a basket already stored in its
+// final state, and stores it without the reservations.
+func (s Service) releaseStored(ctx context.Context, basket models.Basket) (models.Basket, error) {
+	if err := s.releaseAll(ctx, &basket); err != nil {
+		return models.Basket{}, err
+	}
+
+	return s.repository.UpdateBasket(ctx, basket)
+}
+
+// undoSale stores a checked out basket voided or refunded, and puts back its stock and
+// the loyalty points of its customer. The points are taken back first, as the customer
+// could have spent them, and given again when the basket can't be stored, so only one
+// void or refund of the basket puts back its stock.
+func (s Service) undoSale(ctx context.Context, basket models.Basket) (models.Basket, error) {
+	if err := s.reverseLoyalty(ctx, basket); err != nil {
+		return models.Basket{}, err
+	}
+
+	stored, err := s.repository.UpdateBasket(ctx, basket)
+	if err != nil {
+		if undoErr := s.creditLoyalty(ctx, basket); undoErr != nil {
+			return models.Basket{}, undoErr
+		}
+
+		return models.Basket{}, err
+	}
+
+	if err = s.returnStock(ctx, stored); err != nil {
+		return models.Basket{}, err
+	}
+
+	return stored, nil
+}
+
+// lastActivity returns the time of the last scan of a basket,
+// or the time it was opened when it has none.
+func lastActivity(basket models.Basket) time.Time {
+	if i := len(basket.Scans) - 1; i >= 0 && basket.Scans[i].ScannedAt.After(basket.OpenedAt) {
+		return basket.Scans[i].ScannedAt
+	}
+
+	return basket.OpenedAt
+}
@@ -0,0 +1,296 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func newStateService(t *testing.T) (Service, storage.InventoryRepository, storage.CustomerRepository) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	inventory := memory.NewInventoryRepository(models.Stock{ProductCode: models.Tshirt, OnHand: 10})
+	customers := memory.NewCustomerRepository()
+	service := NewService(RulesEngine, memory.NewRepository(), WithInventory(inventory), WithCustomers(customers))
+
+	return service, inventory, customers
+}
+
+func TestService_BasketLifecycle(t *testing.T) {
+	service, inventory, customers := newStateService(t)
+	ctx := context.Background()
+
+	customer, err := service.CreateCustomer(ctx, "Pepito", "standard")
+	require.NoError(t, err)
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.StateOpen, basket.State)
+
+	_, err = service.AttachCustomer(ctx, basket.Code, customer.ID)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	// an open basket can't be paid nor refunded
+	_, err = service.PayBasket(ctx, basket.Code)
+	assert.Equal(t, &models.TransitionError{From: models.StateOpen, To: models.StatePaid}, err)
+	_, err = service.RefundBasket(ctx, basket.Code)
+	assert.Equal(t, &models.TransitionError{From: models.StateOpen, To: models.StateRefunded}, err)
+
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.StateCheckedOut, basket.State)
+	assert.Equal(t, basket.CheckedOutAt, basket.TransitionedAt(models.StateCheckedOut))
+
+	// the lines of a checked out basket can't be changed
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	assert.Equal(t, models.ErrBasketIsClosed, err)
+	_, err = service.RefundBasket(ctx, basket.Code)
+	assert.True(t, errors.Is(err, models.ErrInvalidTransition))
+
+	basket, err = service.PayBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatePaid, basket.State)
+
+	_, err = service.VoidBasket(ctx, basket.Code)
+	assert.Equal(t, &models.TransitionError{From: models.StatePaid, To: models.StateVoided}, err)
+
+	// refunds up to the large refund amount don't require approval
+	basket, err = service.RefundBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.StateRefunded, basket.State)
+	assert.Equal(t, []string{models.StateOpen, models.StateCheckedOut, models.StatePaid},
+		[]string{basket.Transitions[0].From, basket.Transitions[1].From, basket.Transitions[2].From})
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 10}, stock)
+
+	customer, err = customers.FindCustomerByID(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, customer.Points)
+
+	_, err = service.PayBasket(ctx, basket.Code)
+	assert.Equal(t, &models.TransitionError{From: models.StateRefunded, To: models.StatePaid}, err)
+}
+
+func TestService_RefundBasket_Large(t *testing.T) {
+	service, _, _ := newStateService(t)
+	defer func() { require.NoError(t, LoadRulesConfig()) }()
+	require.NoError(t, LoadManagers("testdata/managers.yml"))
+
+	configRules.Approvals.LargeRefund = models.NewMoney(10, 0)
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	_, err = service.PayBasket(ctx, basket.Code)
+	require.NoError(t, err)
+
+	_, err = service.RefundBasket(ctx, basket.Code)
+	assert.Equal(t, &models.ApprovalRequiredError{Operation: models.OperationRefund}, err)
+
+	_, err = service.Approve(ctx, basket.Code, models.OperationRefund, "M-001", "1234")
+	require.NoError(t, err)
+
+	basket, err = service.RefundBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.StateRefunded, basket.State)
+}
+
+func TestService_VoidBasket(t *testing.T) {
+	service, inventory, _ := newStateService(t)
+	require.NoError(t, LoadManagers("testdata/managers.yml"))
+	ctx := context.Background()
+
+	open, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, open.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	checkedOut, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, checkedOut.Code, models.Tshirt)
+	require.NoError(t, err)
+	_, err = service.CheckoutBasket(ctx, checkedOut.Code)
+	require.NoError(t, err)
+
+	// voiding a basket is a void
+	_, err = service.VoidBasket(ctx, open.Code)
+	assert.True(t, errors.Is(err, models.ErrApprovalRequired))
+
+	for _, code := range []string{open.Code, checkedOut.Code} {
+		_, err = service.Approve(ctx, code, models.OperationVoid, "M-001", "1234")
+		require.NoError(t, err)
+
+		basket, err := service.VoidBasket(ctx, code)
+		require.NoError(t, err)
+		assert.Equal(t, models.StateVoided, basket.State)
+		assert.Empty(t, basket.Reservations)
+	}
+
+	// the unit held is released and the one sold is put back
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 10}, stock)
+
+	_, err = service.AddProduct(ctx, open.Code, models.Tshirt)
+	assert.Equal(t, models.ErrBasketIsClosed, err)
+
+	// an empty basket is voided without approval
+	empty, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	empty, err = service.VoidBasket(ctx, empty.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.StateVoided, empty.State)
+}
+
+func TestService_ExpireBaskets(t *testing.T) {
+	service, inventory, _ := newStateService(t)
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	basket, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	lastScan := basket.Scans[0].ScannedAt
+	expired, err := service.ExpireBaskets(ctx, lastScan.Add(configRules.Baskets.ExpireAfter-time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 0, expired)
+
+	expired, err = service.ExpireBaskets(ctx, lastScan.Add(configRules.Baskets.ExpireAfter))
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+
+	basket, err = service.GetBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.StateExpired, basket.State)
+	assert.Empty(t, basket.Reservations)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stock.Reserved)
+
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	assert.Equal(t, &models.TransitionError{From: models.StateExpired, To: models.StateCheckedOut}, err)
+}
+
+// readBarrier makes the readers of a basket wait until all of them have read it.
+type readBarrier struct {
+	storage.Repository
+	sync.WaitGroup
+}
+
+func (r *readBarrier) FindBasketByID(ctx context.Context, id string) (models.Basket, error) {
+	basket, err := r.Repository.FindBasketByID(ctx, id)
+	r.Done()
+	r.Wait()
+
+	return basket, err
+}
+
+func TestService_RefundBasket_Concurrent(t *testing.T) {
+	service, inventory, customers := newStateService(t)
+	ctx := context.Background()
+
+	customer, err := service.CreateCustomer(ctx, "Pepito", "standard")
+	require.NoError(t, err)
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AttachCustomer(ctx, basket.Code, customer.ID)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	require.NoError(t, err)
+	_, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	_, err = service.PayBasket(ctx, basket.Code)
+	require.NoError(t, err)
+
+	// every refund reads the paid basket before any of them stores it
+	read := &readBarrier{Repository: service.repository}
+	read.Add(10)
+	service.repository = read
+
+	var wg sync.WaitGroup
+	var refunded int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.RefundBasket(ctx, basket.Code)
+			if err == nil {
+				atomic.AddInt32(&refunded, 1)
+				return
+			}
+
+			// the points earned are taken back once too
+			assert.True(t, errors.Is(err, models.ErrBasketChanged) || errors.Is(err, models.ErrInvalidTransition) ||
+				errors.Is(err, models.ErrNotEnoughPoints), err)
+		}()
+	}
+	wg.Wait()
+
+	// the stock and the points are put back once
+	assert.Equal(t, int32(1), refunded)
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 10}, stock)
+
+	customer, err = customers.FindCustomerByID(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, customer.Points)
+}
+
+func TestService_RemoveBasket(t *testing.T) {
+	service, inventory, _ := newStateService(t)
+	require.NoError(t, LoadManagers("testdata/managers.yml"))
+	ctx := context.Background()
+
+	checkedOut, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, checkedOut.Code, models.Tshirt)
+	require.NoError(t, err)
+	_, err = service.CheckoutBasket(ctx, checkedOut.Code)
+	require.NoError(t, err)
+
+	// only open baskets are removed
+	err = service.RemoveBasket(ctx, checkedOut.Code)
+	assert.Equal(t, models.ErrBasketIsClosed, err)
+
+	open, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+	_, err = service.AddProduct(ctx, open.Code, models.Tshirt)
+	require.NoError(t, err)
+
+	// removing a basket with lines is a void
+	err = service.RemoveBasket(ctx, open.Code)
+	assert.True(t, errors.Is(err, models.ErrApprovalRequired))
+
+	_, err = service.Approve(ctx, open.Code, models.OperationVoid, "M-001", "1234")
+	require.NoError(t, err)
+	require.NoError(t, service.RemoveBasket(ctx, open.Code))
+
+	_, err = service.GetBasket(ctx, open.Code)
+	assert.Equal(t, models.ErrBasketNotFound, err)
+
+	stock, err := inventory.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 9}, stock)
+}
@@ -0,0 +1,66 @@
+package cashRegister
+
+import (
+	"sort"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// calculateTotal computes the tax of every line of a basket,
+// after its discounts, and then the total of the basket.
+func calculateTotal(basket *models.Basket) {
+	basket.Currency = baseCurrency()
+	basket.PricesIncludeTax = configRules.Taxes.PricesIncludeTax
+	apportionPoints(basket)
+	for code, item := range basket.Items {
+		basket.Items[code] = applyTax(item, basket.PricesIncludeTax)
+	}
+
+	basket.CalculateTotal()
+}
+
+// apportionPoints splits the points discount of a basket between its lines in
+// proportion to their totals, so it's a discount their tax is computed after.
+// The cents lost by the rounding go to the greatest line.
+func apportionPoints(basket *models.Basket) {
+	codes := make([]string, 0, len(basket.Items))
+	for code := range basket.Items {
+		codes = append(codes, code)
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		a, b := basket.Items[codes[i]], basket.Items[codes[j]]
+		if a.Total != b.Total {
+			return a.Total < b.Total
+		}
+
+		return codes[i] < codes[j]
+	})
+
+	weights := make([]models.Money, len(codes))
+	for i, code := range codes {
+		weights[i] = basket.Items[code].Total
+	}
+
+	shares := basket.PointsDiscount.Allocate(weights)
+	for i, code := range codes {
+		item := basket.Items[code]
+		item.PointsDiscount = shares[i]
+		basket.Items[code] = item
+	}
+}
+
+// applyTax computes the tax of a line with the rate of its category, on its amount
+// after the points discount. When prices include tax the tax is the part of the amount
+// over the net one, otherwise it's the rate over the amount. Each line is rounded half
+// up to the cent.
+func applyTax(item models.Item, pricesIncludeTax bool) models.Item {
+	item.TaxRate = configRules.Taxes.Rates[item.TaxCategory()]
+	if pricesIncludeTax {
+		item.Tax = item.Taxable().PercentIncluded(item.TaxRate, models.RoundHalfUp)
+		return item
+	}
+
+	item.Tax = item.Taxable().Percent(item.TaxRate, models.RoundHalfUp)
+	return item
+}
@@ -0,0 +1,151 @@
+package cashRegister
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/storagemocks"
+)
+
+func taxBasketMock() models.Basket {
+	return models.Basket{
+		Code: "4200f350-4fa5-11ec-a386-1e003b1e5256",
+		Items: map[string]models.Item{
+			"VOUCHER": {
+				Product:  models.ProductMap["VOUCHER"],
+				Quantity: 1,
+				Total:    500,
+			},
+			"TSHIRT": {
+				Product:  models.ProductMap["TSHIRT"],
+				Quantity: 3,
+				Total:    6000,
+			},
+			"PANTS": {
+				Product:  models.ProductMap["PANTS"],
+				Quantity: 1,
+				Total:    750,
+			},
+		},
+	}
+}
+
+func TestService_CheckoutBasket_TaxInclusive(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(taxBasketMock(), nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.CheckoutBasket(context.Background(), "4200f350-4fa5-11ec-a386-1e003b1e5256")
+	require.NoError(t, err)
+
+	// the tax of TSHIRT is computed over the price of the promotion
+	assert.Equal(t, models.Money(5700), basket.Items["TSHIRT"].Total)
+	assert.Equal(t, models.Money(989), basket.Items["TSHIRT"].Tax)
+	assert.Equal(t, models.Money(130), basket.Items["PANTS"].Tax)
+	assert.Equal(t, models.Money(0), basket.Items["VOUCHER"].Tax)
+
+	want := []models.TaxSummary{
+		{Category: models.TaxOutOfScope, Rate: 0, Net: 500, Tax: 0, Gross: 500},
+		{Category: models.TaxStandard, Rate: 21, Net: 5331, Tax: 1119, Gross: 6450},
+	}
+	assert.True(t, basket.PricesIncludeTax)
+	assert.Equal(t, want, basket.Taxes)
+	assert.Equal(t, models.Money(1119), basket.Tax)
+	assert.Equal(t, models.Money(6950), basket.Total)
+}
+
+func TestService_CheckoutBasket_TaxExclusive(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+	configRules.Taxes.PricesIncludeTax = false
+	defer func() { configRules.Taxes.PricesIncludeTax = true }()
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(taxBasketMock(), nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.CheckoutBasket(context.Background(), "4200f350-4fa5-11ec-a386-1e003b1e5256")
+	require.NoError(t, err)
+
+	assert.Equal(t, models.Money(1197), basket.Items["TSHIRT"].Tax)
+	assert.Equal(t, models.Money(158), basket.Items["PANTS"].Tax)
+
+	want := []models.TaxSummary{
+		{Category: models.TaxOutOfScope, Rate: 0, Net: 500, Tax: 0, Gross: 500},
+		{Category: models.TaxStandard, Rate: 21, Net: 6450, Tax: 1355, Gross: 7805},
+	}
+	assert.False(t, basket.PricesIncludeTax)
+	assert.Equal(t, want, basket.Taxes)
+	assert.Equal(t, models.Money(8305), basket.Total)
+}
+
+func TestService_CheckoutBasket_TaxAfterDiscounts(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	basketMock := taxBasketMock()
+	basketMock.EmployeeID = "E-001"
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+
+	service := NewService(RulesEngine, repositoryMock)
+	basket, err := service.CheckoutBasket(context.Background(), basketMock.Code)
+	require.NoError(t, err)
+
+	// 57.00 of the promotion less 20% of employee discount
+	assert.Equal(t, models.Money(4560), basket.Items["TSHIRT"].Total)
+	assert.Equal(t, models.Money(791), basket.Items["TSHIRT"].Tax)
+}
+
+func TestService_CheckoutBasket_TaxAfterPoints(t *testing.T) {
+	err := LoadRulesConfig()
+	require.NoError(t, err)
+
+	basketMock := taxBasketMock()
+	basketMock.CustomerID = "c1"
+	basketMock.PointsRedeemed = 1000
+	basketMock.PointsDiscount = 1000
+	customer := models.Customer{ID: "c1", Tier: models.TierStandard, Points: 1000}
+
+	repositoryMock := new(storagemocks.Repository)
+	repositoryMock.On("FindBasketByID", mock.Anything, mock.Anything).Return(basketMock, nil)
+	repositoryMock.On("UpdateBasket", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, basket models.Basket) models.Basket { return basket }, nil)
+	customersMock := new(storagemocks.CustomerRepository)
+	customersMock.On("FindCustomerByID", mock.Anything, "c1").Return(customer, nil)
+	customersMock.On("AddPoints", mock.Anything, "c1", mock.Anything).Return(customer, nil)
+
+	service := NewService(RulesEngine, repositoryMock, WithCustomers(customersMock))
+	basket, err := service.CheckoutBasket(context.Background(), basketMock.Code)
+	require.NoError(t, err)
+
+	// 10.00 of points split by the totals of the lines, the cents left go to TSHIRT
+	assert.Equal(t, models.Money(822), basket.Items["TSHIRT"].PointsDiscount)
+	assert.Equal(t, models.Money(107), basket.Items["PANTS"].PointsDiscount)
+	assert.Equal(t, models.Money(71), basket.Items["VOUCHER"].PointsDiscount)
+	assert.Equal(t, models.Money(847), basket.Items["TSHIRT"].Tax)
+	assert.Equal(t, models.Money(112), basket.Items["PANTS"].Tax)
+
+	want := []models.TaxSummary{
+		{Category: models.TaxOutOfScope, Rate: 0, Net: 429, Tax: 0, Gross: 429},
+		{Category: models.TaxStandard, Rate: 21, Net: 4562, Tax: 959, Gross: 5521},
+	}
+	assert.Equal(t, want, basket.Taxes)
+	assert.Equal(t, models.Money(959), basket.Tax)
+	assert.Equal(t, models.Money(5950), basket.Total)
+}
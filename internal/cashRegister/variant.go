@@ -0,0 +1,106 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// ListVariants return the variants of a product of the catalog sorted by code.
+// require a product code
+// it will return the variants if this is ok.
+// otherwise will return error
+func (s Service) ListVariants(ctx context.Context, code string) ([]models.Product, error) {
+	if _, err := s.catalog.FindProductByCode(ctx, code); err != nil {
+		return nil, err
+	}
+
+	return s.catalog.ListVariants(ctx, code)
+}
+
+// resolveVariant returns the product sold when it's scanned, a variant takes
+// the price, category and tax category it doesn't have from its parent. The products with variants
+// can't be sold, only their variants can.
+func (s Service) resolveVariant(ctx context.Context, product models.Product) (models.Product, error) {
+	if product.Parent == "" {
+		variants, err := s.catalog.ListVariants(ctx, product.Code)
+		if err != nil {
+			return models.Product{}, err
+		}
+
+		if len(variants) > 0 {
+			return models.Product{}, models.ErrVariantRequired
+		}
+
+		return product, nil
+	}
+
+	parent, err := s.catalog.FindProductByCode(ctx, product.Parent)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	parent, err = s.priceAt(ctx, parent, time.Now())
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	if product.Price == 0 {
+		product.Price, product.Currency = parent.Price, parent.Currency
+	}
+
+	if product.Category == "" {
+		product.Category = parent.Category
+	}
+
+	if product.TaxCategory == "" {
+		product.TaxCategory = parent.TaxCategory
+	}
+
+	product.Active = product.Active && parent.Active
+
+	return product, nil
+}
+
+// validateVariant checks the parent of a variant exists and isn't a variant,
+// and that the variant has no variants of its own.
+func (s Service) validateVariant(ctx context.Context, product models.Product) error {
+	if product.Parent == product.Code {
+		return models.ErrInvalidVariant
+	}
+
+	parent, err := s.catalog.FindProductByCode(ctx, product.Parent)
+	if errors.Is(err, models.ErrProductNotFound) {
+		return models.ErrInvalidVariant
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if parent.Parent != "" || parent.IsWeighed() || product.IsWeighed() {
+		return models.ErrInvalidVariant
+	}
+
+	variants, err := s.catalog.ListVariants(ctx, product.Code)
+	if err != nil {
+		return err
+	}
+
+	if len(variants) > 0 {
+		return models.ErrInvalidVariant
+	}
+
+	return nil
+}
+
+// promotionItem returns the item the promotions of a line are chosen with,
+// the units of all the variants of a parent count towards its promotions.
+func promotionItem(item models.Item, units map[string]int) models.Item {
+	item.Product.Code = item.Product.PromotionCode()
+	item.Quantity = units[item.Product.Code]
+
+	return item
+}
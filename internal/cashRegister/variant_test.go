@@ -0,0 +1,93 @@
+package cashRegister
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	catalogmemory "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func TestService_CreateProduct_Variant(t *testing.T) {
+	service := NewService(RulesEngine, nil, WithCatalog(catalogmemory.NewProductRepository(
+		models.ProductMap[models.Tshirt], models.ProductMap[models.Pants])))
+	ctx := context.Background()
+
+	variant, err := service.CreateProduct(ctx, models.Product{
+		Code: "TSHIRT-M-BLUE", Name: "Summer T-Shirt M blue", Parent: models.Tshirt, Size: "M", Colour: "blue",
+	})
+	require.NoError(t, err)
+	assert.True(t, variant.Active)
+
+	variants, err := service.ListVariants(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Product{variant}, variants)
+
+	tests := []struct {
+		name    string
+		product models.Product
+	}{
+		{name: "unknown parent", product: models.Product{Code: "HAT-M", Name: "Summer Hat M", Parent: "HAT"}},
+		{name: "parent is a variant", product: models.Product{Code: "TSHIRT-M-BLUE-2", Name: "Summer T-Shirt", Parent: "TSHIRT-M-BLUE"}},
+		{name: "its own parent", product: models.Product{Code: "PANTS", Name: "Summer Pants", Parent: models.Pants}},
+		{name: "parent with variants", product: models.Product{Code: models.Tshirt, Name: "Summer T-Shirt", Parent: models.Pants}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateProduct(ctx, tt.product)
+			assert.Equal(t, models.ErrInvalidVariant, err)
+		})
+	}
+}
+
+func TestService_CheckoutBasket_Variants(t *testing.T) {
+	require.NoError(t, LoadRulesConfig())
+	require.NoError(t, LoadExchangeRates(""))
+
+	service := NewService(RulesEngine, memory.NewRepository(), WithCatalog(catalogmemory.NewProductRepository(
+		models.ProductMap[models.Tshirt],
+		models.Product{Code: "TSHIRT-S", Name: "Summer T-Shirt S", Parent: models.Tshirt, Size: "S", Barcodes: []string{"8412345000041"}, Active: true},
+		models.Product{Code: "TSHIRT-XL", Name: "Summer T-Shirt XL", Parent: models.Tshirt, Size: "XL", Price: 2200, Active: true},
+	)))
+	ctx := context.Background()
+
+	basket, err := service.CreateBasket(ctx)
+	require.NoError(t, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, models.Tshirt)
+	assert.Equal(t, models.ErrVariantRequired, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, "8412345000041")
+	require.NoError(t, err)
+	basket, err = service.AddProduct(ctx, basket.Code, "TSHIRT-XL")
+	require.NoError(t, err)
+
+	// the small one is sold at the price of its parent
+	assert.Equal(t, models.Money(2000), basket.Items["TSHIRT-S"].Total)
+	assert.Equal(t, models.Money(2200), basket.Items["TSHIRT-XL"].Total)
+
+	_, err = service.AddProduct(ctx, basket.Code, "TSHIRT-XL")
+	require.NoError(t, err)
+
+	// three T-shirts of any size get the price of buy_three_or_more_new_price
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(1900), basket.Items["TSHIRT-S"].Total)
+	assert.Equal(t, models.Money(3800), basket.Items["TSHIRT-XL"].Total)
+	assert.Equal(t, models.Money(5700), basket.Total)
+}
+
+func TestDiscountBuyingTwoGetOneFree_VariantLine(t *testing.T) {
+	rule := Rule{Quantity: 2}
+	item := models.Item{Product: models.Product{Code: "VOUCHER-RED", Parent: models.Voucher, Price: 500}, Quantity: 1, Total: 500}
+
+	// the line of a variant with a single unit has nothing free
+	assert.Equal(t, models.Money(500), discountBuyingTwoGetOneFree(item, rule).Total)
+
+	item.Quantity = 3
+	assert.Equal(t, models.Money(1000), discountBuyingTwoGetOneFree(item, rule).Total)
+}
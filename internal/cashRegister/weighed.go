@@ -0,0 +1,100 @@
+package cashRegister
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// scan represents a product read at the till, with the weight or
+// the price embedded in the in-store barcode printed by a scale.
+type scan struct {
+	product models.Product
+	weight  models.Weight
+	amount  models.Money
+}
+
+// AddWeighedProduct add a weighed product into basket.
+// require a basket id, product code and the weight of the product
+// it will return a basket if this is ok.
+// otherwise will return  error
+func (s Service) AddWeighedProduct(ctx context.Context, basketID, productCode string, weight models.Weight) (models.Basket, error) {
+	if weight <= 0 {
+		return models.Basket{}, models.ErrInvalidWeight
+	}
+
+	return s.addProduct(ctx, basketID, productCode, weight)
+}
+
+// scanProduct returns the product of a product code or a barcode,
+// decoding the weight or the price of the in-store barcodes of the scales.
+func (s Service) scanProduct(ctx context.Context, code string) (scan, error) {
+	if len(code) != 13 || !models.IsBarcode(code) {
+		product, err := s.findProduct(ctx, code)
+		return scan{product: product}, err
+	}
+
+	byWeight := hasPrefix(configRules.VariableMeasure.WeightPrefixes, code)
+	byPrice := hasPrefix(configRules.VariableMeasure.PricePrefixes, code)
+	if !byWeight && !byPrice {
+		product, err := s.findProduct(ctx, code)
+		return scan{product: product}, err
+	}
+
+	gtin, err := models.ParseGTIN(code)
+	if err != nil {
+		return scan{}, err
+	}
+
+	product, err := s.catalog.FindProductByPLU(ctx, gtin[2:7])
+	if errors.Is(err, models.ErrProductNotFound) {
+		return scan{}, models.ErrUnknownBarcode
+	}
+
+	if err != nil {
+		return scan{}, err
+	}
+
+	value, _ := strconv.ParseInt(gtin[7:12], 10, 64)
+	if byWeight {
+		return scan{product: product, weight: models.Weight(value)}, nil
+	}
+
+	return scan{product: product, amount: models.Money(value)}, nil
+}
+
+func hasPrefix(prefixes []string, code string) bool {
+	for _, prefix := range prefixes {
+		if len(prefix) == 2 && code[:2] == prefix {
+			return true
+		}
+	}
+
+	return false
+}
+
+// measure checks the weight or the price of a scan matches
+// how its product is sold, the weight given at the till is used
+// when the barcode has none.
+func (sc scan) measure(weight models.Weight) (scan, error) {
+	if weight > 0 {
+		if sc.weight > 0 || sc.amount > 0 {
+			return scan{}, models.ErrInvalidWeight
+		}
+
+		sc.weight = weight
+	}
+
+	measured := sc.weight > 0 || sc.amount > 0
+	if sc.product.IsWeighed() && !measured {
+		return scan{}, models.ErrWeightRequired
+	}
+
+	if !sc.product.IsWeighed() && measured {
+		return scan{}, models.ErrInvalidWeight
+	}
+
+	return sc, nil
+}
@@ -0,0 +1,114 @@
+package cashRegister
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	catalogmemory "github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func newWeighedService(t *testing.T) (Service, models.Basket) {
+	require.NoError(t, LoadRulesConfig())
+
+	bananas := models.Product{
+		Code: "BANANAS", Name: "Bananas", Price: 199, Currency: models.EUR,
+		TaxCategory: models.TaxReduced, Unit: models.UnitKg, PLU: "00123", Active: true,
+	}
+	service := NewService(RulesEngine, memory.NewRepository(),
+		WithCatalog(catalogmemory.NewProductRepository(bananas, models.ProductMap[models.Tshirt])))
+
+	basket, err := service.CreateBasket(context.Background())
+	require.NoError(t, err)
+
+	return service, basket
+}
+
+func TestService_AddWeighedProduct(t *testing.T) {
+	service, basket := newWeighedService(t)
+	ctx := context.Background()
+
+	_, err := service.AddWeighedProduct(ctx, basket.Code, "BANANAS", 1250)
+	require.NoError(t, err)
+	basket, err = service.AddWeighedProduct(ctx, basket.Code, "BANANAS", 500)
+	require.NoError(t, err)
+
+	item := basket.Items["BANANAS"]
+	assert.Equal(t, 2, item.Quantity)
+	assert.Equal(t, models.Weight(1750), item.Weight)
+	// 1.250 kg and 0.500 kg at 1.99 per kg, each scan rounded on its own
+	assert.Equal(t, []models.Money{249, 100}, item.ScanAmounts)
+	assert.Equal(t, models.Money(349), item.Total)
+	assert.Equal(t, models.Money(349), basket.Total)
+
+	_, err = service.AddWeighedProduct(ctx, basket.Code, "BANANAS", 0)
+	assert.Equal(t, models.ErrInvalidWeight, err)
+
+	_, err = service.AddWeighedProduct(ctx, basket.Code, models.Tshirt, 500)
+	assert.Equal(t, models.ErrInvalidWeight, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, "BANANAS")
+	assert.Equal(t, models.ErrWeightRequired, err)
+}
+
+func TestService_AddWeighedProduct_PriceChange(t *testing.T) {
+	service, basket := newWeighedService(t)
+	ctx := context.Background()
+
+	_, err := service.AddWeighedProduct(ctx, basket.Code, "BANANAS", 1000)
+	require.NoError(t, err)
+
+	bananas, err := service.GetProduct(ctx, "BANANAS")
+	require.NoError(t, err)
+	bananas.Price = 299
+	_, err = service.UpdateProduct(ctx, bananas)
+	require.NoError(t, err)
+
+	// the weight scanned after the change is at the new price
+	basket, err = service.AddWeighedProduct(ctx, basket.Code, "BANANAS", 1000)
+	require.NoError(t, err)
+
+	item := basket.Items["BANANAS"]
+	assert.Equal(t, []models.Money{199, 299}, item.ScanAmounts)
+	assert.Equal(t, models.Money(498), item.Total)
+
+	basket, err = service.CheckoutBasket(ctx, basket.Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(498), basket.Total)
+}
+
+func TestService_AddProduct_VariableMeasureBarcode(t *testing.T) {
+	service, basket := newWeighedService(t)
+	ctx := context.Background()
+
+	// PLU 00123 weighing 1.250 kg
+	basket, err := service.AddProduct(ctx, basket.Code, "2100123012503")
+	require.NoError(t, err)
+
+	item := basket.Items["BANANAS"]
+	assert.Equal(t, models.Weight(1250), item.Weight)
+	assert.Equal(t, models.Money(249), item.Total)
+
+	// PLU 00123 priced 3.49 by the scale
+	basket, err = service.AddProduct(ctx, basket.Code, "2300123003499")
+	require.NoError(t, err)
+
+	item = basket.Items["BANANAS"]
+	assert.Equal(t, 2, item.Quantity)
+	assert.Equal(t, models.Weight(1250), item.Weight)
+	assert.Equal(t, models.Money(349), item.PrintedAmount)
+	assert.Equal(t, models.Money(598), basket.Total)
+
+	_, err = service.AddWeighedProduct(ctx, basket.Code, "2100123012503", 500)
+	assert.Equal(t, models.ErrInvalidWeight, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, "2100123012504")
+	assert.Equal(t, models.ErrMalformedBarcode, err)
+
+	_, err = service.AddProduct(ctx, basket.Code, "2100999010009")
+	assert.Equal(t, models.ErrUnknownBarcode, err)
+}
@@ -0,0 +1,22 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+//go:generate mockery --case=snake --outpkg=catalogmocks --output=catalogmocks --name=ProductRepository
+type ProductRepository interface {
+	CreateProduct(ctx context.Context, product models.Product) (models.Product, error)
+	FindProductByCode(ctx context.Context, code string) (models.Product, error)
+	FindProductByBarcode(ctx context.Context, barcode string) (models.Product, error)
+	FindProductByPLU(ctx context.Context, plu string) (models.Product, error)
+	ListProducts(ctx context.Context) ([]models.Product, error)
+	ListVariants(ctx context.Context, parent string) ([]models.Product, error)
+	UpdateProduct(ctx context.Context, product models.Product) (models.Product, error)
+	SavePriceChange(ctx context.Context, change models.PriceChange) (models.PriceChange, error)
+	ListPriceChanges(ctx context.Context, code string) ([]models.PriceChange, error)
+	SaveCostChange(ctx context.Context, change models.CostChange) (models.CostChange, error)
+	ListCostChanges(ctx context.Context, code string) ([]models.CostChange, error)
+}
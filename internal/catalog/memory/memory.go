@@ -0,0 +1,228 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/patriciabonaldy/cash_register/internal/catalog"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+// ProductMemory is a memory ProductRepository implementation.
+type ProductMemory struct {
+	mux      sync.Mutex
+	products map[string]models.Product
+	prices   map[string][]models.PriceChange
+	costs    map[string][]models.CostChange
+}
+
+// NewProductRepository initializes a memory implementation of catalog.ProductRepository
+// with the given products.
+func NewProductRepository(products ...models.Product) catalog.ProductRepository {
+	m := &ProductMemory{
+		products: make(map[string]models.Product),
+		prices:   make(map[string][]models.PriceChange),
+		costs:    make(map[string][]models.CostChange),
+	}
+	for _, product := range products {
+		m.products[product.Code] = product
+	}
+
+	return m
+}
+
+// CreateProduct implements the catalog.ProductRepository interface.
+func (m *ProductMemory) CreateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	if _, ok := m.products[product.Code]; ok {
+		return models.Product{}, models.ErrProductCreated
+	}
+
+	if m.barcodeInUse(product) {
+		return models.Product{}, models.ErrBarcodeInUse
+	}
+
+	m.products[product.Code] = product
+
+	return product, nil
+}
+
+// barcodeInUse reports whether another product has one of the barcodes of a product,
+// it must be called with the lock held.
+func (m *ProductMemory) barcodeInUse(product models.Product) bool {
+	for _, barcode := range product.Barcodes {
+		for _, other := range m.products {
+			if other.Code == product.Code {
+				continue
+			}
+
+			for _, b := range other.Barcodes {
+				if b == barcode {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// FindProductByCode implements the catalog.ProductRepository interface.
+func (m *ProductMemory) FindProductByCode(ctx context.Context, code string) (models.Product, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	product, ok := m.products[code]
+	if !ok {
+		return models.Product{}, models.ErrProductNotFound
+	}
+
+	return product, nil
+}
+
+// FindProductByBarcode implements the catalog.ProductRepository interface.
+func (m *ProductMemory) FindProductByBarcode(ctx context.Context, barcode string) (models.Product, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	for _, product := range m.products {
+		for _, b := range product.Barcodes {
+			if b == barcode {
+				return product, nil
+			}
+		}
+	}
+
+	return models.Product{}, models.ErrProductNotFound
+}
+
+// FindProductByPLU implements the catalog.ProductRepository interface.
+func (m *ProductMemory) FindProductByPLU(ctx context.Context, plu string) (models.Product, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	for _, product := range m.products {
+		if product.PLU != "" && product.PLU == plu {
+			return product, nil
+		}
+	}
+
+	return models.Product{}, models.ErrProductNotFound
+}
+
+// ListProducts implements the catalog.ProductRepository interface.
+func (m *ProductMemory) ListProducts(ctx context.Context) ([]models.Product, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	products := make([]models.Product, 0, len(m.products))
+	for _, product := range m.products {
+		products = append(products, product)
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].Code < products[j].Code
+	})
+
+	return products, nil
+}
+
+// ListVariants implements the catalog.ProductRepository interface.
+func (m *ProductMemory) ListVariants(ctx context.Context, parent string) ([]models.Product, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	variants := make([]models.Product, 0)
+	for _, product := range m.products {
+		if parent != "" && product.Parent == parent {
+			variants = append(variants, product)
+		}
+	}
+
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].Code < variants[j].Code
+	})
+
+	return variants, nil
+}
+
+// UpdateProduct implements the catalog.ProductRepository interface.
+func (m *ProductMemory) UpdateProduct(ctx context.Context, product models.Product) (models.Product, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	if _, ok := m.products[product.Code]; !ok {
+		return models.Product{}, models.ErrProductNotFound
+	}
+
+	if m.barcodeInUse(product) {
+		return models.Product{}, models.ErrBarcodeInUse
+	}
+
+	m.products[product.Code] = product
+
+	return product, nil
+}
+
+// SavePriceChange implements the catalog.ProductRepository interface.
+func (m *ProductMemory) SavePriceChange(ctx context.Context, change models.PriceChange) (models.PriceChange, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	if _, ok := m.products[change.ProductCode]; !ok {
+		return models.PriceChange{}, models.ErrProductNotFound
+	}
+
+	changes := append(m.prices[change.ProductCode], change)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].EffectiveFrom.Before(changes[j].EffectiveFrom)
+	})
+	m.prices[change.ProductCode] = changes
+
+	return change, nil
+}
+
+// ListPriceChanges implements the catalog.ProductRepository interface,
+// the changes are sorted by the time they take effect.
+func (m *ProductMemory) ListPriceChanges(ctx context.Context, code string) ([]models.PriceChange, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	changes := make([]models.PriceChange, len(m.prices[code]))
+	copy(changes, m.prices[code])
+
+	return changes, nil
+}
+
+// SaveCostChange implements the catalog.ProductRepository interface.
+func (m *ProductMemory) SaveCostChange(ctx context.Context, change models.CostChange) (models.CostChange, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	if _, ok := m.products[change.ProductCode]; !ok {
+		return models.CostChange{}, models.ErrProductNotFound
+	}
+
+	changes := append(m.costs[change.ProductCode], change)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].EffectiveFrom.Before(changes[j].EffectiveFrom)
+	})
+	m.costs[change.ProductCode] = changes
+
+	return change, nil
+}
+
+// ListCostChanges implements the catalog.ProductRepository interface,
+// the changes are sorted by the time they take effect.
+func (m *ProductMemory) ListCostChanges(ctx context.Context, code string) ([]models.CostChange, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	changes := make([]models.CostChange, len(m.costs[code]))
+	copy(changes, m.costs[code])
+
+	return changes, nil
+}
@@ -0,0 +1,120 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/catalog/memory"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestProductMemory(t *testing.T) {
+	repository := memory.NewProductRepository(models.ProductMap[models.Tshirt])
+	ctx := context.Background()
+	product := models.Product{Code: "SOCKS", Name: "Summer Socks", Price: 450, Active: true}
+
+	_, err := repository.FindProductByCode(ctx, product.Code)
+	assert.Equal(t, models.ErrProductNotFound, err)
+
+	_, err = repository.UpdateProduct(ctx, product)
+	assert.Equal(t, models.ErrProductNotFound, err)
+
+	_, err = repository.CreateProduct(ctx, product)
+	require.NoError(t, err)
+
+	_, err = repository.CreateProduct(ctx, product)
+	assert.Equal(t, models.ErrProductCreated, err)
+
+	product.Active = false
+	_, err = repository.UpdateProduct(ctx, product)
+	require.NoError(t, err)
+
+	got, err := repository.FindProductByCode(ctx, product.Code)
+	require.NoError(t, err)
+	assert.Equal(t, product, got)
+
+	products, err := repository.ListProducts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Product{product, models.ProductMap[models.Tshirt]}, products)
+}
+
+func TestProductMemory_Barcodes(t *testing.T) {
+	repository := memory.NewProductRepository(models.ProductMap[models.Tshirt])
+	ctx := context.Background()
+	product := models.Product{Code: "SOCKS", Name: "Summer Socks", Price: 450, Barcodes: []string{"8412345000027"}}
+
+	_, err := repository.CreateProduct(ctx, product)
+	assert.Equal(t, models.ErrBarcodeInUse, err)
+
+	product.Barcodes = []string{"4006381333931"}
+	_, err = repository.CreateProduct(ctx, product)
+	require.NoError(t, err)
+
+	tshirt := models.ProductMap[models.Tshirt]
+	tshirt.Barcodes = append(tshirt.Barcodes, "4006381333931")
+	_, err = repository.UpdateProduct(ctx, tshirt)
+	assert.Equal(t, models.ErrBarcodeInUse, err)
+
+	// a product keeps its own barcodes
+	_, err = repository.UpdateProduct(ctx, product)
+	require.NoError(t, err)
+
+	found, err := repository.FindProductByBarcode(ctx, "4006381333931")
+	require.NoError(t, err)
+	assert.Equal(t, "SOCKS", found.Code)
+}
+
+func TestProductMemory_ListVariants(t *testing.T) {
+	small := models.Product{Code: "TSHIRT-S", Name: "Summer T-Shirt S", Parent: models.Tshirt, Size: "S"}
+	large := models.Product{Code: "TSHIRT-L", Name: "Summer T-Shirt L", Parent: models.Tshirt, Size: "L"}
+	repository := memory.NewProductRepository(models.ProductMap[models.Tshirt], small, large)
+	ctx := context.Background()
+
+	variants, err := repository.ListVariants(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Product{large, small}, variants)
+
+	variants, err = repository.ListVariants(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, variants)
+}
+
+func TestProductMemory_PriceChanges(t *testing.T) {
+	repository := memory.NewProductRepository(models.ProductMap[models.Tshirt])
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := repository.SavePriceChange(ctx, models.PriceChange{ProductCode: "SOCKS", Price: 400, EffectiveFrom: now})
+	assert.Equal(t, models.ErrProductNotFound, err)
+
+	later, err := repository.SavePriceChange(ctx, models.PriceChange{ProductCode: models.Tshirt, Price: 1800, EffectiveFrom: now.Add(time.Hour)})
+	require.NoError(t, err)
+	sooner, err := repository.SavePriceChange(ctx, models.PriceChange{ProductCode: models.Tshirt, Price: 2200, EffectiveFrom: now})
+	require.NoError(t, err)
+
+	changes, err := repository.ListPriceChanges(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, []models.PriceChange{sooner, later}, changes)
+}
+
+func TestProductMemory_CostChanges(t *testing.T) {
+	repository := memory.NewProductRepository(models.ProductMap[models.Voucher])
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := repository.SaveCostChange(ctx, models.CostChange{ProductCode: "SOCKS", Cost: 200, EffectiveFrom: now})
+	assert.Equal(t, models.ErrProductNotFound, err)
+
+	later, err := repository.SaveCostChange(ctx, models.CostChange{ProductCode: models.Voucher, Cost: 300, EffectiveFrom: now.Add(time.Hour)})
+	require.NoError(t, err)
+	sooner, err := repository.SaveCostChange(ctx, models.CostChange{ProductCode: models.Voucher, Cost: 250, EffectiveFrom: now})
+	require.NoError(t, err)
+
+	changes, err := repository.ListCostChanges(ctx, models.Voucher)
+	require.NoError(t, err)
+	assert.Equal(t, []models.CostChange{sooner, later}, changes)
+}
@@ -0,0 +1,377 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Columns are the fields of the products in the import and export files,
+// only code, name and price are required.
+var Columns = []string{"code", "name", "price", "category", "barcodes", "currency", "tax_category",
+	"unit", "plu", "parent", "size", "colour", "components", "active"}
+
+// Row represents a product read from an import file,
+// Err is set when the row couldn't be read.
+type Row struct {
+	Line    int
+	Product models.Product
+	// Fields are the columns of the row, the other fields of a product
+	// are kept when it's updated.
+	Fields map[string]bool
+	Err    error
+}
+
+// Update returns the product with the fields of the row.
+func (r Row) Update(product models.Product) models.Product {
+	for field := range r.Fields {
+		switch field {
+		case "name":
+			product.Name = r.Product.Name
+		case "price":
+			product.Price = r.Product.Price
+		case "category":
+			product.Category = r.Product.Category
+		case "barcodes":
+			product.Barcodes = r.Product.Barcodes
+		case "currency":
+			product.Currency = r.Product.Currency
+		case "tax_category":
+			product.TaxCategory = r.Product.TaxCategory
+		case "unit":
+			product.Unit = r.Product.Unit
+		case "plu":
+			product.PLU = r.Product.PLU
+		case "parent":
+			product.Parent = r.Product.Parent
+		case "size":
+			product.Size = r.Product.Size
+		case "colour":
+			product.Colour = r.Product.Colour
+		case "components":
+			product.Components = r.Product.Components
+		case "active":
+			product.Active = r.Product.Active
+		}
+	}
+
+	return product
+}
+
+type record struct {
+	Code        string       `json:"code"`
+	Name        string       `json:"name"`
+	Price       models.Money `json:"price"`
+	Category    string       `json:"category"`
+	Barcodes    []string     `json:"barcodes"`
+	Currency    string       `json:"currency"`
+	TaxCategory string       `json:"tax_category"`
+	Unit        string       `json:"unit"`
+	PLU         string       `json:"plu"`
+	Parent      string       `json:"parent"`
+	Size        string       `json:"size"`
+	Colour      string       `json:"colour"`
+	Components  []component  `json:"components"`
+	// Active is true when it's missing.
+	Active *bool `json:"active"`
+}
+
+type component struct {
+	Code     string `json:"code"`
+	Quantity int    `json:"quantity"`
+}
+
+func newRecord(p models.Product) record {
+	active := p.Active
+	rec := record{
+		Code:        p.Code,
+		Name:        p.Name,
+		Price:       p.Price,
+		Category:    p.Category,
+		Barcodes:    append([]string{}, p.Barcodes...),
+		Currency:    p.Currency,
+		TaxCategory: p.TaxCategory,
+		Unit:        p.Unit,
+		PLU:         p.PLU,
+		Parent:      p.Parent,
+		Size:        p.Size,
+		Colour:      p.Colour,
+		Components:  []component{},
+		Active:      &active,
+	}
+	for _, c := range p.Components {
+		rec.Components = append(rec.Components, component{Code: c.ProductCode, Quantity: c.Quantity})
+	}
+
+	return rec
+}
+
+func (r record) product() models.Product {
+	product := models.Product{
+		Code:        r.Code,
+		Name:        r.Name,
+		Price:       r.Price,
+		Category:    r.Category,
+		Currency:    r.Currency,
+		TaxCategory: r.TaxCategory,
+		Unit:        r.Unit,
+		PLU:         r.PLU,
+		Parent:      r.Parent,
+		Size:        r.Size,
+		Colour:      r.Colour,
+		Active:      r.Active == nil || *r.Active,
+	}
+	if len(r.Barcodes) > 0 {
+		product.Barcodes = r.Barcodes
+	}
+
+	for _, c := range r.Components {
+		product.Components = append(product.Components, models.Component{ProductCode: c.Code, Quantity: c.Quantity})
+	}
+
+	return product
+}
+
+// Read returns the rows of an import file in the given format.
+// It only fails when the file as a whole can't be read,
+// the errors of each row are kept in the row.
+func Read(format string, r io.Reader) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatJSON:
+		return readJSON(r)
+	default:
+		return nil, models.ErrInvalidImportFormat
+	}
+}
+
+// Write writes the products in the given format,
+// the file can be read back by Read.
+func Write(format string, w io.Writer, products []models.Product) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, products)
+	case FormatJSON:
+		return writeJSON(w, products)
+	default:
+		return models.ErrInvalidImportFormat
+	}
+}
+
+// readCSV reads a file with a header, the columns can be in any order
+// and only code, name and price are required.
+func readCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalidImportFile, err)
+	}
+
+	index := make(map[string]int)
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, column := range header {
+		if !isColumn(strings.ToLower(strings.TrimSpace(column))) {
+			return nil, fmt.Errorf("%w: unknown column %q", models.ErrInvalidImportFile, column)
+		}
+	}
+
+	for _, column := range Columns[:3] {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", models.ErrInvalidImportFile, column)
+		}
+	}
+
+	var rows []Row
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("%w: %s", models.ErrInvalidImportFile, err)
+		}
+
+		row := Row{Line: line, Fields: make(map[string]bool)}
+		for column := range index {
+			row.Fields[column] = true
+		}
+
+		if err != nil {
+			row.Err = csv.ErrFieldCount
+			if i, ok := index["code"]; ok && i < len(fields) {
+				row.Product.Code = fields[i]
+			}
+
+			rows = append(rows, row)
+			continue
+		}
+
+		field := func(column string) string {
+			if i, ok := index[column]; ok {
+				return strings.TrimSpace(fields[i])
+			}
+
+			return ""
+		}
+
+		row.Product = models.Product{
+			Code:        field("code"),
+			Name:        field("name"),
+			Category:    field("category"),
+			Barcodes:    parseList(field("barcodes")),
+			Currency:    field("currency"),
+			TaxCategory: field("tax_category"),
+			Unit:        field("unit"),
+			PLU:         field("plu"),
+			Parent:      field("parent"),
+			Size:        field("size"),
+			Colour:      field("colour"),
+			Active:      true,
+		}
+		row.Product.Price, row.Err = models.ParseMoney(field("price"))
+		if row.Err == nil {
+			row.Product.Components, row.Err = parseComponents(field("components"))
+		}
+
+		if active := field("active"); row.Err == nil && active != "" {
+			row.Product.Active, row.Err = strconv.ParseBool(active)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseList reads the values of a field written like "A;B".
+func parseList(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	values := strings.Split(s, ";")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	return values
+}
+
+// parseComponents reads the components of a kit written like "MUG:1;TSHIRT:2".
+func parseComponents(s string) ([]models.Component, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var components []models.Component
+	for _, part := range strings.Split(s, ";") {
+		code, quantity, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, models.ErrInvalidKit
+		}
+
+		n, err := strconv.Atoi(quantity)
+		if err != nil {
+			return nil, models.ErrInvalidKit
+		}
+
+		components = append(components, models.Component{ProductCode: code, Quantity: n})
+	}
+
+	return components, nil
+}
+
+func formatComponents(components []models.Component) string {
+	parts := make([]string, 0, len(components))
+	for _, c := range components {
+		parts = append(parts, c.ProductCode+":"+strconv.Itoa(c.Quantity))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func isColumn(name string) bool {
+	for _, column := range Columns {
+		if column == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readJSON reads an array of products, each element is decoded on its own
+// so a wrong element doesn't hide the errors of the others.
+func readJSON(r io.Reader) ([]Row, error) {
+	var elements []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&elements); err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalidImportFile, err)
+	}
+
+	rows := make([]Row, 0, len(elements))
+	for i, element := range elements {
+		var rec record
+		var fields map[string]json.RawMessage
+		err := json.Unmarshal(element, &rec)
+		if err == nil {
+			err = json.Unmarshal(element, &fields)
+		}
+
+		row := Row{Line: i + 1, Product: rec.product(), Fields: make(map[string]bool), Err: err}
+		for field := range fields {
+			row.Fields[field] = true
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func writeCSV(w io.Writer, products []models.Product) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Columns); err != nil {
+		return err
+	}
+
+	for _, p := range products {
+		err := writer.Write([]string{p.Code, p.Name, p.Price.String(), p.Category, strings.Join(p.Barcodes, ";"), p.Currency, p.TaxCategory,
+			p.Unit, p.PLU, p.Parent, p.Size, p.Colour, formatComponents(p.Components), strconv.FormatBool(p.Active)})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeJSON(w io.Writer, products []models.Product) error {
+	records := make([]record, 0, len(products))
+	for _, p := range products {
+		records = append(records, newRecord(p))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
@@ -0,0 +1,120 @@
+package catalog_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/catalog"
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestRead_CSV(t *testing.T) {
+	file := "name,code,price,category,barcodes\n" +
+		"Summer Socks,SOCKS,4.50,apparel,\n" +
+		"Summer Hat,HAT,ten,apparel,\n" +
+		"Summer Cap,CAP\n"
+
+	rows, err := catalog.Read(catalog.FormatCSV, strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, catalog.Row{
+		Line:    2,
+		Product: models.Product{Code: "SOCKS", Name: "Summer Socks", Price: 450, Category: "apparel", Active: true},
+		Fields:  map[string]bool{"code": true, "name": true, "price": true, "category": true, "barcodes": true},
+	}, rows[0])
+	assert.Equal(t, 3, rows[1].Line)
+	assert.ErrorIs(t, rows[1].Err, models.ErrInvalidMoney)
+	assert.Equal(t, 4, rows[2].Line)
+	assert.Equal(t, "CAP", rows[2].Product.Code)
+	assert.Error(t, rows[2].Err)
+}
+
+func TestRead_InvalidFile(t *testing.T) {
+	_, err := catalog.Read(catalog.FormatCSV, strings.NewReader("code,name\nSOCKS,Summer Socks\n"))
+	assert.ErrorIs(t, err, models.ErrInvalidImportFile)
+
+	_, err = catalog.Read(catalog.FormatCSV, strings.NewReader("code,name,price,weight\n"))
+	assert.ErrorIs(t, err, models.ErrInvalidImportFile)
+
+	_, err = catalog.Read(catalog.FormatJSON, strings.NewReader(`{"code":"SOCKS"}`))
+	assert.ErrorIs(t, err, models.ErrInvalidImportFile)
+
+	_, err = catalog.Read("xml", strings.NewReader(""))
+	assert.Equal(t, models.ErrInvalidImportFormat, err)
+}
+
+func TestRead_JSON(t *testing.T) {
+	file := `[{"code":"SOCKS","name":"Summer Socks","price":"4.50"},{"code":"HAT","name":"Summer Hat","price":"ten"}]`
+
+	rows, err := catalog.Read(catalog.FormatJSON, strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, catalog.Row{
+		Line:    1,
+		Product: models.Product{Code: "SOCKS", Name: "Summer Socks", Price: 450, Active: true},
+		Fields:  map[string]bool{"code": true, "name": true, "price": true},
+	}, rows[0])
+	assert.Equal(t, 2, rows[1].Line)
+	assert.Error(t, rows[1].Err)
+}
+
+func TestWrite_RoundTrip(t *testing.T) {
+	products := []models.Product{
+		{Code: "PANTS", Name: "Summer Pants", Price: 750, Currency: models.USD, Category: "apparel",
+			Barcodes: []string{"4006381333931", "0036000291452"}, TaxCategory: models.TaxReduced, Active: true},
+		{Code: "PANTS-S", Name: "Summer Pants S", Parent: "PANTS", Size: "S", Colour: "blue"},
+		{Code: "KIT", Name: "Summer Kit", Price: 2500, Active: true,
+			Components: []models.Component{{ProductCode: "PANTS", Quantity: 1}, {ProductCode: "SOCKS", Quantity: 2}}},
+		{Code: "APPLES", Name: "Apples, Golden", Price: 299, Unit: models.UnitKg, PLU: "20001", Active: true},
+	}
+
+	for _, format := range []string{catalog.FormatCSV, catalog.FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, catalog.Write(format, &buf, products))
+
+			rows, err := catalog.Read(format, &buf)
+			require.NoError(t, err)
+			require.Len(t, rows, len(products))
+			for i, row := range rows {
+				require.NoError(t, row.Err)
+				assert.Equal(t, products[i], row.Product)
+				assert.Len(t, row.Fields, len(catalog.Columns))
+			}
+		})
+	}
+}
+
+func TestRead_Components(t *testing.T) {
+	file := "code,name,price,components,active\n" +
+		"KIT,Summer Kit,25.00,PANTS:1; SOCKS:2,false\n" +
+		"BOX,Summer Box,10.00,PANTS,\n" +
+		"BAG,Summer Bag,10.00,,maybe\n"
+
+	rows, err := catalog.Read(catalog.FormatCSV, strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	require.NoError(t, rows[0].Err)
+	assert.Equal(t, []models.Component{{ProductCode: "PANTS", Quantity: 1}, {ProductCode: "SOCKS", Quantity: 2}}, rows[0].Product.Components)
+	assert.False(t, rows[0].Product.Active)
+	assert.Equal(t, models.ErrInvalidKit, rows[1].Err)
+	assert.Error(t, rows[2].Err)
+}
+
+func TestRow_Update(t *testing.T) {
+	row := catalog.Row{
+		Product: models.Product{Code: "PANTS", Name: "Summer Pants", Price: 800, Active: true},
+		Fields:  map[string]bool{"code": true, "name": true, "price": true},
+	}
+	current := models.Product{Code: "PANTS", Name: "Pants", Price: 750, Category: "apparel", TaxCategory: models.TaxReduced}
+
+	want := models.Product{Code: "PANTS", Name: "Summer Pants", Price: 800, Category: "apparel", TaxCategory: models.TaxReduced}
+	assert.Equal(t, want, row.Update(current))
+}
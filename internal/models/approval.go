@@ -0,0 +1,39 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	OperationOverride = "override"
+	OperationVoid     = "void"
+	OperationRefund   = "refund"
+	// OperationBasketTotal lifts the limit of the total of a basket.
+	OperationBasketTotal = "basket_total"
+)
+
+// Approval represents the authorization of a manager
+// for a restricted operation on a basket.
+type Approval struct {
+	Operation  string
+	ManagerID  string
+	ApprovedAt time.Time
+	// UsedAt is set once the approved operation is done, an approval is used only once.
+	UsedAt time.Time
+}
+
+// ApprovalRequiredError is returned by restricted operations
+// done without the approval of a manager.
+type ApprovalRequiredError struct {
+	Operation string
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrApprovalRequired, e.Operation)
+}
+
+// Is makes errors.Is(err, ErrApprovalRequired) true for any operation.
+func (e *ApprovalRequiredError) Is(target error) bool {
+	return target == ErrApprovalRequired
+}
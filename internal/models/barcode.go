@@ -0,0 +1,50 @@
+package models
+
+import "strings"
+
+// IsBarcode reports whether a code scanned or typed at the till is a barcode
+// rather than a product code, barcodes only have digits.
+func IsBarcode(code string) bool {
+	if code == "" {
+		return false
+	}
+
+	return strings.Trim(code, "0123456789") == ""
+}
+
+// ParseGTIN validates an EAN-13 or UPC-A barcode and its check digit,
+// and returns it as a GTIN-13, UPC-A barcodes get a leading zero.
+func ParseGTIN(code string) (string, error) {
+	if !IsBarcode(code) {
+		return "", ErrMalformedBarcode
+	}
+
+	switch len(code) {
+	case 12:
+		code = "0" + code
+	case 13:
+	default:
+		return "", ErrMalformedBarcode
+	}
+
+	if checkDigit(code[:12]) != code[12] {
+		return "", ErrMalformedBarcode
+	}
+
+	return code, nil
+}
+
+// checkDigit returns the GS1 check digit of the first twelve digits of a GTIN-13:
+// from the right, the digits are weighted 3 and 1 alternately.
+func checkDigit(digits string) byte {
+	var sum int
+	for i := range digits {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,31 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestParseGTIN(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    string
+		wantErr error
+	}{
+		{name: "EAN-13", code: "4006381333931", want: "4006381333931"},
+		{name: "UPC-A", code: "036000291452", want: "0036000291452"},
+		{name: "wrong check digit", code: "4006381333932", wantErr: models.ErrMalformedBarcode},
+		{name: "wrong length", code: "40063813339", wantErr: models.ErrMalformedBarcode},
+		{name: "not digits", code: "TSHIRT", wantErr: models.ErrMalformedBarcode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := models.ParseGTIN(tt.code)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
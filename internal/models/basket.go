@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 const (
 	Voucher = "VOUCHER"
 	Tshirt  = "TSHIRT"
@@ -7,52 +9,286 @@ const (
 )
 
 var (
+	// ProductMap are the products the catalog starts with.
 	ProductMap = map[string]Product{
-		Voucher: {Code: Voucher, Name: "Gift Card", Price: 5.00},
-		Tshirt:  {Code: Tshirt, Name: "Summer T-Shirt", Price: 20.00},
-		Pants:   {Code: Pants, Name: "Summer Pants ", Price: 7.50},
+		Voucher: {Code: Voucher, Name: "Gift Card", Price: NewMoney(5, 0), Currency: EUR, Barcodes: []string{"8412345000010"}, TaxCategory: TaxOutOfScope, Active: true},
+		Tshirt:  {Code: Tshirt, Name: "Summer T-Shirt", Price: NewMoney(20, 0), Currency: EUR, Barcodes: []string{"8412345000027"}, TaxCategory: TaxStandard, Active: true},
+		Pants:   {Code: Pants, Name: "Summer Pants ", Price: NewMoney(7, 50), Currency: EUR, Barcodes: []string{"8412345000034"}, TaxCategory: TaxStandard, Active: true},
 	}
 )
 
 type Basket struct {
 	Code  string
 	Items map[string]Item
-	Total float64
-	Close bool
+	Total Money
+	// State of the basket in its lifecycle and the transitions that led to it.
+	State       string
+	Transitions []Transition
+	OpenedAt    time.Time
+	// Variants keeps the variant assigned to the basket by experiment name.
+	Variants map[string]string
+	// CustomerID is the loyalty account attached to the basket.
+	CustomerID string
+	// PointsRedeemed are the points of the customer used as discount.
+	PointsRedeemed int
+	PointsDiscount Money
+	// PointsEarned are the points credited to the customer on checkout.
+	PointsEarned int
+	// EmployeeID is set on staff purchases, for payroll deduction.
+	EmployeeID       string
+	EmployeeDiscount Money
+	// PriceList overrides the price list of the customer for new lines.
+	PriceList string
+	// Approvals granted by managers for restricted operations.
+	Approvals []Approval
+	// PricesIncludeTax tells whether the totals of the items already include their tax.
+	PricesIncludeTax bool
+	Tax              Money
+	Taxes            []TaxSummary
+	// Currency of all the amounts of the basket, the base currency of the store.
+	Currency string
+	// PaymentCurrency is the currency the customer pays in, the total is converted
+	// to it at checkout with the exchange rate in force.
+	PaymentCurrency string
+	ExchangeRate    Rate
+	PaymentTotal    Money
+	// Tender is how the basket is paid, cash or card.
+	Tender string
+	// RoundingAdjustment is the amount, in the payment currency, added to
+	// the converted total to round a cash payment. It's included in PaymentTotal.
+	// BaseRoundingAdjustment is the same adjustment in the currency of the basket.
+	RoundingAdjustment     Money
+	BaseRoundingAdjustment Money
+	// Reservations are the units of stock held for the basket by product code,
+	// they're released when they expire, ReservedAt is the time of the last one.
+	Reservations map[string]int
+	ReservedAt   time.Time
+	// Markdown is the clearance discount of the items, and PromotionDiscount
+	// the discount of the promotions of the rules engine, both already in Total.
+	Markdown          Money
+	PromotionDiscount Money
+	// CheckedOutAt is the time the basket was checked out.
+	CheckedOutAt time.Time
+	// Cashier is the one at the till, it's recorded with the scans.
+	Cashier string
+	Scans   []Scan
 }
 
 type Product struct {
 	Code  string
 	Name  string
-	Price float64
+	Price Money
+	// Currency of the price.
+	Currency string
+	// Category groups the products of the store, like apparel or gift cards.
+	Category string
+	// Barcodes are the GTINs printed on the product, like the ones
+	// of each of its suppliers. No two products share one.
+	Barcodes []string
+	// TaxCategory is standard when it's empty.
+	TaxCategory string
+	// Active is false for products deactivated in the catalog,
+	// they can't be added to baskets anymore.
+	Active bool
+	// Unit is each when it's empty, the price of the products by kg is per kilogram.
+	Unit string
+	// PLU is the item reference of a weighed product in the in-store barcodes.
+	PLU string
+	// Parent is the code of the product this one is a variant of, like a size
+	// and colour of a T-shirt. A variant without price is sold at the price of its parent.
+	Parent string
+	Size   string
+	Colour string
+	// Components of a kit, sold at the price of the kit as one line.
+	Components []Component
 }
 
 type Item struct {
-	Product  Product
-	Quantity int
-	Total    float64
+	Product          Product
+	Quantity         int
+	Total            Money
+	EmployeeDiscount Money
+	// PriceList is the name of the price list the product price was taken from.
+	PriceList          string
+	PromotionsDisabled bool
+	// Override is the manual price change of the line, if any.
+	Override       *Override
+	ManualDiscount Money
+	// PointsDiscount is the share of the points discount of the basket, it's
+	// not in Total but the tax is computed after it.
+	PointsDiscount Money
+	// Tax of the line, computed after all its discounts.
+	TaxRate float64
+	Tax     Money
+	// Weight of a weighed product, Quantity is the number of pieces scanned.
+	Weight Weight
+	// PrintedAmount is the sum of the prices printed on the barcodes
+	// of the pieces of a weighed product that were priced by the scale.
+	PrintedAmount Money
+	// ScanAmounts are the amounts of the scans of a weighed product, each one
+	// weighed at the price in force when it was scanned or printed by the scale.
+	ScanAmounts []Money
+	// UnitPrices are the prices of the units of the line, each one at the
+	// price in force when it was added. Product.Price is the one of the first unit.
+	UnitPrices []Money
+	// UnitMarkdowns are the markdowns of the units, in the order of UnitPrices.
+	UnitMarkdowns []Money
+	// Markdown is the clearance discount of the units, already in UnitPrices,
+	// MarkdownPercent is the one in force when the line was created.
+	Markdown          Money
+	MarkdownPercent   float64
+	PromotionDiscount Money
+	// Promotions are the names of the rules applied to the line at checkout.
+	Promotions []string
+	// Cost of the units sold, at the cost prices in force at checkout.
+	Cost Money
+	// Allocations of a kit line to its components, set at checkout.
+	Allocations []Allocation
+}
+
+// ProductQuantity represents the units of a product to add to a basket.
+type ProductQuantity struct {
+	ProductCode string
+	Quantity    int
 }
 
 func NewBasket(id string) Basket {
 	return Basket{
-		Code:  id,
-		Items: make(map[string]Item),
+		Code:     id,
+		Items:    make(map[string]Item),
+		State:    StateOpen,
+		OpenedAt: time.Now(),
 	}
 }
 
 func (b *Basket) CalculateTotal() {
-	var total float64
+	b.Tax, b.Taxes = b.summarizeTaxes()
+	b.Markdown, b.PromotionDiscount = 0, 0
 	for _, i := range b.Items {
-		total += i.Total
+		b.Markdown += i.MarkdownAmount()
+		b.PromotionDiscount += i.PromotionDiscount
+	}
+
+	total := b.Subtotal()
+	if !b.PricesIncludeTax {
+		total += b.Tax
+	}
+
+	total -= b.PointsDiscount
+	if total < 0 {
+		total = 0
 	}
 
 	b.Total = total
 }
 
+// Subtotal returns the amount of the items before basket level discounts.
+func (b Basket) Subtotal() Money {
+	var total Money
+	for _, i := range b.Items {
+		total += i.Total
+	}
+
+	return total
+}
+
 func (i *Item) WithOutDiscount() {
-	var discountAmount float64
+	var discountAmount Money
+
+	discountAmount = i.UnitPrice().Mul(i.Quantity)
+	switch {
+	case i.Product.IsWeighed() && !i.HasPriceOverride() && len(i.ScanAmounts) == i.Quantity:
+		discountAmount = 0
+		for _, amount := range i.ScanAmounts {
+			discountAmount += amount
+		}
+	case i.Product.IsWeighed():
+		discountAmount = i.Weight.Price(i.UnitPrice()) + i.PrintedAmount
+	case !i.HasPriceOverride() && len(i.UnitPrices) == i.Quantity:
+		discountAmount = 0
+		for _, price := range i.UnitPrices {
+			discountAmount += price
+		}
+	}
 
-	product := i.Product
-	discountAmount = product.Price * float64(i.Quantity)
 	i.Total = discountAmount
+	i.EmployeeDiscount = 0
+	i.ManualDiscount = 0
+	i.PromotionDiscount = 0
+}
+
+// Taxable returns the amount of the line after its share of the points discount,
+// the one its tax is computed on.
+func (i Item) Taxable() Money {
+	return i.Total - i.PointsDiscount
+}
+
+// MarkdownAmount returns the clearance discount of the line,
+// none when its unit price was overridden.
+func (i Item) MarkdownAmount() Money {
+	if i.HasPriceOverride() {
+		return 0
+	}
+
+	return i.Markdown
+}
+
+// UnitPrice returns the price of the product unless it was overridden.
+func (i Item) UnitPrice() Money {
+	if i.Override != nil && i.Override.Type == OverrideUnitPrice {
+		return i.Override.Amount
+	}
+
+	return i.Product.Price
+}
+
+// PromotionCode returns the code the promotions of the product target,
+// the one of its parent for the variants.
+func (p Product) PromotionCode() string {
+	if p.Parent != "" {
+		return p.Parent
+	}
+
+	return p.Code
+}
+
+// IsWeighed reports whether the product is priced per kilogram.
+func (p Product) IsWeighed() bool {
+	return p.Unit == UnitKg
+}
+
+// TaxCategory returns the tax category of the product.
+func (i Item) TaxCategory() string {
+	if i.Product.TaxCategory == "" {
+		return TaxStandard
+	}
+
+	return i.Product.TaxCategory
+}
+
+// HasPriceOverride reports whether the unit price of the line was overridden.
+func (i Item) HasPriceOverride() bool {
+	return i.Override != nil && i.Override.Type == OverrideUnitPrice
+}
+
+// ApplyManualDiscount subtracts the manual discount of the line from its total.
+func (i *Item) ApplyManualDiscount() {
+	if i.Override == nil {
+		return
+	}
+
+	var discount Money
+	switch i.Override.Type {
+	case OverrideDiscountAmount:
+		discount = i.Override.Amount
+	case OverrideDiscountPercent:
+		discount = i.Total.Percent(i.Override.Percent, RoundHalfUp)
+	}
+
+	if discount > i.Total {
+		discount = i.Total
+	}
+
+	i.Total -= discount
+	i.ManualDiscount = discount
 }
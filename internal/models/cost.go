@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	MarginByProduct   = "product"
+	MarginByCategory  = "category"
+	MarginByPromotion = "promotion"
+)
+
+// CostChange is a new cost price of a product from a given time on,
+// the price the store pays for a unit, or a kilogram of the weighed products.
+type CostChange struct {
+	ProductCode   string
+	Cost          Money
+	EffectiveFrom time.Time
+	CreatedAt     time.Time
+}
+
+// EffectiveCost returns the latest of the changes in force at the given time,
+// the changes must be sorted by EffectiveFrom.
+func EffectiveCost(changes []CostChange, at time.Time) (CostChange, bool) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		if !changes[i].EffectiveFrom.After(at) {
+			return changes[i], true
+		}
+	}
+
+	return CostChange{}, false
+}
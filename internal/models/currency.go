@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EUR = "EUR"
+	USD = "USD"
+	GBP = "GBP"
+)
+
+// Currencies are the ISO 4217 codes accepted by the store.
+var Currencies = map[string]bool{
+	EUR: true,
+	USD: true,
+	GBP: true,
+}
+
+var ErrInvalidRate = errors.New("rate is not a valid positive decimal or fraction")
+
+// Rate is an exact exchange rate, it's kept as a fraction so the inverse
+// of a rate is exact too. The zero Rate is no rate.
+type Rate struct {
+	rat *big.Rat
+}
+
+// NewRate returns the rate of a positive fraction.
+func NewRate(r *big.Rat) Rate {
+	return Rate{rat: new(big.Rat).Set(r)}
+}
+
+// ParseRate parses a positive rate, either a decimal like "1.05" or a fraction like "20/21".
+func ParseRate(s string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+
+	return Rate{rat: r}, nil
+}
+
+// IsZero reports whether there is no rate.
+func (r Rate) IsZero() bool {
+	return r.rat == nil
+}
+
+// Rat returns a copy of the fraction of the rate, nil when there is no rate.
+func (r Rate) Rat() *big.Rat {
+	if r.rat == nil {
+		return nil
+	}
+
+	return new(big.Rat).Set(r.rat)
+}
+
+// String returns the rate as a decimal like "1.05", or as a fraction like "20/21"
+// when it has no finite decimal expansion. It's empty when there is no rate.
+func (r Rate) String() string {
+	if r.rat == nil {
+		return ""
+	}
+
+	// the decimal is finite with n digits when the denominator divides 10^n,
+	// which takes at most as many digits as the bits of the denominator.
+	den, pow := r.rat.Denom(), big.NewInt(1)
+	for digits := 0; digits <= den.BitLen(); digits++ {
+		if new(big.Int).Rem(pow, den).Sign() == 0 {
+			return r.rat.FloatString(digits)
+		}
+
+		pow.Mul(pow, big.NewInt(10))
+	}
+
+	return r.rat.RatString()
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (r *Rate) UnmarshalYAML(value *yaml.Node) error {
+	rate, err := ParseRate(value.Value)
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+	return nil
+}
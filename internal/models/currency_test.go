@@ -0,0 +1,61 @@
+package models_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1.05", want: "1.05"},
+		{in: "1.10", want: "1.1"},
+		{in: "2", want: "2"},
+		{in: "20/21", want: "20/21"},
+		{in: "0", wantErr: true},
+		{in: "-1.05", wantErr: true},
+		{in: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := models.ParseRate(tt.in)
+			if tt.wantErr {
+				assert.Equal(t, models.ErrInvalidRate, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestRate_Inverse(t *testing.T) {
+	rate, err := models.ParseRate("1.05")
+	require.NoError(t, err)
+
+	// the inverse of 1.05 has no finite decimal, it's kept exact as a fraction
+	inverse := models.NewRate(new(big.Rat).Inv(rate.Rat()))
+	assert.Equal(t, "20/21", inverse.String())
+	assert.Equal(t, models.Money(2000), models.Money(2100).MulRat(inverse.Rat(), models.RoundHalfUp))
+	assert.Equal(t, "", models.Rate{}.String())
+}
+
+func TestRate_UnmarshalYAML(t *testing.T) {
+	var v struct {
+		Rate models.Rate `yaml:"rate"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte("rate: 0.85"), &v))
+	assert.Equal(t, "0.85", v.Rate.String())
+
+	assert.Error(t, yaml.Unmarshal([]byte("rate: -1"), &v))
+}
@@ -0,0 +1,17 @@
+package models
+
+const (
+	TierStandard = "standard"
+	TierSilver   = "silver"
+	TierGold     = "gold"
+)
+
+// Customer represents a loyalty account.
+type Customer struct {
+	ID     string
+	Name   string
+	Tier   string
+	Points int
+	// PriceList is the name of the price list negotiated with the customer.
+	PriceList string
+}
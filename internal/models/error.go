@@ -7,5 +7,79 @@ var (
 	ErrBasketNotFound  = errors.New("basket does not exist")
 	ErrBasketIsClosed  = errors.New("basket is closed")
 	ErrProductNotFound = errors.New("product does not exist")
-	ErrItemNotFound    = errors.New("item does not exist")
+	ErrProductCreated  = errors.New("product was created previously")
+	ErrInvalidProduct  = errors.New("product is not valid")
+	ErrProductInactive = errors.New("product is not active")
+
+	ErrInvalidTransition = errors.New("basket state transition is not allowed")
+	ErrBasketChanged     = errors.New("basket was changed meanwhile")
+
+	ErrMalformedBarcode = errors.New("barcode is not a valid EAN-13 or UPC-A")
+	ErrUnknownBarcode   = errors.New("barcode does not match any product")
+	ErrBarcodeInUse     = errors.New("barcode belongs to another product")
+
+	ErrInvalidPriceChange = errors.New("price change is not valid")
+	ErrInvalidCostChange  = errors.New("cost change is not valid")
+
+	ErrInvalidMarginGroup = errors.New("margins are grouped by product, category or promotion")
+	ErrInvalidDateRange   = errors.New("date range is not valid")
+
+	ErrInvalidVariant  = errors.New("parent product does not exist or is a variant")
+	ErrVariantRequired = errors.New("product is sold by its variants")
+	ErrInvalidKit      = errors.New("kit components are not valid")
+
+	ErrWeightRequired = errors.New("weighed product requires a weight")
+	ErrInvalidWeight  = errors.New("weight is only valid for weighed products")
+
+	ErrInvalidImportFormat = errors.New("import format must be csv or json")
+	ErrInvalidImportFile   = errors.New("import file is not valid")
+	ErrDuplicatedRow       = errors.New("product code is repeated in the file")
+	ErrItemNotFound        = errors.New("item does not exist")
+	ErrInvalidQuantity     = errors.New("quantity is not valid")
+	ErrNoScans             = errors.New("basket has no scans to undo")
+	ErrScanNotUndoable     = errors.New("scan of a weighed product can not be undone")
+
+	ErrExperimentNotFound = errors.New("experiment does not exist")
+
+	ErrCustomerCreated        = errors.New("customer was created previously")
+	ErrCustomerNotFound       = errors.New("customer does not exist")
+	ErrCustomerNotAttached    = errors.New("basket has no customer")
+	ErrInvalidTier            = errors.New("loyalty tier does not exist")
+	ErrNotEnoughPoints        = errors.New("customer has not enough points")
+	ErrRedemptionExceedsTotal = errors.New("points discount exceeds basket total")
+	ErrLoyaltyDisabled        = errors.New("loyalty accounts are not enabled")
+
+	ErrPriceListNotFound  = errors.New("price list does not exist")
+	ErrInvalidPriceList   = errors.New("price list is not valid")
+	ErrPriceListsDisabled = errors.New("price lists are not enabled")
+
+	ErrMarkdownNotFound  = errors.New("markdown does not exist")
+	ErrInvalidMarkdown   = errors.New("markdown is not valid")
+	ErrMarkdownsDisabled = errors.New("markdowns are not enabled")
+
+	ErrInvalidOverride   = errors.New("price override is not valid")
+	ErrInvalidReasonCode = errors.New("reason code is not valid")
+
+	ErrApprovalRequired         = errors.New("manager approval required")
+	ErrInvalidOperation         = errors.New("operation does not require approval")
+	ErrInvalidManagerCredential = errors.New("manager id or pin is not valid")
+	ErrManagerLockedOut         = errors.New("manager is locked out after too many wrong pins")
+	ErrInvalidManagers          = errors.New("managers are not valid")
+
+	ErrInvalidCurrency      = errors.New("currency is not valid")
+	ErrExchangeRateNotFound = errors.New("exchange rate does not exist")
+	ErrInvalidExchangeRates = errors.New("exchange rates are not valid")
+
+	ErrInvalidTender = errors.New("tender is not valid")
+
+	ErrPurchaseLimit = errors.New("purchase limit exceeded")
+
+	ErrInsufficientStock = errors.New("product has not enough stock")
+	ErrInventoryDisabled = errors.New("inventory is not enabled")
+	ErrInvalidAdjustment = errors.New("inventory adjustment is not valid")
+
+	ErrCycleCountNotFound = errors.New("cycle count does not exist")
+	ErrCycleCountPosted   = errors.New("cycle count was posted")
+	ErrInvalidCycleCount  = errors.New("cycle count is not valid")
+	ErrProductNotCounted  = errors.New("product is not in the cycle count")
 )
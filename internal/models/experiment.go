@@ -0,0 +1,21 @@
+package models
+
+// ExperimentResult represents the outcome of an A/B experiment per variant.
+type ExperimentResult struct {
+	Experiment string
+	Rule       string
+	Variants   []VariantResult
+}
+
+// VariantResult represents the conversion and revenue of the baskets assigned to a variant.
+type VariantResult struct {
+	Variant        string
+	Baskets        int
+	CheckedOut     int
+	ConversionRate float64
+	Revenue        Money
+	AverageBasket  Money
+	// Rounding is the cash rounding of the payments, Tendered the revenue taken with it.
+	Rounding Money
+	Tendered Money
+}
@@ -0,0 +1,27 @@
+package models
+
+// ImportOptions represents how a catalog import is done.
+// DryRun only validates the rows, Upsert updates the products that already exist
+// instead of reporting them as errors.
+type ImportOptions struct {
+	DryRun bool
+	Upsert bool
+}
+
+// ImportResult represents the outcome of a catalog import.
+// The rows are only applied when all of them are valid and it's not a dry run.
+type ImportResult struct {
+	DryRun  bool
+	Applied bool
+	Created int
+	Updated int
+	Errors  []ImportError
+}
+
+// ImportError represents a row of an import file that is not valid.
+// Row is the line of a CSV file, or the position of a JSON element starting at 1.
+type ImportError struct {
+	Row   int
+	Code  string
+	Error string
+}
@@ -0,0 +1,41 @@
+package models
+
+// Component is a product a kit is made of, with its units in one kit.
+type Component struct {
+	ProductCode string
+	Quantity    int
+}
+
+// Allocation is the part of a kit line sold attributed to one of its components,
+// in proportion to the regular prices of the components.
+type Allocation struct {
+	ProductCode string
+	Category    string
+	Quantity    int
+	Revenue     Money
+	Tax         Money
+	Cost        Money
+}
+
+// IsKit reports whether the product is sold as a bundle of other products.
+func (p Product) IsKit() bool {
+	return len(p.Components) > 0
+}
+
+// StockUnits returns the units taken out of stock by selling units of the product
+// by product code, the ones of its components for a kit. The products sold
+// by weight are not tracked.
+func (p Product) StockUnits(quantity int) map[string]int {
+	units := make(map[string]int)
+	switch {
+	case p.IsWeighed():
+	case p.IsKit():
+		for _, c := range p.Components {
+			units[c.ProductCode] += c.Quantity * quantity
+		}
+	default:
+		units[p.Code] = quantity
+	}
+
+	return units
+}
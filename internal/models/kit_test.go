@@ -0,0 +1,21 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestProduct_StockUnits(t *testing.T) {
+	outfit := models.Product{Code: "OUTFIT", Components: []models.Component{
+		{ProductCode: models.Tshirt, Quantity: 1},
+		{ProductCode: models.Pants, Quantity: 2},
+	}}
+
+	assert.True(t, outfit.IsKit())
+	assert.Equal(t, map[string]int{models.Tshirt: 3, models.Pants: 6}, outfit.StockUnits(3))
+	assert.Equal(t, map[string]int{models.Tshirt: 3}, models.ProductMap[models.Tshirt].StockUnits(3))
+	assert.Empty(t, models.Product{Code: "BANANAS", Unit: models.UnitKg}.StockUnits(3))
+}
@@ -0,0 +1,26 @@
+package models
+
+import "fmt"
+
+// PurchaseLimitError is returned when a product added to a basket exceeds
+// one of its purchase limits: the units of a product, or the total over which
+// a manager must approve the basket.
+type PurchaseLimitError struct {
+	ProductCode string
+	MaxUnits    int
+	MaxTotal    Money
+}
+
+func (e *PurchaseLimitError) Error() string {
+	if e.MaxTotal > 0 {
+		return fmt.Sprintf("%s: a total over %s requires the approval of a manager", ErrPurchaseLimit, e.MaxTotal)
+	}
+
+	return fmt.Sprintf("%s: %d units of %s per basket", ErrPurchaseLimit, e.MaxUnits, e.ProductCode)
+}
+
+// Is makes errors.Is(err, ErrPurchaseLimit) true for any limit,
+// the limit of the total included though a manager can lift it.
+func (e *PurchaseLimitError) Is(target error) bool {
+	return target == ErrPurchaseLimit
+}
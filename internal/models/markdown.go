@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Markdown represents a clearance policy, the products or categories it's
+// attached to are marked down in steps from its start.
+type Markdown struct {
+	Name       string
+	Products   []string
+	Categories []string
+	Start      time.Time
+	Steps      []MarkdownStep
+}
+
+// MarkdownStep is the percent the price is marked down after some days.
+type MarkdownStep struct {
+	AfterDays int
+	Percent   float64
+}
+
+// Percent returns the markdown in force at the given time,
+// the one of the latest step reached.
+func (m Markdown) Percent(at time.Time) float64 {
+	if at.Before(m.Start) {
+		return 0
+	}
+
+	days := int(at.Sub(m.Start).Hours() / 24)
+	var percent float64
+	var reached = -1
+	for _, step := range m.Steps {
+		if step.AfterDays <= days && step.AfterDays > reached {
+			reached, percent = step.AfterDays, step.Percent
+		}
+	}
+
+	return percent
+}
+
+// AppliesTo reports whether a product is marked down by the policy,
+// the variants of a product are marked down with it.
+func (m Markdown) AppliesTo(product Product) bool {
+	for _, code := range m.Products {
+		if code == product.Code || code == product.Parent {
+			return true
+		}
+	}
+
+	for _, category := range m.Categories {
+		if product.Category != "" && category == product.Category {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,40 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestMarkdown_Percent(t *testing.T) {
+	start := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	markdown := models.Markdown{Start: start, Steps: []models.MarkdownStep{{AfterDays: 90, Percent: 50}, {AfterDays: 60, Percent: 20}}}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want float64
+	}{
+		{name: "before the start", at: start.Add(-time.Hour), want: 0},
+		{name: "no step reached", at: start.AddDate(0, 0, 59), want: 0},
+		{name: "first step", at: start.AddDate(0, 0, 60), want: 20},
+		{name: "last step", at: start.AddDate(0, 0, 120), want: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, markdown.Percent(tt.at))
+		})
+	}
+}
+
+func TestMarkdown_AppliesTo(t *testing.T) {
+	markdown := models.Markdown{Products: []string{models.Tshirt}, Categories: []string{"apparel"}}
+
+	assert.True(t, markdown.AppliesTo(models.Product{Code: models.Tshirt}))
+	assert.True(t, markdown.AppliesTo(models.Product{Code: "TSHIRT-M", Parent: models.Tshirt}))
+	assert.True(t, markdown.AppliesTo(models.Product{Code: models.Pants, Category: "apparel"}))
+	assert.False(t, markdown.AppliesTo(models.Product{Code: models.Voucher}))
+}
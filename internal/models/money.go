@@ -0,0 +1,197 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Money is an exact amount in minor units (cents).
+// It's written as a decimal string like "20.00" in JSON,
+// and it's read from JSON or YAML either as a string or as a number.
+type Money int64
+
+// RoundingMode defines how an amount with fractions of a cent is rounded.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest cent, halves away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest cent, halves to the even cent (banker's rounding).
+	RoundHalfEven
+	// RoundDown truncates the fractions of a cent towards zero.
+	RoundDown
+	// RoundUp rounds the fractions of a cent away from zero.
+	RoundUp
+)
+
+var ErrInvalidMoney = errors.New("amount is not a valid decimal")
+
+// NewMoney returns the amount of the given units (euros) and cents.
+func NewMoney(units, cents int64) Money {
+	return Money(units*100 + cents)
+}
+
+// ParseMoney parses a decimal amount like "7.5" or "-20.00".
+// It fails when the amount has more than two decimals.
+func ParseMoney(s string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || strings.ContainsAny(s, "/eE") {
+		return 0, ErrInvalidMoney
+	}
+
+	cents := new(big.Rat).Mul(r, big.NewRat(100, 1))
+	if !cents.IsInt() || !cents.Num().IsInt64() {
+		return 0, ErrInvalidMoney
+	}
+
+	return Money(cents.Num().Int64()), nil
+}
+
+// String returns the amount as a decimal with two digits, like "7.50".
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// Float64 returns the amount in units, only meant for display and ratios.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// Mul returns the amount multiplied by a quantity.
+func (m Money) Mul(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// Div returns the amount divided by n, rounded with the given mode.
+func (m Money) Div(n int64, mode RoundingMode) Money {
+	return m.MulRat(big.NewRat(1, n), mode)
+}
+
+// Percent returns the given percent of the amount, rounded with the given mode.
+func (m Money) Percent(percent float64, mode RoundingMode) Money {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(percent, 'f', -1, 64))
+	return m.MulRat(r.Quo(r, big.NewRat(100, 1)), mode)
+}
+
+// PercentIncluded returns the part of the amount that is the given percent
+// on top of a base, like the tax of a price that includes it, rounded with the given mode.
+func (m Money) PercentIncluded(percent float64, mode RoundingMode) Money {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(percent, 'f', -1, 64))
+	base := new(big.Rat).Add(r, big.NewRat(100, 1))
+	return m.MulRat(r.Quo(r, base), mode)
+}
+
+// MulRat returns the amount multiplied by an exact rational, rounded with the given mode.
+func (m Money) MulRat(r *big.Rat, mode RoundingMode) Money {
+	v := new(big.Rat).Mul(big.NewRat(int64(m), 1), r)
+	return Money(round(v.Num(), v.Denom(), mode))
+}
+
+// round returns num/den as an integer rounded with the given mode, den is positive.
+func round(num, den *big.Int, mode RoundingMode) int64 {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo.Int64()
+	}
+
+	sign := int64(num.Sign())
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	half := twice.Cmp(den)
+
+	var away bool
+	switch mode {
+	case RoundDown:
+		away = false
+	case RoundUp:
+		away = true
+	case RoundHalfEven:
+		away = half > 0 || (half == 0 && quo.Bit(0) == 1)
+	default:
+		away = half >= 0
+	}
+
+	if away {
+		return quo.Int64() + sign
+	}
+
+	return quo.Int64()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	money, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+	return nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (m *Money) UnmarshalYAML(value *yaml.Node) error {
+	money, err := ParseMoney(value.Value)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+	return nil
+}
+
+// Allocate splits the amount in proportion to the weights, the cents lost
+// by the rounding go to the last share. The shares are equal without weights.
+func (m Money) Allocate(weights []Money) []Money {
+	shares := make([]Money, len(weights))
+	if len(weights) == 0 {
+		return shares
+	}
+
+	var total Money
+	for _, w := range weights {
+		total += w
+	}
+
+	var allocated Money
+	for i, w := range weights {
+		if total == 0 {
+			shares[i] = m / Money(len(weights))
+		} else {
+			shares[i] = Money(int64(m) * int64(w) / int64(total))
+		}
+
+		allocated += shares[i]
+	}
+
+	shares[len(shares)-1] += m - allocated
+
+	return shares
+}
@@ -0,0 +1,114 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    models.Money
+		wantErr bool
+	}{
+		{in: "20", want: 2000},
+		{in: "7.5", want: 750},
+		{in: "0.01", want: 1},
+		{in: "-1.25", want: -125},
+		{in: "1.005", wantErr: true},
+		{in: "1e2", wantErr: true},
+		{in: "1/2", wantErr: true},
+		{in: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := models.ParseMoney(tt.in)
+			if tt.wantErr {
+				assert.Equal(t, models.ErrInvalidMoney, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "7.50", models.NewMoney(7, 50).String())
+	assert.Equal(t, "0.05", models.Money(5).String())
+	assert.Equal(t, "-1.25", models.Money(-125).String())
+}
+
+func TestMoney_Rounding(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  models.Money
+		percent float64
+		mode    models.RoundingMode
+		want    models.Money
+	}{
+		{name: "half up", amount: 1250, percent: 10.2, mode: models.RoundHalfUp, want: 128},
+		{name: "half up on half", amount: 25, percent: 10, mode: models.RoundHalfUp, want: 3},
+		{name: "half even on half to even", amount: 25, percent: 10, mode: models.RoundHalfEven, want: 2},
+		{name: "half even on half to odd", amount: 35, percent: 10, mode: models.RoundHalfEven, want: 4},
+		{name: "down", amount: 29, percent: 10, mode: models.RoundDown, want: 2},
+		{name: "up", amount: 21, percent: 10, mode: models.RoundUp, want: 3},
+		{name: "negative half up", amount: -25, percent: 10, mode: models.RoundHalfUp, want: -3},
+		{name: "exact", amount: 5700, percent: 20, mode: models.RoundHalfUp, want: 1140},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.amount.Percent(tt.percent, tt.mode))
+		})
+	}
+
+	assert.Equal(t, models.Money(347), models.Money(2000).PercentIncluded(21, models.RoundHalfUp))
+	assert.Equal(t, models.Money(0), models.Money(500).PercentIncluded(0, models.RoundHalfUp))
+
+	assert.Equal(t, models.Money(333), models.Money(1000).Div(3, models.RoundHalfUp))
+	assert.Equal(t, models.Money(334), models.Money(1000).Div(3, models.RoundUp))
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Total models.Money `json:"total"`
+	}{Total: 7450})
+	require.NoError(t, err)
+	assert.Equal(t, `{"total":"74.50"}`, string(data))
+
+	var got struct {
+		Price  models.Money `json:"price"`
+		Amount models.Money `json:"amount"`
+	}
+	err = json.Unmarshal([]byte(`{"price":"19.99","amount":7.5}`), &got)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(1999), got.Price)
+	assert.Equal(t, models.Money(750), got.Amount)
+
+	err = json.Unmarshal([]byte(`{"price":"19.999"}`), &got)
+	assert.Error(t, err)
+}
+
+func TestMoney_YAML(t *testing.T) {
+	var got struct {
+		NewPrice models.Money `yaml:"newPrice"`
+	}
+	err := yaml.Unmarshal([]byte("newPrice: 19"), &got)
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(1900), got.NewPrice)
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	assert.Equal(t, []models.Money{3636, 1364}, models.Money(5000).Allocate([]models.Money{2000, 750}))
+	assert.Equal(t, []models.Money{333, 333, 334}, models.Money(1000).Allocate([]models.Money{0, 0, 0}))
+	assert.Empty(t, models.Money(1000).Allocate(nil))
+}
@@ -0,0 +1,55 @@
+package models
+
+const (
+	OverrideUnitPrice       = "unit_price"
+	OverrideDiscountAmount  = "discount_amount"
+	OverrideDiscountPercent = "discount_percent"
+)
+
+const (
+	ReasonDamaged         = "damaged"
+	ReasonPriceMatch      = "price_match"
+	ReasonCustomerService = "customer_service"
+	ReasonMissingTag      = "missing_tag"
+)
+
+// ReasonCodes are the reasons accepted for a manual price change.
+var ReasonCodes = map[string]bool{
+	ReasonDamaged:         true,
+	ReasonPriceMatch:      true,
+	ReasonCustomerService: true,
+	ReasonMissingTag:      true,
+}
+
+// Override represents a manual change of the price of a line made by a cashier,
+// either a new unit price or a discount by amount, both kept in Amount, or by Percent.
+type Override struct {
+	Type    string
+	Amount  Money
+	Percent float64
+	Reason  string
+}
+
+// Validate checks the override has a known type, a valid value and a reason code.
+func (o Override) Validate() error {
+	if !ReasonCodes[o.Reason] {
+		return ErrInvalidReasonCode
+	}
+
+	switch o.Type {
+	case OverrideUnitPrice, OverrideDiscountAmount:
+		if o.Amount < 0 {
+			return ErrInvalidOverride
+		}
+
+		return nil
+	case OverrideDiscountPercent:
+		if o.Percent < 0 || o.Percent > 100 {
+			return ErrInvalidOverride
+		}
+
+		return nil
+	default:
+		return ErrInvalidOverride
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// PriceChange is a new price of a product from a given time on,
+// the changes of a product are its price history.
+type PriceChange struct {
+	ProductCode string
+	Price       Money
+	// Currency of the price, the one of the product when it's empty.
+	Currency      string
+	EffectiveFrom time.Time
+	CreatedAt     time.Time
+}
+
+// EffectivePrice returns the latest of the changes in force at the given time,
+// the changes must be sorted by EffectiveFrom.
+func EffectivePrice(changes []PriceChange, at time.Time) (PriceChange, bool) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		if !changes[i].EffectiveFrom.After(at) {
+			return changes[i], true
+		}
+	}
+
+	return PriceChange{}, false
+}
@@ -0,0 +1,50 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestEffectivePrice(t *testing.T) {
+	june := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	september := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	changes := []models.PriceChange{
+		{ProductCode: models.Tshirt, Price: 2200, EffectiveFrom: june},
+		{ProductCode: models.Tshirt, Price: 1800, EffectiveFrom: september},
+	}
+
+	_, ok := models.EffectivePrice(changes, june.Add(-time.Second))
+	assert.False(t, ok)
+
+	change, ok := models.EffectivePrice(changes, june)
+	assert.True(t, ok)
+	assert.Equal(t, models.Money(2200), change.Price)
+
+	change, ok = models.EffectivePrice(changes, september.Add(time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, models.Money(1800), change.Price)
+}
+
+func TestEffectiveCost(t *testing.T) {
+	june := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	september := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	changes := []models.CostChange{
+		{ProductCode: models.Voucher, Cost: 250, EffectiveFrom: june},
+		{ProductCode: models.Voucher, Cost: 300, EffectiveFrom: september},
+	}
+
+	_, ok := models.EffectiveCost(changes, june.Add(-time.Second))
+	assert.False(t, ok)
+
+	change, ok := models.EffectiveCost(changes, september.Add(-time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, models.Money(250), change.Cost)
+
+	change, ok = models.EffectiveCost(changes, september)
+	assert.True(t, ok)
+	assert.Equal(t, models.Money(300), change.Cost)
+}
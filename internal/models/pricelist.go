@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// PriceList represents the negotiated prices of wholesale customers.
+// A zero ValidFrom or ValidTo leaves the list open on that side.
+type PriceList struct {
+	Name string
+	// Currency of the prices, the base currency of the store when it's empty.
+	Currency          string
+	Prices            map[string]Money
+	ValidFrom         time.Time
+	ValidTo           time.Time
+	DisablePromotions bool
+}
+
+// IsValid reports whether the price list is in force at the given time.
+func (p PriceList) IsValid(at time.Time) bool {
+	if !p.ValidFrom.IsZero() && at.Before(p.ValidFrom) {
+		return false
+	}
+
+	if !p.ValidTo.IsZero() && !at.Before(p.ValidTo) {
+		return false
+	}
+
+	return true
+}
+
+// Price returns the price of a product at the given time
+// and whether the price list has one.
+func (p PriceList) Price(productCode string, at time.Time) (Money, bool) {
+	if !p.IsValid(at) {
+		return 0, false
+	}
+
+	price, ok := p.Prices[productCode]
+	return price, ok
+}
@@ -0,0 +1,42 @@
+package models
+
+// StaffPurchases represents the checked out baskets of an employee.
+type StaffPurchases struct {
+	EmployeeID string
+	Baskets    int
+	Gross      Money
+	Discount   Money
+	Total      Money
+	// Rounding is the cash rounding of the payments, Tendered the total taken with it.
+	Rounding Money
+	Tendered Money
+}
+
+// ProductDiscounts represents the units of a product sold in the checked out baskets,
+// the markdowns of clearance and the discounts of the promotions are kept apart.
+type ProductDiscounts struct {
+	ProductCode       string
+	Units             int
+	Markdown          Money
+	PromotionDiscount Money
+	Total             Money
+}
+
+// Margin represents the gross margin of the lines of the checked out baskets
+// grouped by product, category or promotion. Revenue is net of tax.
+type Margin struct {
+	Key     string
+	Units   int
+	Revenue Money
+	Cost    Money
+	Margin  Money
+}
+
+// Percent returns the margin over the revenue.
+func (m Margin) Percent() float64 {
+	if m.Revenue == 0 {
+		return 0
+	}
+
+	return float64(m.Margin) * 100 / float64(m.Revenue)
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Scan is a change of the units of a line of a basket, the scans of
+// a basket are kept in the order they were made.
+type Scan struct {
+	ProductCode string
+	// Quantity is the number of units added, negative when they were removed.
+	Quantity int
+	// Cashier is the one of the basket when the scan was made.
+	Cashier   string
+	ScannedAt time.Time
+	// UndoneAt is set when the scan was undone, its units are not in the basket anymore.
+	UndoneAt time.Time
+}
+
+// IsUndone reports whether the scan was undone.
+func (s Scan) IsUndone() bool {
+	return !s.UndoneAt.IsZero()
+}
+
+// LastScan returns the index of the last scan of the basket that was not undone,
+// -1 when there is none.
+func (b Basket) LastScan() int {
+	for i := len(b.Scans) - 1; i >= 0; i-- {
+		if !b.Scans[i].IsUndone() {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// OrderedItems returns the items of the basket in the order their first unit was scanned,
+// a line removed and scanned again goes after the others. The items without scans
+// go last, by product code.
+func (b Basket) OrderedItems() []Item {
+	quantities := make(map[string]int)
+	var codes []string
+	for _, scan := range b.Scans {
+		if scan.IsUndone() {
+			continue
+		}
+
+		before := quantities[scan.ProductCode]
+		quantities[scan.ProductCode] += scan.Quantity
+		if before <= 0 && quantities[scan.ProductCode] > 0 {
+			codes = append(remove(codes, scan.ProductCode), scan.ProductCode)
+		}
+	}
+
+	items := make([]Item, 0, len(b.Items))
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if item, ok := b.Items[code]; ok {
+			items = append(items, item)
+			seen[code] = true
+		}
+	}
+
+	var rest []string
+	for code := range b.Items {
+		if !seen[code] {
+			rest = append(rest, code)
+		}
+	}
+
+	sort.Strings(rest)
+	for _, code := range rest {
+		items = append(items, b.Items[code])
+	}
+
+	return items
+}
+
+func remove(codes []string, code string) []string {
+	for i, c := range codes {
+		if c == code {
+			return append(codes[:i], codes[i+1:]...)
+		}
+	}
+
+	return codes
+}
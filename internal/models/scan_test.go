@@ -0,0 +1,46 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestBasket_OrderedItems(t *testing.T) {
+	now := time.Now()
+	basket := models.Basket{
+		Items: map[string]models.Item{
+			"A": {Product: models.Product{Code: "A"}, Quantity: 1},
+			"B": {Product: models.Product{Code: "B"}, Quantity: 1},
+			"C": {Product: models.Product{Code: "C"}, Quantity: 2},
+			"D": {Product: models.Product{Code: "D"}, Quantity: 1},
+			"E": {Product: models.Product{Code: "E"}, Quantity: 1},
+		},
+		Scans: []models.Scan{
+			{ProductCode: "C", Quantity: 1},
+			{ProductCode: "A", Quantity: 1},
+			{ProductCode: "B", Quantity: 1},
+			{ProductCode: "C", Quantity: 1},
+			// A is removed and scanned again, after B
+			{ProductCode: "A", Quantity: -1},
+			{ProductCode: "A", Quantity: 1},
+			// the undone scans don't count
+			{ProductCode: "D", Quantity: 1, UndoneAt: now},
+			{ProductCode: "D", Quantity: 1},
+			{ProductCode: "B", Quantity: 1, UndoneAt: now},
+		},
+	}
+
+	var codes []string
+	for _, item := range basket.OrderedItems() {
+		codes = append(codes, item.Product.Code)
+	}
+
+	// E has no scans, it goes last
+	assert.Equal(t, []string{"C", "B", "A", "D", "E"}, codes)
+	assert.Equal(t, 7, basket.LastScan())
+	assert.Equal(t, -1, models.Basket{}.LastScan())
+}
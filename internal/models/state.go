@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// StateOpen is a basket products can be added to.
+	StateOpen = "open"
+	// StateCheckedOut is a basket priced and with its stock taken, waiting for the payment.
+	StateCheckedOut = "checked_out"
+	// StatePaid is a checked out basket whose payment was taken.
+	StatePaid = "paid"
+	// StateVoided is a basket cancelled before it was paid.
+	StateVoided = "voided"
+	// StateRefunded is a paid basket whose payment was given back.
+	StateRefunded = "refunded"
+	// StateExpired is an open basket abandoned at the till.
+	StateExpired = "expired"
+)
+
+// transitions are the states a basket can go to from each state,
+// voided, refunded and expired are final.
+var transitions = map[string][]string{
+	StateOpen:       {StateCheckedOut, StateVoided, StateExpired},
+	StateCheckedOut: {StatePaid, StateVoided},
+	StatePaid:       {StateRefunded},
+}
+
+// Transition represents a change of the state of a basket.
+type Transition struct {
+	From string
+	To   string
+	At   time.Time
+}
+
+// TransitionError is returned when a basket can't go from its state to another one.
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: from %s to %s", ErrInvalidTransition, e.From, e.To)
+}
+
+// Is makes errors.Is(err, ErrInvalidTransition) true for any transition,
+// and errors.Is(err, ErrBasketIsClosed) true when the basket is not open.
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition || (target == ErrBasketIsClosed && e.From != StateOpen)
+}
+
+// CurrentState returns the state of the basket, a basket without state is open.
+func (b Basket) CurrentState() string {
+	if b.State == "" {
+		return StateOpen
+	}
+
+	return b.State
+}
+
+// IsOpen reports whether the lines of the basket can still be changed.
+func (b Basket) IsOpen() bool {
+	return b.CurrentState() == StateOpen
+}
+
+// IsSold reports whether the basket was checked out, and was neither voided nor refunded.
+func (b Basket) IsSold() bool {
+	return b.State == StateCheckedOut || b.State == StatePaid
+}
+
+// MoveTo changes the state of the basket and records the transition at the given time,
+// it returns a TransitionError when the transition is not allowed.
+func (b *Basket) MoveTo(state string, at time.Time) error {
+	from := b.CurrentState()
+	for _, to := range transitions[from] {
+		if to == state {
+			b.State = state
+			b.Transitions = append(append([]Transition{}, b.Transitions...), Transition{From: from, To: state, At: at})
+			return nil
+		}
+	}
+
+	return &TransitionError{From: from, To: state}
+}
+
+// TransitionedAt returns the time the basket went to a state, zero when it didn't.
+func (b Basket) TransitionedAt(state string) time.Time {
+	for _, t := range b.Transitions {
+		if t.To == state {
+			return t.At
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,72 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestBasket_MoveTo(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		allowed bool
+	}{
+		{from: "", to: models.StateCheckedOut, allowed: true},
+		{from: models.StateOpen, to: models.StateVoided, allowed: true},
+		{from: models.StateOpen, to: models.StateExpired, allowed: true},
+		{from: models.StateOpen, to: models.StatePaid},
+		{from: models.StateOpen, to: models.StateRefunded},
+		{from: models.StateCheckedOut, to: models.StatePaid, allowed: true},
+		{from: models.StateCheckedOut, to: models.StateVoided, allowed: true},
+		{from: models.StateCheckedOut, to: models.StateCheckedOut},
+		{from: models.StateCheckedOut, to: models.StateRefunded},
+		{from: models.StatePaid, to: models.StateRefunded, allowed: true},
+		{from: models.StatePaid, to: models.StateVoided},
+		{from: models.StateVoided, to: models.StateOpen},
+		{from: models.StateRefunded, to: models.StatePaid},
+		{from: models.StateExpired, to: models.StateCheckedOut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+"_"+tt.to, func(t *testing.T) {
+			at := time.Now()
+			basket := models.Basket{State: tt.from}
+			from := basket.CurrentState()
+
+			err := basket.MoveTo(tt.to, at)
+			if !tt.allowed {
+				assert.Equal(t, &models.TransitionError{From: from, To: tt.to}, err)
+				assert.True(t, errors.Is(err, models.ErrInvalidTransition))
+				assert.Equal(t, from != models.StateOpen, errors.Is(err, models.ErrBasketIsClosed))
+				assert.Empty(t, basket.Transitions)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.to, basket.State)
+			assert.Equal(t, []models.Transition{{From: from, To: tt.to, At: at}}, basket.Transitions)
+			assert.Equal(t, at, basket.TransitionedAt(tt.to))
+		})
+	}
+}
+
+func TestBasket_IsSold(t *testing.T) {
+	for state, sold := range map[string]bool{
+		"":                     false,
+		models.StateOpen:       false,
+		models.StateCheckedOut: true,
+		models.StatePaid:       true,
+		models.StateVoided:     false,
+		models.StateRefunded:   false,
+		models.StateExpired:    false,
+	} {
+		basket := models.Basket{State: state}
+		assert.Equal(t, sold, basket.IsSold(), state)
+		assert.Equal(t, state == "" || state == models.StateOpen, basket.IsOpen(), state)
+	}
+}
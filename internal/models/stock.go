@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+const (
+	// MovementReserve holds units for an open basket.
+	MovementReserve = "reserve"
+	// MovementRelease gives back the units held for a basket.
+	MovementRelease = "release"
+	// MovementSale takes out the units sold at checkout.
+	MovementSale = "sale"
+	// MovementReturn puts back the units of a sale voided or refunded.
+	MovementReturn = "return"
+
+	// MovementReceipt adds the units of a delivery.
+	MovementReceipt = "receipt"
+	// MovementDamage takes out the units that can't be sold.
+	MovementDamage = "damage"
+	// MovementTheft takes out the units stolen.
+	MovementTheft = "theft"
+	// MovementCountCorrection sets the units on hand to the ones counted.
+	MovementCountCorrection = "count_correction"
+)
+
+// AdjustmentReasons are the reason codes of the inventory adjustments.
+var AdjustmentReasons = map[string]bool{
+	MovementReceipt:         true,
+	MovementDamage:          true,
+	MovementTheft:           true,
+	MovementCountCorrection: true,
+}
+
+const (
+	CycleCountOpen   = "open"
+	CycleCountPosted = "posted"
+)
+
+// Stock is the ledger of a product: the units in the store
+// and the units held by the open baskets.
+type Stock struct {
+	ProductCode string
+	OnHand      int
+	Reserved    int
+}
+
+// Available returns the units that can still be added to a basket.
+func (s Stock) Available() int {
+	return s.OnHand - s.Reserved
+}
+
+// StockMovement is a change of the stock of a product, the deltas are
+// signed and OnHand and Reserved are the balances after the movement.
+type StockMovement struct {
+	ProductCode   string
+	Type          string
+	OnHandDelta   int
+	ReservedDelta int
+	OnHand        int
+	Reserved      int
+	BasketID      string
+	// CycleCountID is the count a correction was posted from.
+	CycleCountID string
+	Note         string
+	CreatedAt    time.Time
+}
+
+// CycleCount is a physical count of some products. The units on hand are
+// taken as expected when a product is counted, and the variances are
+// posted as count corrections.
+type CycleCount struct {
+	ID        string
+	Status    string
+	Lines     []CountLine
+	CreatedAt time.Time
+	PostedAt  time.Time
+}
+
+// CountLine is a product of a cycle count, Counted is nil until it's counted.
+type CountLine struct {
+	ProductCode string
+	Expected    int
+	Counted     *int
+}
+
+// Variance returns the units counted over the expected ones.
+func (l CountLine) Variance() int {
+	if l.Counted == nil {
+		return 0
+	}
+
+	return *l.Counted - l.Expected
+}
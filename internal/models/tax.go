@@ -0,0 +1,73 @@
+package models
+
+import "sort"
+
+const (
+	TaxStandard = "standard"
+	TaxReduced  = "reduced"
+	TaxExempt   = "exempt"
+	// TaxOutOfScope is for products outside of VAT, like gift cards,
+	// which are taxed when they are spent.
+	TaxOutOfScope = "out_of_scope"
+)
+
+// TaxCategories are the tax categories of the products.
+var TaxCategories = map[string]bool{
+	TaxStandard:   true,
+	TaxReduced:    true,
+	TaxExempt:     true,
+	TaxOutOfScope: true,
+}
+
+// TaxSummary represents the tax of a basket for one category and rate.
+type TaxSummary struct {
+	Category string
+	Rate     float64
+	Net      Money
+	Tax      Money
+	Gross    Money
+}
+
+// summarizeTaxes groups the tax of the lines by category and rate.
+func (b Basket) summarizeTaxes() (Money, []TaxSummary) {
+	type key struct {
+		category string
+		rate     float64
+	}
+
+	var total Money
+	byRate := make(map[key]*TaxSummary)
+	for _, i := range b.Items {
+		k := key{category: i.TaxCategory(), rate: i.TaxRate}
+		summary, ok := byRate[k]
+		if !ok {
+			summary = &TaxSummary{Category: k.category, Rate: k.rate}
+			byRate[k] = summary
+		}
+
+		net, gross := i.Taxable(), i.Taxable()+i.Tax
+		if b.PricesIncludeTax {
+			net, gross = i.Taxable()-i.Tax, i.Taxable()
+		}
+
+		summary.Net += net
+		summary.Tax += i.Tax
+		summary.Gross += gross
+		total += i.Tax
+	}
+
+	taxes := make([]TaxSummary, 0, len(byRate))
+	for _, summary := range byRate {
+		taxes = append(taxes, *summary)
+	}
+
+	sort.Slice(taxes, func(i, j int) bool {
+		if taxes[i].Category != taxes[j].Category {
+			return taxes[i].Category < taxes[j].Category
+		}
+
+		return taxes[i].Rate < taxes[j].Rate
+	})
+
+	return total, taxes
+}
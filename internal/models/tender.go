@@ -0,0 +1,17 @@
+package models
+
+const (
+	TenderCash = "cash"
+	TenderCard = "card"
+)
+
+// Tenders are the ways a basket can be paid.
+var Tenders = map[string]bool{
+	TenderCash: true,
+	TenderCard: true,
+}
+
+// Tendered returns the amount taken for the basket in its currency, the total with the cash rounding.
+func (b Basket) Tendered() Money {
+	return b.Total + b.BaseRoundingAdjustment
+}
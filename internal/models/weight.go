@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// UnitEach is the unit of the products priced per unit, the default one.
+	UnitEach = "each"
+	// UnitKg is the unit of the weighed products, priced per kilogram.
+	UnitKg = "kg"
+)
+
+// Weight is an exact weight in grams.
+// It's written as a decimal of kilograms like "1.250" in JSON.
+type Weight int64
+
+var ErrInvalidWeightFormat = errors.New("weight is not a valid decimal of kilograms")
+
+// ParseWeight parses a decimal of kilograms like "1.25".
+// It fails when the weight has more than three decimals or it's not positive.
+func ParseWeight(s string) (Weight, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || strings.ContainsAny(s, "/eE") {
+		return 0, ErrInvalidWeightFormat
+	}
+
+	grams := new(big.Rat).Mul(r, big.NewRat(1000, 1))
+	if !grams.IsInt() || !grams.Num().IsInt64() || grams.Sign() <= 0 {
+		return 0, ErrInvalidWeightFormat
+	}
+
+	return Weight(grams.Num().Int64()), nil
+}
+
+// String returns the weight in kilograms with three digits, like "1.250".
+func (w Weight) String() string {
+	return fmt.Sprintf("%d.%03d", int64(w)/1000, int64(w)%1000)
+}
+
+// Price returns the amount of the weight at the given price per kilogram,
+// rounded half up to the cent.
+func (w Weight) Price(perKg Money) Money {
+	return perKg.MulRat(big.NewRat(int64(w), 1000), RoundHalfUp)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (w Weight) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (w *Weight) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	weight, err := ParseWeight(s)
+	if err != nil {
+		return err
+	}
+
+	*w = weight
+	return nil
+}
@@ -0,0 +1,54 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+)
+
+func TestParseWeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		weight  string
+		want    models.Weight
+		wantErr error
+	}{
+		{name: "grams", weight: "1.250", want: 1250},
+		{name: "less decimals", weight: "0.5", want: 500},
+		{name: "integer", weight: "2", want: 2000},
+		{name: "too many decimals", weight: "1.2505", wantErr: models.ErrInvalidWeightFormat},
+		{name: "zero", weight: "0", wantErr: models.ErrInvalidWeightFormat},
+		{name: "negative", weight: "-1", wantErr: models.ErrInvalidWeightFormat},
+		{name: "fraction", weight: "1/2", wantErr: models.ErrInvalidWeightFormat},
+		{name: "not a number", weight: "kg", wantErr: models.ErrInvalidWeightFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := models.ParseWeight(tt.weight)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWeight_Price(t *testing.T) {
+	// 1.250 kg at 1.99 per kg is 2.4875
+	assert.Equal(t, models.Money(249), models.Weight(1250).Price(199))
+	assert.Equal(t, models.Money(100), models.Weight(500).Price(199))
+	assert.Equal(t, models.Money(0), models.Weight(1).Price(199))
+}
+
+func TestWeight_JSON(t *testing.T) {
+	data, err := json.Marshal(models.Weight(1250))
+	require.NoError(t, err)
+	assert.Equal(t, `"1.250"`, string(data))
+
+	var w models.Weight
+	require.NoError(t, json.Unmarshal([]byte(`"0.075"`), &w))
+	assert.Equal(t, models.Weight(75), w)
+	assert.Equal(t, "0.075", w.String())
+}
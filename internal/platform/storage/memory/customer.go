@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
+)
+
+// CustomerMemory is a memory CustomerRepository implementation.
+type CustomerMemory struct {
+	mux       sync.Mutex
+	customers map[string]models.Customer
+}
+
+// NewCustomerRepository initializes a memory implementation of storage.CustomerRepository.
+func NewCustomerRepository() storage.CustomerRepository {
+	return &CustomerMemory{customers: make(map[string]models.Customer)}
+}
+
+// CreateCustomer implements the storage.CustomerRepository interface.
+func (m *CustomerMemory) CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	if _, exist := m.customers[customer.ID]; exist {
+		return models.Customer{}, models.ErrCustomerCreated
+	}
+
+	m.customers[customer.ID] = customer
+	return customer, nil
+}
+
+// FindCustomerByID implements the storage.CustomerRepository interface.
+func (m *CustomerMemory) FindCustomerByID(ctx context.Context, id string) (models.Customer, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	customer, ok := m.customers[id]
+	if !ok {
+		return models.Customer{}, models.ErrCustomerNotFound
+	}
+
+	return customer, nil
+}
+
+// UpdateCustomer implements the storage.CustomerRepository interface.
+func (m *CustomerMemory) UpdateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	if _, ok := m.customers[customer.ID]; !ok {
+		return models.Customer{}, models.ErrCustomerNotFound
+	}
+
+	m.customers[customer.ID] = customer
+	return customer, nil
+}
+
+// AddPoints implements the storage.CustomerRepository interface.
+func (m *CustomerMemory) AddPoints(ctx context.Context, id string, delta int) (models.Customer, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	customer, ok := m.customers[id]
+	if !ok {
+		return models.Customer{}, models.ErrCustomerNotFound
+	}
+
+	if customer.Points+delta < 0 {
+		return models.Customer{}, models.ErrNotEnoughPoints
+	}
+
+	customer.Points += delta
+	m.customers[id] = customer
+	return customer, nil
+}
@@ -0,0 +1,61 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func TestCustomerMemory(t *testing.T) {
+	repository := memory.NewCustomerRepository()
+	ctx := context.Background()
+	customer := models.Customer{ID: "c1", Name: "Pepito", Tier: models.TierStandard}
+
+	_, err := repository.FindCustomerByID(ctx, customer.ID)
+	assert.Equal(t, models.ErrCustomerNotFound, err)
+
+	_, err = repository.UpdateCustomer(ctx, customer)
+	assert.Equal(t, models.ErrCustomerNotFound, err)
+
+	_, err = repository.CreateCustomer(ctx, customer)
+	require.NoError(t, err)
+
+	_, err = repository.CreateCustomer(ctx, customer)
+	assert.Equal(t, models.ErrCustomerCreated, err)
+
+	customer.Points = 120
+	_, err = repository.UpdateCustomer(ctx, customer)
+	require.NoError(t, err)
+
+	got, err := repository.FindCustomerByID(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, customer, got)
+}
+
+func TestCustomerMemory_AddPoints(t *testing.T) {
+	repository := memory.NewCustomerRepository()
+	ctx := context.Background()
+
+	_, err := repository.AddPoints(ctx, "c1", 10)
+	assert.Equal(t, models.ErrCustomerNotFound, err)
+
+	_, err = repository.CreateCustomer(ctx, models.Customer{ID: "c1", Name: "Pepito", Points: 100})
+	require.NoError(t, err)
+
+	customer, err := repository.AddPoints(ctx, "c1", -60)
+	require.NoError(t, err)
+	assert.Equal(t, 40, customer.Points)
+
+	// the points are never negative
+	_, err = repository.AddPoints(ctx, "c1", -41)
+	assert.Equal(t, models.ErrNotEnoughPoints, err)
+
+	customer, err = repository.FindCustomerByID(ctx, "c1")
+	require.NoError(t, err)
+	assert.Equal(t, 40, customer.Points)
+}
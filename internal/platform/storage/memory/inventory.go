@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
+)
+
+// InventoryMemory is a memory InventoryRepository implementation.
+type InventoryMemory struct {
+	mux       sync.Mutex
+	stock     map[string]models.Stock
+	movements map[string][]models.StockMovement
+	counts    map[string]models.CycleCount
+}
+
+// NewInventoryRepository initializes a memory implementation of storage.InventoryRepository
+// with the given stock.
+func NewInventoryRepository(stock ...models.Stock) storage.InventoryRepository {
+	m := &InventoryMemory{
+		stock:     make(map[string]models.Stock),
+		movements: make(map[string][]models.StockMovement),
+		counts:    make(map[string]models.CycleCount),
+	}
+	for _, s := range stock {
+		m.stock[s.ProductCode] = s
+	}
+
+	return m
+}
+
+// FindStock implements the storage.InventoryRepository interface,
+// the products without movements have no stock.
+func (m *InventoryMemory) FindStock(ctx context.Context, productCode string) (models.Stock, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	stock, ok := m.stock[productCode]
+	if !ok {
+		return models.Stock{ProductCode: productCode}, nil
+	}
+
+	return stock, nil
+}
+
+// Move implements the storage.InventoryRepository interface.
+func (m *InventoryMemory) Move(ctx context.Context, movement models.StockMovement) (models.StockMovement, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	return m.move(movement), nil
+}
+
+// Reserve implements the storage.InventoryRepository interface. A movement
+// that doesn't lower the available units is applied even if they're negative.
+func (m *InventoryMemory) Reserve(ctx context.Context, movement models.StockMovement, allowNegative bool) (models.StockMovement, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	stock := m.stock[movement.ProductCode]
+	available := stock.Available() + movement.OnHandDelta - movement.ReservedDelta
+	if !allowNegative && available < 0 && available < stock.Available() {
+		return models.StockMovement{}, models.ErrInsufficientStock
+	}
+
+	return m.move(movement), nil
+}
+
+// SetOnHand implements the storage.InventoryRepository interface.
+func (m *InventoryMemory) SetOnHand(ctx context.Context, movement models.StockMovement, onHand int) (models.StockMovement, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	movement.OnHandDelta = onHand - m.stock[movement.ProductCode].OnHand
+
+	return m.move(movement), nil
+}
+
+// move applies and records a movement, the lock must be held.
+func (m *InventoryMemory) move(movement models.StockMovement) models.StockMovement {
+	stock, ok := m.stock[movement.ProductCode]
+	if !ok {
+		stock = models.Stock{ProductCode: movement.ProductCode}
+	}
+
+	stock.OnHand += movement.OnHandDelta
+	stock.Reserved += movement.ReservedDelta
+	m.stock[movement.ProductCode] = stock
+
+	movement.OnHand, movement.Reserved = stock.OnHand, stock.Reserved
+	m.movements[movement.ProductCode] = append(m.movements[movement.ProductCode], movement)
+
+	return movement
+}
+
+// ListMovements implements the storage.InventoryRepository interface,
+// the movements are in the order they were recorded.
+func (m *InventoryMemory) ListMovements(ctx context.Context, productCode string) ([]models.StockMovement, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	movements := make([]models.StockMovement, len(m.movements[productCode]))
+	copy(movements, m.movements[productCode])
+
+	return movements, nil
+}
+
+// SaveCycleCount implements the storage.InventoryRepository interface.
+func (m *InventoryMemory) SaveCycleCount(ctx context.Context, count models.CycleCount) (models.CycleCount, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	if m.counts[count.ID].Status == models.CycleCountPosted {
+		return models.CycleCount{}, models.ErrCycleCountPosted
+	}
+
+	m.counts[count.ID] = count
+
+	return count, nil
+}
+
+// FindCycleCount implements the storage.InventoryRepository interface.
+func (m *InventoryMemory) FindCycleCount(ctx context.Context, id string) (models.CycleCount, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	count, ok := m.counts[id]
+	if !ok {
+		return models.CycleCount{}, models.ErrCycleCountNotFound
+	}
+
+	return count, nil
+}
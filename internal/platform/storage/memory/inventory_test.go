@@ -0,0 +1,119 @@
+package memory_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func TestInventoryMemory(t *testing.T) {
+	repository := memory.NewInventoryRepository(models.Stock{ProductCode: models.Tshirt, OnHand: 5})
+	ctx := context.Background()
+
+	stock, err := repository.FindStock(ctx, models.Pants)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Pants}, stock)
+
+	reserve, err := repository.Move(ctx, models.StockMovement{
+		ProductCode: models.Tshirt, Type: models.MovementReserve, ReservedDelta: 2, BasketID: "1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, reserve.OnHand)
+	assert.Equal(t, 2, reserve.Reserved)
+
+	sale, err := repository.Move(ctx, models.StockMovement{
+		ProductCode: models.Tshirt, Type: models.MovementSale, OnHandDelta: -2, ReservedDelta: -2, BasketID: "1",
+	})
+	require.NoError(t, err)
+
+	stock, err = repository.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 3}, stock)
+
+	movements, err := repository.ListMovements(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, []models.StockMovement{reserve, sale}, movements)
+}
+
+func TestInventoryMemory_Reserve(t *testing.T) {
+	repository := memory.NewInventoryRepository(models.Stock{ProductCode: models.Tshirt, OnHand: 5})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var reserved int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repository.Reserve(ctx, models.StockMovement{
+				ProductCode: models.Tshirt, Type: models.MovementReserve, ReservedDelta: 1,
+			}, false)
+			if err == nil {
+				atomic.AddInt32(&reserved, 1)
+				return
+			}
+
+			assert.Equal(t, models.ErrInsufficientStock, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(5), reserved)
+	stock, err := repository.FindStock(ctx, models.Tshirt)
+	require.NoError(t, err)
+	assert.Equal(t, models.Stock{ProductCode: models.Tshirt, OnHand: 5, Reserved: 5}, stock)
+
+	// the sale of the held units doesn't need more
+	_, err = repository.Reserve(ctx, models.StockMovement{
+		ProductCode: models.Tshirt, Type: models.MovementSale, OnHandDelta: -5, ReservedDelta: -5,
+	}, false)
+	require.NoError(t, err)
+
+	movement, err := repository.Reserve(ctx, models.StockMovement{
+		ProductCode: models.Tshirt, Type: models.MovementSale, OnHandDelta: -1,
+	}, true)
+	require.NoError(t, err)
+	assert.Equal(t, -1, movement.OnHand)
+}
+
+func TestInventoryMemory_CycleCount(t *testing.T) {
+	repository := memory.NewInventoryRepository()
+	ctx := context.Background()
+
+	_, err := repository.FindCycleCount(ctx, "1")
+	assert.Equal(t, models.ErrCycleCountNotFound, err)
+
+	count := models.CycleCount{ID: "1", Status: models.CycleCountOpen, Lines: []models.CountLine{{ProductCode: models.Tshirt}}}
+	_, err = repository.SaveCycleCount(ctx, count)
+	require.NoError(t, err)
+
+	got, err := repository.FindCycleCount(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, count, got)
+	// a posted count can't be saved again
+	count.Status = models.CycleCountPosted
+	_, err = repository.SaveCycleCount(ctx, count)
+	require.NoError(t, err)
+	_, err = repository.SaveCycleCount(ctx, count)
+	assert.Equal(t, models.ErrCycleCountPosted, err)
+}
+
+func TestInventoryMemory_SetOnHand(t *testing.T) {
+	repository := memory.NewInventoryRepository(models.Stock{ProductCode: models.Tshirt, OnHand: 5, Reserved: 1})
+	ctx := context.Background()
+
+	movement, err := repository.SetOnHand(ctx, models.StockMovement{
+		ProductCode: models.Tshirt, Type: models.MovementCountCorrection,
+	}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, -2, movement.OnHandDelta)
+	assert.Equal(t, 3, movement.OnHand)
+	assert.Equal(t, 1, movement.Reserved)
+}
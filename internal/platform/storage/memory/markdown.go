@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
+)
+
+// MarkdownMemory is a memory MarkdownRepository implementation.
+type MarkdownMemory struct {
+	mux       sync.Mutex
+	markdowns map[string]models.Markdown
+}
+
+// NewMarkdownRepository initializes a memory implementation of storage.MarkdownRepository.
+func NewMarkdownRepository() storage.MarkdownRepository {
+	return &MarkdownMemory{markdowns: make(map[string]models.Markdown)}
+}
+
+// SaveMarkdown implements the storage.MarkdownRepository interface.
+func (m *MarkdownMemory) SaveMarkdown(ctx context.Context, markdown models.Markdown) (models.Markdown, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	m.markdowns[markdown.Name] = markdown
+
+	return markdown, nil
+}
+
+// FindMarkdown implements the storage.MarkdownRepository interface.
+func (m *MarkdownMemory) FindMarkdown(ctx context.Context, name string) (models.Markdown, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	markdown, ok := m.markdowns[name]
+	if !ok {
+		return models.Markdown{}, models.ErrMarkdownNotFound
+	}
+
+	return markdown, nil
+}
+
+// ListMarkdowns implements the storage.MarkdownRepository interface.
+func (m *MarkdownMemory) ListMarkdowns(ctx context.Context) ([]models.Markdown, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	markdowns := make([]models.Markdown, 0, len(m.markdowns))
+	for _, markdown := range m.markdowns {
+		markdowns = append(markdowns, markdown)
+	}
+
+	sort.Slice(markdowns, func(i, j int) bool {
+		return markdowns[i].Name < markdowns[j].Name
+	})
+
+	return markdowns, nil
+}
@@ -0,0 +1,35 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func TestMarkdownMemory(t *testing.T) {
+	repository := memory.NewMarkdownRepository()
+	ctx := context.Background()
+
+	_, err := repository.FindMarkdown(ctx, "summer")
+	assert.Equal(t, models.ErrMarkdownNotFound, err)
+
+	summer := models.Markdown{Name: "summer", Products: []string{"TSHIRT"}, Steps: []models.MarkdownStep{{AfterDays: 60, Percent: 20}}}
+	clearance := models.Markdown{Name: "clearance", Categories: []string{"apparel"}, Steps: []models.MarkdownStep{{Percent: 10}}}
+	for _, markdown := range []models.Markdown{summer, clearance} {
+		_, err = repository.SaveMarkdown(ctx, markdown)
+		require.NoError(t, err)
+	}
+
+	got, err := repository.FindMarkdown(ctx, "summer")
+	require.NoError(t, err)
+	assert.Equal(t, summer, got)
+
+	markdowns, err := repository.ListMarkdowns(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Markdown{clearance, summer}, markdowns)
+}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/patriciabonaldy/cash_register/internal/models"
@@ -37,16 +38,37 @@ func (m *Memory) UpdateBasket(ctx context.Context, basket models.Basket) (models
 	defer m.mux.Unlock()
 
 	m.mux.Lock()
-	_, ok := m.basketStage[basket.Code]
+	stored, ok := m.basketStage[basket.Code]
 	if !ok {
 		return models.Basket{}, models.ErrBasketNotFound
 	}
 
-	m.basketStage[basket.Code] = basket
+	if !followsStates(stored, basket) {
+		return models.Basket{}, models.ErrBasketChanged
+	}
+
+	m.basketStage[basket.Code] = clone(basket)
 
 	return basket, nil
 }
 
+// followsStates reports whether a basket keeps the transitions of the stored one,
+// with at most a new one, so it was read in the state the stored basket is in.
+func followsStates(stored, basket models.Basket) bool {
+	if len(basket.Transitions) < len(stored.Transitions) || len(basket.Transitions) > len(stored.Transitions)+1 {
+		return false
+	}
+
+	for i, t := range stored.Transitions {
+		next := basket.Transitions[i]
+		if next.From != t.From || next.To != t.To || !next.At.Equal(t.At) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // CreateBasket implements the storage.Repository interface.
 func (m *Memory) CreateBasket(ctx context.Context, id string) (models.Basket, error) {
 	defer m.mux.Unlock()
@@ -71,7 +93,7 @@ func (m *Memory) FindBasketByID(ctx context.Context, id string) (models.Basket,
 		return models.Basket{}, models.ErrBasketNotFound
 	}
 
-	return basket, nil
+	return clone(basket), nil
 }
 
 // RemoveBasket implements the storage.Repository interface.
@@ -88,6 +110,23 @@ func (m *Memory) RemoveBasket(ctx context.Context, basketID string) error {
 	return nil
 }
 
+// ListBaskets implements the storage.Repository interface.
+func (m *Memory) ListBaskets(ctx context.Context) ([]models.Basket, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	baskets := make([]models.Basket, 0, len(m.basketStage))
+	for _, basket := range m.basketStage {
+		baskets = append(baskets, clone(basket))
+	}
+
+	sort.Slice(baskets, func(i, j int) bool {
+		return baskets[i].Code < baskets[j].Code
+	})
+
+	return baskets, nil
+}
+
 // RemoveProduct implements the storage.Repository interface.
 func (m *Memory) RemoveProduct(ctx context.Context, basketID string, productCode string) (models.Basket, error) {
 	defer m.mux.Unlock()
@@ -98,15 +137,38 @@ func (m *Memory) RemoveProduct(ctx context.Context, basketID string, productCode
 		return models.Basket{}, models.ErrBasketNotFound
 	}
 
-	item, ok := basket.Items[productCode]
-	if !ok {
+	basket = clone(basket)
+	if _, ok = basket.Items[productCode]; !ok {
 		return models.Basket{}, models.ErrItemNotFound
 	}
 
 	delete(basket.Items, productCode)
 
-	basket.Total -= item.Total
-	m.basketStage[basketID] = basket
+	basket.CalculateTotal()
+	m.basketStage[basketID] = clone(basket)
 
 	return basket, nil
 }
+
+// clone copies the maps and the scans of a basket, so the changes of a basket
+// are only kept when it's updated.
+func clone(basket models.Basket) models.Basket {
+	items := make(map[string]models.Item, len(basket.Items))
+	for code, item := range basket.Items {
+		items[code] = item
+	}
+
+	basket.Items = items
+	if basket.Reservations != nil {
+		reservations := make(map[string]int, len(basket.Reservations))
+		for code, quantity := range basket.Reservations {
+			reservations[code] = quantity
+		}
+
+		basket.Reservations = reservations
+	}
+
+	basket.Scans = append([]models.Scan(nil), basket.Scans...)
+
+	return basket
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,6 +14,10 @@ import (
 )
 
 func TestMemory_FindBasketByID(t *testing.T) {
+	repository := memory.NewRepository()
+	created, err := repository.CreateBasket(context.Background(), "4200f350-4fa5-11ec-a386-1e003b1e5256")
+	require.NoError(t, err)
+
 	testcases := []struct {
 		name          string
 		basketID      string
@@ -24,9 +29,11 @@ func TestMemory_FindBasketByID(t *testing.T) {
 			basketID:      "4200f350-4fa5-11ec-a386-1e003b1e5256",
 			expectedError: nil,
 			want: models.Basket{
-				Code:  "4200f350-4fa5-11ec-a386-1e003b1e5256",
-				Items: make(map[string]models.Item),
-				Total: 0,
+				Code:     "4200f350-4fa5-11ec-a386-1e003b1e5256",
+				Items:    make(map[string]models.Item),
+				Total:    0,
+				State:    models.StateOpen,
+				OpenedAt: created.OpenedAt,
 			},
 		},
 		{
@@ -40,9 +47,6 @@ func TestMemory_FindBasketByID(t *testing.T) {
 		},
 	}
 
-	repository := memory.NewRepository()
-	_, err := repository.CreateBasket(context.Background(), "4200f350-4fa5-11ec-a386-1e003b1e5256")
-	require.NoError(t, err)
 	for _, test := range testcases {
 		t.Run(test.name, func(t *testing.T) {
 			basket, err := repository.FindBasketByID(context.Background(), test.basketID)
@@ -67,13 +71,13 @@ func TestMemory_GetItem(t *testing.T) {
 			Product: models.Product{
 				Code:  "TSHIRT",
 				Name:  "Summer T-Shirt",
-				Price: 20,
+				Price: 2000,
 			},
 			Quantity: 1,
-			Total:    20,
+			Total:    2000,
 		},
 	}
-	basket.Total = 20
+	basket.Total = 2000
 	_, err = repository.UpdateBasket(ctx, basket)
 	require.NoError(t, err)
 
@@ -94,6 +98,30 @@ func TestMemory_RemoveBasket(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestMemory_UpdateBasket_StateChanged(t *testing.T) {
+	repository := memory.NewRepository()
+	ctx := context.Background()
+	basket, err := repository.CreateBasket(ctx, "4200f350-4fa5-11ec-a386-1e003b1e5256")
+	require.NoError(t, err)
+
+	voided, checkedOut := basket, basket
+	require.NoError(t, voided.MoveTo(models.StateVoided, time.Now()))
+	require.NoError(t, checkedOut.MoveTo(models.StateCheckedOut, time.Now()))
+
+	_, err = repository.UpdateBasket(ctx, voided)
+	require.NoError(t, err)
+
+	// the basket was read before it was voided
+	_, err = repository.UpdateBasket(ctx, checkedOut)
+	assert.Equal(t, models.ErrBasketChanged, err)
+	_, err = repository.UpdateBasket(ctx, basket)
+	assert.Equal(t, models.ErrBasketChanged, err)
+
+	// the voided basket can still be stored again
+	_, err = repository.UpdateBasket(ctx, voided)
+	assert.NoError(t, err)
+}
+
 func TestMemory_RemoveProduct(t *testing.T) {
 	repository := memory.NewRepository()
 	ctx := context.Background()
@@ -118,16 +146,36 @@ func TestMemory_RemoveProduct(t *testing.T) {
 			Product: models.Product{
 				Code:  "TSHIRT",
 				Name:  "Summer T-Shirt",
-				Price: 20,
+				Price: 2000,
 			},
 			Quantity: 1,
-			Total:    20,
+			Total:    2000,
 		},
 	}
-	basket.Total = 20
+	basket.Total = 2000
 	_, err = repository.UpdateBasket(ctx, basket)
 	require.NoError(t, err)
 
 	_, err = repository.RemoveProduct(ctx, "4200f350-4fa5-11ec-a386-1e003b1e5256", "TSHIRT")
 	assert.NoError(t, err)
 }
+
+func TestMemory_ListBaskets(t *testing.T) {
+	repository := memory.NewRepository()
+	ctx := context.Background()
+
+	baskets, err := repository.ListBaskets(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, baskets)
+
+	_, err = repository.CreateBasket(ctx, "b")
+	require.NoError(t, err)
+	_, err = repository.CreateBasket(ctx, "a")
+	require.NoError(t, err)
+
+	baskets, err = repository.ListBaskets(ctx)
+	require.NoError(t, err)
+	require.Len(t, baskets, 2)
+	assert.Equal(t, "a", baskets[0].Code)
+	assert.Equal(t, "b", baskets[1].Code)
+}
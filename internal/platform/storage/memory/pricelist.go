@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage"
+)
+
+// PriceListMemory is a memory PriceListRepository implementation.
+type PriceListMemory struct {
+	mux        sync.Mutex
+	priceLists map[string]models.PriceList
+}
+
+// NewPriceListRepository initializes a memory implementation of storage.PriceListRepository.
+func NewPriceListRepository() storage.PriceListRepository {
+	return &PriceListMemory{priceLists: make(map[string]models.PriceList)}
+}
+
+// SavePriceList implements the storage.PriceListRepository interface.
+func (m *PriceListMemory) SavePriceList(ctx context.Context, priceList models.PriceList) (models.PriceList, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	m.priceLists[priceList.Name] = priceList
+
+	return priceList, nil
+}
+
+// FindPriceList implements the storage.PriceListRepository interface.
+func (m *PriceListMemory) FindPriceList(ctx context.Context, name string) (models.PriceList, error) {
+	defer m.mux.Unlock()
+
+	m.mux.Lock()
+	priceList, ok := m.priceLists[name]
+	if !ok {
+		return models.PriceList{}, models.ErrPriceListNotFound
+	}
+
+	return priceList, nil
+}
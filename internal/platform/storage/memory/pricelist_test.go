@@ -0,0 +1,28 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/cash_register/internal/models"
+	"github.com/patriciabonaldy/cash_register/internal/platform/storage/memory"
+)
+
+func TestPriceListMemory(t *testing.T) {
+	repository := memory.NewPriceListRepository()
+	ctx := context.Background()
+
+	_, err := repository.FindPriceList(ctx, "wholesale")
+	assert.Equal(t, models.ErrPriceListNotFound, err)
+
+	priceList := models.PriceList{Name: "wholesale", Prices: map[string]models.Money{"TSHIRT": 1200}}
+	_, err = repository.SavePriceList(ctx, priceList)
+	require.NoError(t, err)
+
+	got, err := repository.FindPriceList(ctx, "wholesale")
+	require.NoError(t, err)
+	assert.Equal(t, priceList, got)
+}
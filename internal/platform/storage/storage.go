@@ -7,6 +7,8 @@ import (
 )
 
 // Repository defines the expected behaviour from a storage.
+// UpdateBasket returns models.ErrBasketChanged when the stored basket changed
+// of state since the basket was read, so a transition is stored only once.
 type Repository interface {
 	FindBasketByID(ctx context.Context, id string) (models.Basket, error)
 	CreateBasket(ctx context.Context, id string) (models.Basket, error)
@@ -14,6 +16,51 @@ type Repository interface {
 	UpdateBasket(ctx context.Context, basketID models.Basket) (models.Basket, error)
 	RemoveProduct(ctx context.Context, basketID, productCode string) (models.Basket, error)
 	RemoveBasket(ctx context.Context, id string) error
+	ListBaskets(ctx context.Context) ([]models.Basket, error)
+}
+
+// CustomerRepository defines the expected behaviour from a storage of loyalty accounts.
+type CustomerRepository interface {
+	CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error)
+	FindCustomerByID(ctx context.Context, id string) (models.Customer, error)
+	UpdateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error)
+	// AddPoints adds delta points to a customer at once, and returns
+	// models.ErrNotEnoughPoints when the points would be negative.
+	AddPoints(ctx context.Context, id string, delta int) (models.Customer, error)
+}
+
+// PriceListRepository defines the expected behaviour from a storage of price lists.
+type PriceListRepository interface {
+	SavePriceList(ctx context.Context, priceList models.PriceList) (models.PriceList, error)
+	FindPriceList(ctx context.Context, name string) (models.PriceList, error)
+}
+
+// MarkdownRepository defines the expected behaviour from a storage of markdown policies.
+type MarkdownRepository interface {
+	SaveMarkdown(ctx context.Context, markdown models.Markdown) (models.Markdown, error)
+	FindMarkdown(ctx context.Context, name string) (models.Markdown, error)
+	ListMarkdowns(ctx context.Context) ([]models.Markdown, error)
+}
+
+// InventoryRepository defines the expected behaviour from a storage of stock.
+// Move applies the deltas of a movement to the stock of its product and records it.
+// Reserve does the same in one step with the check of the stock: unless allowNegative,
+// it returns models.ErrInsufficientStock and changes nothing when the movement
+// takes the available units below zero. SetOnHand records a movement that takes the
+// units on hand to the given ones, its delta is computed with the stock when it's applied.
+// SaveCycleCount returns models.ErrCycleCountPosted when the count was already posted.
+type InventoryRepository interface {
+	FindStock(ctx context.Context, productCode string) (models.Stock, error)
+	Move(ctx context.Context, movement models.StockMovement) (models.StockMovement, error)
+	Reserve(ctx context.Context, movement models.StockMovement, allowNegative bool) (models.StockMovement, error)
+	SetOnHand(ctx context.Context, movement models.StockMovement, onHand int) (models.StockMovement, error)
+	ListMovements(ctx context.Context, productCode string) ([]models.StockMovement, error)
+	SaveCycleCount(ctx context.Context, count models.CycleCount) (models.CycleCount, error)
+	FindCycleCount(ctx context.Context, id string) (models.CycleCount, error)
 }
 
 //go:generate mockery --case=snake --outpkg=storagemocks --output=storagemocks --name=Repository
+//go:generate mockery --case=snake --outpkg=storagemocks --output=storagemocks --name=CustomerRepository
+//go:generate mockery --case=snake --outpkg=storagemocks --output=storagemocks --name=PriceListRepository
+//go:generate mockery --case=snake --outpkg=storagemocks --output=storagemocks --name=InventoryRepository
+//go:generate mockery --case=snake --outpkg=storagemocks --output=storagemocks --name=MarkdownRepository
@@ -77,6 +77,29 @@ func (_m *Repository) GetItem(ctx context.Context, basketID string, productCode
 	return r0, r1
 }
 
+// ListBaskets provides a mock function with given fields: ctx
+func (_m *Repository) ListBaskets(ctx context.Context) ([]models.Basket, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.Basket
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Basket); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Basket)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveBasket provides a mock function with given fields: ctx, id
 func (_m *Repository) RemoveBasket(ctx context.Context, id string) error {
 	ret := _m.Called(ctx, id)